@@ -0,0 +1,268 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf/models"
+)
+
+// pluginStatus describes a loaded plugin in the admin API.
+type pluginStatus struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+	State string `json:"state"`
+}
+
+// outputStatus describes a loaded output plugin in the admin API.
+type outputStatus struct {
+	pluginStatus
+	BufferSize  int    `json:"buffer_size"`
+	BufferLimit int    `json:"buffer_limit"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+// agentStatus lists all loaded plugins in the admin API.
+type agentStatus struct {
+	Inputs      []pluginStatus `json:"inputs"`
+	Processors  []pluginStatus `json:"processors"`
+	Aggregators []pluginStatus `json:"aggregators"`
+	Outputs     []outputStatus `json:"outputs"`
+}
+
+// startAdminServer serves the admin API on the given address until the
+// returned server is closed.
+func (a *Agent) startAdminServer(address string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("starting admin API: %w", err)
+	}
+
+	server := &http.Server{
+		Handler:           a.adminHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("E! [agent] Serving admin API failed: %v", err)
+		}
+	}()
+	log.Printf("I! [agent] Serving admin API on %s", listener.Addr())
+
+	return server, nil
+}
+
+// adminHandler returns the handler of the admin API. The API provides
+//
+//	GET  /plugins                       list all plugins and their state
+//	POST /inputs/<id>/gather            trigger an immediate gather
+//	POST /outputs/<id>/flush            trigger an immediate flush
+//	POST /{inputs,outputs}/<id>/pause   pause the plugin
+//	POST /{inputs,outputs}/<id>/resume  resume the plugin
+//
+// Actions apply to all plugins with the given ID.
+func (a *Agent) adminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/plugins", a.handlePlugins)
+	mux.HandleFunc("/inputs/", a.handleInputAction)
+	mux.HandleFunc("/outputs/", a.handleOutputAction)
+	return mux
+}
+
+func (a *Agent) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	status := agentStatus{
+		Inputs:      make([]pluginStatus, 0, len(a.Config.Inputs)),
+		Processors:  make([]pluginStatus, 0, len(a.Config.Processors)+len(a.Config.AggProcessors)),
+		Aggregators: make([]pluginStatus, 0, len(a.Config.Aggregators)),
+		Outputs:     make([]outputStatus, 0, len(a.Config.Outputs)),
+	}
+	for _, input := range a.Config.Inputs {
+		status.Inputs = append(status.Inputs, pluginStatus{
+			ID:    input.ID(),
+			Name:  input.Config.Name,
+			Alias: input.Config.Alias,
+			State: input.State(),
+		})
+	}
+	for _, processors := range []models.RunningProcessors{a.Config.AggProcessors, a.Config.Processors} {
+		for _, processor := range processors {
+			status.Processors = append(status.Processors, pluginStatus{
+				ID:    processor.ID(),
+				Name:  processor.Config.Name,
+				Alias: processor.Config.Alias,
+				State: "running",
+			})
+		}
+	}
+	for _, aggregator := range a.Config.Aggregators {
+		status.Aggregators = append(status.Aggregators, pluginStatus{
+			ID:    aggregator.ID(),
+			Name:  aggregator.Config.Name,
+			Alias: aggregator.Config.Alias,
+			State: "running",
+		})
+	}
+	for _, output := range a.Config.Outputs {
+		s := outputStatus{
+			pluginStatus: pluginStatus{
+				ID:    output.ID(),
+				Name:  output.Config.Name,
+				Alias: output.Config.Alias,
+				State: output.State(),
+			},
+			BufferSize:  output.BufferLength(),
+			BufferLimit: output.MetricBufferLimit,
+		}
+		if err := output.LastError(); err != nil {
+			s.LastError = err.Error()
+		}
+		status.Outputs = append(status.Outputs, s)
+	}
+
+	writeAdminJSON(w, http.StatusOK, status)
+}
+
+func (a *Agent) handleInputAction(w http.ResponseWriter, r *http.Request) {
+	id, action, ok := parseAdminAction(w, r)
+	if !ok {
+		return
+	}
+
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	if a.iu == nil {
+		writeAdminError(w, http.StatusServiceUnavailable, "agent not running")
+		return
+	}
+	a.iu.Lock()
+	defer a.iu.Unlock()
+
+	var found bool
+	for _, input := range a.iu.inputs {
+		if input.ID() != id {
+			continue
+		}
+		found = true
+
+		switch action {
+		case "gather":
+			if input.Paused() {
+				writeAdminError(w, http.StatusConflict, "input is paused")
+				return
+			}
+			if runner, ok := a.iu.running[input]; ok {
+				runner.Trigger()
+			}
+		case "pause":
+			input.Pause()
+		case "resume":
+			input.Resume()
+		default:
+			writeAdminError(w, http.StatusNotFound, fmt.Sprintf("unknown action %q", action))
+			return
+		}
+	}
+	if !found {
+		writeAdminError(w, http.StatusNotFound, fmt.Sprintf("input %q not found", id))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Agent) handleOutputAction(w http.ResponseWriter, r *http.Request) {
+	id, action, ok := parseAdminAction(w, r)
+	if !ok {
+		return
+	}
+
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	if a.ou == nil {
+		writeAdminError(w, http.StatusServiceUnavailable, "agent not running")
+		return
+	}
+	a.ou.RLock()
+	defer a.ou.RUnlock()
+
+	var found bool
+	for _, output := range a.ou.outputs {
+		if output.ID() != id {
+			continue
+		}
+		found = true
+
+		switch action {
+		case "flush":
+			if output.Paused() {
+				writeAdminError(w, http.StatusConflict, "output is paused")
+				return
+			}
+			if delay := output.RetryDelay(); delay > 0 {
+				msg := fmt.Sprintf("output is waiting to retry, next attempt in %s", delay.Round(time.Second))
+				writeAdminError(w, http.StatusConflict, msg)
+				return
+			}
+			if runner, ok := a.ou.running[output]; ok {
+				runner.Trigger()
+			}
+		case "pause":
+			output.Pause()
+		case "resume":
+			output.Resume()
+		default:
+			writeAdminError(w, http.StatusNotFound, fmt.Sprintf("unknown action %q", action))
+			return
+		}
+	}
+	if !found {
+		writeAdminError(w, http.StatusNotFound, fmt.Sprintf("output %q not found", id))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseAdminAction extracts the plugin ID and the action from a request path
+// of the form "/<type>/<id>/<action>" and writes an error response if the
+// request is invalid.
+func parseAdminAction(w http.ResponseWriter, r *http.Request) (id, action string, ok bool) {
+	if r.Method != http.MethodPost {
+		writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		writeAdminError(w, http.StatusNotFound, "invalid path")
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func writeAdminJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("E! [agent] Writing admin API response failed: %v", err)
+	}
+}
+
+func writeAdminError(w http.ResponseWriter, code int, msg string) {
+	writeAdminJSON(w, code, map[string]string{"error": msg})
+}
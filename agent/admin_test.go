@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAPI(t *testing.T) {
+	a := NewAgent(loadTestConfig(t, `
+[agent]
+  interval = "1s"
+  flush_interval = "1s"
+[[inputs.mem]]
+[[outputs.discard]]
+`))
+	input := a.Config.Inputs[0]
+	output := a.Config.Outputs[0]
+
+	server := httptest.NewServer(a.adminHandler())
+	defer server.Close()
+
+	post := func(path string) int {
+		resp, err := http.Post(server.URL+path, "", nil)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	// Actions require a running agent
+	require.Equal(t, http.StatusServiceUnavailable, post("/inputs/"+input.ID()+"/gather"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- a.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	require.Eventually(t, func() bool {
+		a.reloadLock.Lock()
+		defer a.reloadLock.Unlock()
+		return a.iu != nil && a.ou != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Pause the input and check the listed state
+	require.Equal(t, http.StatusNoContent, post("/inputs/"+input.ID()+"/pause"))
+	require.True(t, input.Paused())
+	require.Equal(t, http.StatusConflict, post("/inputs/"+input.ID()+"/gather"))
+
+	resp, err := http.Get(server.URL + "/plugins")
+	require.NoError(t, err)
+	var status agentStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, []pluginStatus{{ID: input.ID(), Name: "mem", State: "paused"}}, status.Inputs)
+	require.Empty(t, status.Processors)
+	require.Empty(t, status.Aggregators)
+	require.Len(t, status.Outputs, 1)
+	require.Equal(t, output.ID(), status.Outputs[0].ID)
+	require.Equal(t, "running", status.Outputs[0].State)
+	require.Equal(t, output.MetricBufferLimit, status.Outputs[0].BufferLimit)
+
+	require.Equal(t, http.StatusNoContent, post("/inputs/"+input.ID()+"/resume"))
+	require.False(t, input.Paused())
+	require.Equal(t, http.StatusNoContent, post("/inputs/"+input.ID()+"/gather"))
+
+	require.Equal(t, http.StatusNoContent, post("/outputs/"+output.ID()+"/flush"))
+	require.Equal(t, http.StatusNoContent, post("/outputs/"+output.ID()+"/pause"))
+	require.Equal(t, "paused", output.State())
+
+	// Invalid requests
+	require.Equal(t, http.StatusNotFound, post("/inputs/unknown/gather"))
+	require.Equal(t, http.StatusNotFound, post("/outputs/"+output.ID()+"/gather"))
+	require.Equal(t, http.StatusNotFound, post("/inputs/"+input.ID()))
+
+	resp, err = http.Get(server.URL + "/inputs/" + input.ID() + "/pause")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
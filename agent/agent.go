@@ -22,6 +22,11 @@ import (
 // Agent runs a set of plugins.
 type Agent struct {
 	Config *config.Config
+
+	// Units of the running agent used to reload plugins
+	reloadLock sync.Mutex
+	iu         *inputUnit
+	ou         *outputUnit
 }
 
 // NewAgent returns an Agent for the given Config.
@@ -46,6 +51,38 @@ func NewAgent(cfg *config.Config) *Agent {
 type inputUnit struct {
 	dst    chan<- telegraf.Metric
 	inputs []*models.RunningInput
+
+	// Members used to add and remove inputs while running
+	sync.Mutex
+	ctx       context.Context
+	startTime time.Time
+	wg        sync.WaitGroup
+	running   map[*models.RunningInput]*pluginRunner
+	closed    bool
+}
+
+// pluginRunner tracks the goroutine running a single plugin.
+type pluginRunner struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	trigger chan struct{}
+}
+
+func newPluginRunner(cancel context.CancelFunc) *pluginRunner {
+	return &pluginRunner{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// Trigger requests an immediate gather or flush of the plugin. Requests are
+// coalesced if the previous one was not handled yet.
+func (r *pluginRunner) Trigger() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
 }
 
 //  ______     ┌───────────┐     ______
@@ -97,6 +134,19 @@ type aggregatorUnit struct {
 type outputUnit struct {
 	src     <-chan telegraf.Metric
 	outputs []*models.RunningOutput
+
+	// Routing of the metrics to the outputs, each ungrouped output receives
+	// all metrics while each group receives every metric once
+	groupConfigs []*models.OutputGroupConfig
+	ungrouped    []*models.RunningOutput
+	groups       []*models.OutputGroup
+
+	// Members used to add and remove outputs while running
+	sync.RWMutex
+	ctx     context.Context
+	wg      sync.WaitGroup
+	running map[*models.RunningOutput]*pluginRunner
+	closed  bool
 }
 
 // Run starts and runs the Agent until the context is done.
@@ -124,6 +174,22 @@ func (a *Agent) Run(ctx context.Context) error {
 		}
 	}
 
+	if a.Config.Agent.AdminAddress != "" {
+		server, err := a.startAdminServer(a.Config.Agent.AdminAddress)
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+	}
+
+	if a.Config.Agent.MetricsAddress != "" {
+		server, err := startMetricsServer(a.Config.Agent.MetricsAddress)
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+	}
+
 	startTime := time.Now()
 
 	log.Printf("D! [agent] Connecting outputs")
@@ -159,6 +225,17 @@ func (a *Agent) Run(ctx context.Context) error {
 		return err
 	}
 
+	a.reloadLock.Lock()
+	a.iu, a.ou = iu, ou
+	// Plugins removed due to their startup error behavior are not running
+	a.Config.Inputs, a.Config.Outputs = iu.inputs, ou.outputs
+	a.reloadLock.Unlock()
+	defer func() {
+		a.reloadLock.Lock()
+		a.iu, a.ou = nil, nil
+		a.reloadLock.Unlock()
+	}()
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -194,6 +271,17 @@ func (a *Agent) Run(ctx context.Context) error {
 		a.runInputs(ctx, startTime, iu)
 	}()
 
+	if a.Config.Persister != nil && a.Config.Agent.StatefileCheckpointInterval > 0 {
+		interval := time.Duration(a.Config.Agent.StatefileCheckpointInterval)
+		log.Printf("D! [agent] Checkpointing plugin states every %s", interval)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.Config.Persister.Checkpoint(ctx, interval)
+		}()
+	}
+
 	wg.Wait()
 
 	if a.Config.Persister != nil {
@@ -242,6 +330,9 @@ func (a *Agent) initPlugins() error {
 		if err != nil {
 			return fmt.Errorf("could not initialize output %s: %w", output.LogName(), err)
 		}
+		if err := output.OpenBuffer(); err != nil {
+			return fmt.Errorf("could not initialize output %s: %w", output.LogName(), err)
+		}
 	}
 	return nil
 }
@@ -331,27 +422,13 @@ func (a *Agent) startInputs(
 	}
 
 	for _, input := range inputs {
-		if si, ok := input.Input.(telegraf.ServiceInput); ok {
-			// Service input plugins are not normally subject to timestamp
-			// rounding except for when precision is set on the input plugin.
-			//
-			// This only applies to the accumulator passed to Start(), the
-			// Gather() accumulator does apply rounding according to the
-			// precision and interval agent/plugin settings.
-			var interval time.Duration
-			var precision time.Duration
-			if input.Config.Precision != 0 {
-				precision = input.Config.Precision
-			}
-
-			acc := NewAccumulator(input, dst)
-			acc.SetPrecision(getPrecision(precision, interval))
-
-			err := si.Start(acc)
-			if err != nil {
-				stopServiceInputs(unit.inputs)
-				return nil, fmt.Errorf("starting input %s: %w", input.LogName(), err)
+		if err := startServiceInput(dst, input); err != nil {
+			if errors.Is(err, models.ErrPluginSkipped) {
+				log.Printf("W! [agent] Removing input: %v", err)
+				continue
 			}
+			stopServiceInputs(unit.inputs)
+			return nil, err
 		}
 		unit.inputs = append(unit.inputs, input)
 	}
@@ -359,6 +436,33 @@ func (a *Agent) startInputs(
 	return unit, nil
 }
 
+// startServiceInput starts the given input if it is a service input.
+func startServiceInput(dst chan<- telegraf.Metric, input *models.RunningInput) error {
+	if _, ok := input.Input.(telegraf.ServiceInput); !ok {
+		return nil
+	}
+
+	// Service input plugins are not normally subject to timestamp
+	// rounding except for when precision is set on the input plugin.
+	//
+	// This only applies to the accumulator passed to Start(), the
+	// Gather() accumulator does apply rounding according to the
+	// precision and interval agent/plugin settings.
+	var interval time.Duration
+	var precision time.Duration
+	if input.Config.Precision != 0 {
+		precision = input.Config.Precision
+	}
+
+	acc := NewAccumulator(input, dst)
+	acc.SetPrecision(getPrecision(precision, interval))
+
+	if err := input.Start(acc); err != nil {
+		return fmt.Errorf("starting input %s: %w", input.LogName(), err)
+	}
+	return nil
+}
+
 // runInputs starts and triggers the periodic gather for Inputs.
 //
 // When the context is done the timers are stopped and this function returns
@@ -368,58 +472,77 @@ func (a *Agent) runInputs(
 	startTime time.Time,
 	unit *inputUnit,
 ) {
-	var wg sync.WaitGroup
-	tickers := make([]Ticker, 0, len(unit.inputs))
+	unit.Lock()
+	unit.ctx = ctx
+	unit.startTime = startTime
+	unit.running = make(map[*models.RunningInput]*pluginRunner, len(unit.inputs))
 	for _, input := range unit.inputs {
-		// Overwrite agent interval if this plugin has its own.
-		interval := time.Duration(a.Config.Agent.Interval)
-		if input.Config.Interval != 0 {
-			interval = input.Config.Interval
-		}
+		a.runInput(unit, input)
+	}
+	unit.Unlock()
 
-		// Overwrite agent precision if this plugin has its own.
-		precision := time.Duration(a.Config.Agent.Precision)
-		if input.Config.Precision != 0 {
-			precision = input.Config.Precision
-		}
+	<-ctx.Done()
 
-		// Overwrite agent collection_jitter if this plugin has its own.
-		jitter := time.Duration(a.Config.Agent.CollectionJitter)
-		if input.Config.CollectionJitter != 0 {
-			jitter = input.Config.CollectionJitter
-		}
+	unit.Lock()
+	unit.closed = true
+	unit.Unlock()
+	unit.wg.Wait()
 
-		// Overwrite agent collection_offset if this plugin has its own.
-		offset := time.Duration(a.Config.Agent.CollectionOffset)
-		if input.Config.CollectionOffset != 0 {
-			offset = input.Config.CollectionOffset
-		}
+	log.Printf("D! [agent] Stopping service inputs")
+	stopServiceInputs(unit.inputs)
 
-		var ticker Ticker
-		if a.Config.Agent.RoundInterval {
-			ticker = NewAlignedTicker(startTime, interval, jitter, offset)
-		} else {
-			ticker = NewUnalignedTicker(interval, jitter, offset)
-		}
-		tickers = append(tickers, ticker)
+	close(unit.dst)
+	log.Printf("D! [agent] Input channel closed")
+}
 
-		acc := NewAccumulator(input, unit.dst)
-		acc.SetPrecision(getPrecision(precision, interval))
+// runInput starts the periodic gather of the given input in a separate
+// goroutine. The unit must be locked by the caller.
+func (a *Agent) runInput(unit *inputUnit, input *models.RunningInput) {
+	// Overwrite agent interval if this plugin has its own.
+	interval := time.Duration(a.Config.Agent.Interval)
+	if input.Config.Interval != 0 {
+		interval = input.Config.Interval
+	}
 
-		wg.Add(1)
-		go func(input *models.RunningInput) {
-			defer wg.Done()
-			a.gatherLoop(ctx, acc, input, ticker, interval)
-		}(input)
+	// Overwrite agent precision if this plugin has its own.
+	precision := time.Duration(a.Config.Agent.Precision)
+	if input.Config.Precision != 0 {
+		precision = input.Config.Precision
 	}
-	defer stopTickers(tickers)
-	wg.Wait()
 
-	log.Printf("D! [agent] Stopping service inputs")
-	stopServiceInputs(unit.inputs)
+	// Overwrite agent collection_jitter if this plugin has its own.
+	jitter := time.Duration(a.Config.Agent.CollectionJitter)
+	if input.Config.CollectionJitter != 0 {
+		jitter = input.Config.CollectionJitter
+	}
 
-	close(unit.dst)
-	log.Printf("D! [agent] Input channel closed")
+	// Overwrite agent collection_offset if this plugin has its own.
+	offset := time.Duration(a.Config.Agent.CollectionOffset)
+	if input.Config.CollectionOffset != 0 {
+		offset = input.Config.CollectionOffset
+	}
+
+	var ticker Ticker
+	if a.Config.Agent.RoundInterval {
+		ticker = NewAlignedTicker(unit.startTime, interval, jitter, offset)
+	} else {
+		ticker = NewUnalignedTicker(interval, jitter, offset)
+	}
+
+	acc := NewAccumulator(input, unit.dst)
+	acc.SetPrecision(getPrecision(precision, interval))
+
+	ctx, cancel := context.WithCancel(unit.ctx)
+	runner := newPluginRunner(cancel)
+	unit.running[input] = runner
+
+	unit.wg.Add(1)
+	go func() {
+		defer unit.wg.Done()
+		defer close(runner.done)
+		defer ticker.Stop()
+		a.gatherLoop(ctx, acc, input, ticker, runner.trigger, interval)
+	}()
 }
 
 // testStartInputs is a variation of startInputs for use in --test and --once
@@ -436,7 +559,7 @@ func (a *Agent) testStartInputs(
 	}
 
 	for _, input := range inputs {
-		if si, ok := input.Input.(telegraf.ServiceInput); ok {
+		if _, ok := input.Input.(telegraf.ServiceInput); ok {
 			// Service input plugins are not subject to timestamp rounding.
 			// This only applies to the accumulator passed to Start(), the
 			// Gather() accumulator does apply rounding according to the
@@ -444,7 +567,11 @@ func (a *Agent) testStartInputs(
 			acc := NewAccumulator(input, dst)
 			acc.SetPrecision(time.Nanosecond)
 
-			err := si.Start(acc)
+			err := input.Start(acc)
+			if errors.Is(err, models.ErrPluginSkipped) {
+				log.Printf("W! [agent] Removing input %s: %v", input.LogName(), err)
+				continue
+			}
 			if err != nil {
 				log.Printf("E! [agent] Starting input %s: %v", input.LogName(), err)
 			}
@@ -526,9 +653,7 @@ func (a *Agent) testRunInputs(
 // stopServiceInputs stops all service inputs.
 func stopServiceInputs(inputs []*models.RunningInput) {
 	for _, input := range inputs {
-		if si, ok := input.Input.(telegraf.ServiceInput); ok {
-			si.Stop()
-		}
+		input.Stop()
 	}
 }
 
@@ -546,6 +671,7 @@ func (a *Agent) gatherLoop(
 	acc telegraf.Accumulator,
 	input *models.RunningInput,
 	ticker Ticker,
+	trigger <-chan struct{},
 	interval time.Duration,
 ) {
 	defer panicRecover(input)
@@ -557,6 +683,11 @@ func (a *Agent) gatherLoop(
 			if err != nil {
 				acc.AddError(err)
 			}
+		case <-trigger:
+			err := a.gatherOnce(acc, input, ticker, interval)
+			if err != nil {
+				acc.AddError(err)
+			}
 		case <-ctx.Done():
 			return
 		}
@@ -781,9 +912,14 @@ func (a *Agent) startOutputs(
 	outputs []*models.RunningOutput,
 ) (chan<- telegraf.Metric, *outputUnit, error) {
 	src := make(chan telegraf.Metric, 100)
-	unit := &outputUnit{src: src}
+	unit := &outputUnit{src: src, groupConfigs: a.Config.OutputGroups}
 	for _, output := range outputs {
 		err := a.connectOutput(ctx, output)
+		if errors.Is(err, models.ErrPluginSkipped) {
+			log.Printf("W! [agent] Removing output %s: %v", output.LogName(), err)
+			output.Discard()
+			continue
+		}
 		if err != nil {
 			for _, output := range unit.outputs {
 				output.Close()
@@ -793,6 +929,9 @@ func (a *Agent) startOutputs(
 
 		unit.outputs = append(unit.outputs, output)
 	}
+	if len(unit.outputs) == 0 {
+		return nil, nil, errors.New("no outputs could be connected")
+	}
 
 	return src, unit, nil
 }
@@ -800,7 +939,10 @@ func (a *Agent) startOutputs(
 // connectOutputs connects to all outputs.
 func (a *Agent) connectOutput(ctx context.Context, output *models.RunningOutput) error {
 	log.Printf("D! [agent] Attempting connection to [%s]", output.LogName())
-	err := output.Output.Connect()
+	err := output.Connect()
+	if errors.Is(err, models.ErrPluginSkipped) {
+		return err
+	}
 	if err != nil {
 		log.Printf("E! [agent] Failed to connect to [%s], retrying in 15s, "+
 			"error was %q", output.LogName(), err)
@@ -810,7 +952,7 @@ func (a *Agent) connectOutput(ctx context.Context, output *models.RunningOutput)
 			return err
 		}
 
-		err = output.Output.Connect()
+		err = output.Connect()
 		if err != nil {
 			return fmt.Errorf("error connecting to output %q: %w", output.LogName(), err)
 		}
@@ -825,62 +967,117 @@ func (a *Agent) connectOutput(ctx context.Context, output *models.RunningOutput)
 func (a *Agent) runOutputs(
 	unit *outputUnit,
 ) {
-	var wg sync.WaitGroup
-
 	// Start flush loop
-	interval := time.Duration(a.Config.Agent.FlushInterval)
-	jitter := time.Duration(a.Config.Agent.FlushJitter)
-
 	ctx, cancel := context.WithCancel(context.Background())
 
+	unit.Lock()
+	unit.ctx = ctx
+	unit.running = make(map[*models.RunningOutput]*pluginRunner, len(unit.outputs))
 	for _, output := range unit.outputs {
-		interval := interval
-		// Overwrite agent flush_interval if this plugin has its own.
-		if output.Config.FlushInterval != 0 {
-			interval = output.Config.FlushInterval
-		}
-
-		jitter := jitter
-		// Overwrite agent flush_jitter if this plugin has its own.
-		if output.Config.FlushJitter != 0 {
-			jitter = output.Config.FlushJitter
-		}
-
-		wg.Add(1)
-		go func(output *models.RunningOutput) {
-			defer wg.Done()
-
-			ticker := NewRollingTicker(interval, jitter)
-			defer ticker.Stop()
-
-			a.flushLoop(ctx, output, ticker)
-		}(output)
+		a.runOutput(unit, output)
 	}
+	unit.updateRoutes()
+	unit.Unlock()
 
 	for metric := range unit.src {
-		for i, output := range unit.outputs {
-			if i == len(a.Config.Outputs)-1 {
+		unit.RLock()
+		last := len(unit.ungrouped) + len(unit.groups) - 1
+		for i, output := range unit.ungrouped {
+			if i == last {
+				output.AddMetric(metric)
+			} else {
+				output.AddMetric(metric.Copy())
+			}
+		}
+		for i, group := range unit.groups {
+			output := group.Select(metric)
+			if len(unit.ungrouped)+i == last {
 				output.AddMetric(metric)
 			} else {
 				output.AddMetric(metric.Copy())
 			}
 		}
+		unit.RUnlock()
 	}
 
 	log.Println("I! [agent] Hang on, flushing any cached metrics before shutdown")
+	unit.Lock()
+	unit.closed = true
+	unit.Unlock()
 	cancel()
-	wg.Wait()
+	unit.wg.Wait()
 
 	log.Println("I! [agent] Stopping running outputs")
 	stopRunningOutputs(unit.outputs)
 }
 
+// updateRoutes assigns the outputs of the unit to their output groups. Groups
+// without any running member are dropped. The unit must be locked by the
+// caller.
+func (unit *outputUnit) updateRoutes() {
+	grouped := make(map[*models.RunningOutput]bool)
+	unit.groups = make([]*models.OutputGroup, 0, len(unit.groupConfigs))
+	for _, cfg := range unit.groupConfigs {
+		members := make([]*models.RunningOutput, 0, len(cfg.Outputs))
+		for _, alias := range cfg.Outputs {
+			for _, output := range unit.outputs {
+				if output.Config.Alias == alias {
+					members = append(members, output)
+					grouped[output] = true
+				}
+			}
+		}
+		if len(members) > 0 {
+			unit.groups = append(unit.groups, models.NewOutputGroup(cfg, members))
+		}
+	}
+
+	unit.ungrouped = make([]*models.RunningOutput, 0, len(unit.outputs))
+	for _, output := range unit.outputs {
+		if !grouped[output] {
+			unit.ungrouped = append(unit.ungrouped, output)
+		}
+	}
+}
+
+// runOutput starts the flush loop of the given output in a separate
+// goroutine. The unit must be locked by the caller.
+func (a *Agent) runOutput(unit *outputUnit, output *models.RunningOutput) {
+	// Overwrite agent flush_interval if this plugin has its own.
+	interval := time.Duration(a.Config.Agent.FlushInterval)
+	if output.Config.FlushInterval != 0 {
+		interval = output.Config.FlushInterval
+	}
+
+	// Overwrite agent flush_jitter if this plugin has its own.
+	jitter := time.Duration(a.Config.Agent.FlushJitter)
+	if output.Config.FlushJitter != 0 {
+		jitter = output.Config.FlushJitter
+	}
+
+	ctx, cancel := context.WithCancel(unit.ctx)
+	runner := newPluginRunner(cancel)
+	unit.running[output] = runner
+
+	unit.wg.Add(1)
+	go func() {
+		defer unit.wg.Done()
+		defer close(runner.done)
+
+		ticker := NewRollingTicker(interval, jitter)
+		defer ticker.Stop()
+
+		a.flushLoop(ctx, output, ticker, runner.trigger)
+	}()
+}
+
 // flushLoop runs an output's flush function periodically until the context is
 // done.
 func (a *Agent) flushLoop(
 	ctx context.Context,
 	output *models.RunningOutput,
 	ticker Ticker,
+	trigger <-chan struct{},
 ) {
 	logError := func(err error) {
 		if err != nil {
@@ -897,19 +1094,21 @@ func (a *Agent) flushLoop(
 		// Favor shutdown over other methods.
 		select {
 		case <-ctx.Done():
-			logError(a.flushOnce(output, ticker, output.Write))
+			logError(a.flushOnce(output, ticker, output.WriteFinal))
 			return
 		default:
 		}
 
 		select {
 		case <-ctx.Done():
-			logError(a.flushOnce(output, ticker, output.Write))
+			logError(a.flushOnce(output, ticker, output.WriteFinal))
 			return
 		case <-ticker.Elapsed():
 			logError(a.flushOnce(output, ticker, output.Write))
 		case <-flushRequested:
 			logError(a.flushOnce(output, ticker, output.Write))
+		case <-trigger:
+			logError(a.flushOnce(output, ticker, output.Write))
 		case <-output.BatchReady:
 			logError(a.flushBatch(output, output.WriteBatch))
 		}
@@ -1096,6 +1295,7 @@ func (a *Agent) runOnce(ctx context.Context, wait time.Duration) error {
 	if err != nil {
 		return err
 	}
+	a.Config.Outputs = ou.outputs
 
 	var apu []*processorUnit
 	var au *aggregatorUnit
@@ -1194,9 +1394,3 @@ func panicRecover(input *models.RunningInput) {
 			"https://github.com/influxdata/telegraf/issues/new/choose")
 	}
 }
-
-func stopTickers(tickers []Ticker) {
-	for _, ticker := range tickers {
-		ticker.Stop()
-	}
-}
@@ -1,12 +1,15 @@
 package agent
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
 	_ "github.com/influxdata/telegraf/plugins/inputs/all"
 	_ "github.com/influxdata/telegraf/plugins/outputs/all"
 )
@@ -107,6 +110,63 @@ func TestAgent_LoadOutput(t *testing.T) {
 	require.Equal(t, 3, len(a.Config.Outputs))
 }
 
+type failingServiceInput struct{}
+
+func (*failingServiceInput) SampleConfig() string                { return "" }
+func (*failingServiceInput) Gather(_ telegraf.Accumulator) error { return nil }
+func (*failingServiceInput) Start(_ telegraf.Accumulator) error  { return errors.New("failed") }
+func (*failingServiceInput) Stop()                               {}
+
+func TestAgent_StartInputsStartupErrorBehavior(t *testing.T) {
+	newInput := func(behavior string) *models.RunningInput {
+		return models.NewRunningInput(&failingServiceInput{}, &models.InputConfig{
+			Name:                 "failing",
+			StartupErrorBehavior: behavior,
+		})
+	}
+	a := NewAgent(config.NewConfig())
+	dst := make(chan telegraf.Metric)
+
+	_, err := a.startInputs(dst, []*models.RunningInput{newInput("error")})
+	require.ErrorContains(t, err, "failed")
+
+	retry := newInput("retry")
+	unit, err := a.startInputs(dst, []*models.RunningInput{newInput("ignore"), retry})
+	require.NoError(t, err)
+	require.Equal(t, []*models.RunningInput{retry}, unit.inputs)
+}
+
+func TestAgent_OutputGroupRoutes(t *testing.T) {
+	cfg := loadTestConfig(t, `
+[[outputs.discard]]
+  alias = "primary"
+[[outputs.discard]]
+  alias = "secondary"
+[[outputs.discard]]
+  alias = "other"
+
+[output_groups.backend]
+  mode = "failover"
+  outputs = ["secondary", "primary", "missing"]
+`)
+	primary, secondary, other := cfg.Outputs[0], cfg.Outputs[1], cfg.Outputs[2]
+
+	unit := &outputUnit{
+		outputs:      cfg.Outputs,
+		groupConfigs: cfg.OutputGroups,
+	}
+	unit.updateRoutes()
+	require.Equal(t, []*models.RunningOutput{other}, unit.ungrouped)
+	require.Len(t, unit.groups, 1)
+	require.Equal(t, []*models.RunningOutput{secondary, primary}, unit.groups[0].Outputs)
+
+	// Groups without members are dropped
+	unit.outputs = []*models.RunningOutput{other}
+	unit.updateRoutes()
+	require.Equal(t, []*models.RunningOutput{other}, unit.ungrouped)
+	require.Empty(t, unit.groups)
+}
+
 func TestWindow(t *testing.T) {
 	parse := func(s string) time.Time {
 		tm, err := time.Parse(time.RFC3339, s)
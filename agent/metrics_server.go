@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/influxdata/telegraf/plugins/serializers/prometheus"
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+// startMetricsServer serves the internal statistics of the agent in the
+// Prometheus exposition format on the given address until the returned server
+// is closed.
+func startMetricsServer(address string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("starting metrics endpoint: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", handleMetrics)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("E! [agent] Serving metrics endpoint failed: %v", err)
+		}
+	}()
+	log.Printf("I! [agent] Serving internal metrics on http://%s/metrics", listener.Addr())
+
+	return server, nil
+}
+
+// handleMetrics writes the current state of all internal statistics. The
+// OpenMetrics format is used if requested by the client, otherwise the
+// Prometheus text format.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	coll := prometheus.NewCollection(prometheus.FormatConfig{MetricSortOrder: prometheus.SortMetrics})
+	now := time.Now()
+	for _, m := range selfstat.Snapshot() {
+		coll.Add(m, now)
+	}
+
+	format := expfmt.NegotiateIncludingOpenMetrics(r.Header)
+	w.Header().Set("Content-Type", string(format))
+	enc := expfmt.NewEncoder(w, format)
+	for _, mf := range coll.GetProto() {
+		if err := enc.Encode(mf); err != nil {
+			log.Printf("E! [agent] Writing internal metrics failed: %v", err)
+			return
+		}
+	}
+	if closer, ok := enc.(expfmt.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("E! [agent] Writing internal metrics failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	stat := selfstat.Register("metrics_endpoint", "errors", map[string]string{"output": "test"})
+	stat.Set(42)
+
+	server := httptest.NewServer(http.HandlerFunc(handleMetrics))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	require.Contains(t, string(body), `internal_metrics_endpoint_errors{output="test"} 42`)
+
+	// Clients can request the OpenMetrics format
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Contains(t, resp.Header.Get("Content-Type"), "application/openmetrics-text")
+	require.Contains(t, string(body), `internal_metrics_endpoint_errors{output="test"} 42`)
+	require.Contains(t, string(body), "# EOF")
+
+	resp, err = http.Post(server.URL, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
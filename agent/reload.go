@@ -0,0 +1,421 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal/snmp"
+	"github.com/influxdata/telegraf/models"
+)
+
+// ErrRestartRequired is returned by Reload if the configuration changes cannot
+// be applied to the running agent and the agent needs to be restarted.
+var ErrRestartRequired = errors.New("restart required")
+
+// Reload applies the given configuration to the running agent. Plugins are
+// matched by their ID so only inputs and outputs with changed settings are
+// stopped and started while all other plugins keep running, preserving the
+// buffers of unchanged outputs and the state of unchanged inputs.
+//
+// The configuration should be loaded with deferred output buffers to not
+// interfere with the buffers of the running outputs.
+//
+// Changes of the agent settings, global tags, secret-stores, processors or
+// aggregators cannot be applied to the running agent and an error wrapping
+// ErrRestartRequired is returned in this case without touching any plugin.
+func (a *Agent) Reload(ctx context.Context, cfg *config.Config) error {
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	if a.iu == nil || a.ou == nil {
+		return fmt.Errorf("%w: agent not running", ErrRestartRequired)
+	}
+	if reason := restartReason(a.Config, cfg); reason != "" {
+		return fmt.Errorf("%w: %s", ErrRestartRequired, reason)
+	}
+	if len(cfg.Outputs) == 0 {
+		return errors.New("no outputs found, did you provide a valid config file?")
+	}
+
+	keptInputs, addedInputs, removedInputs := diffPlugins(a.Config.Inputs, cfg.Inputs)
+	keptOutputs, addedOutputs, removedOutputs := diffPlugins(a.Config.Outputs, cfg.Outputs)
+	if len(addedInputs) == 0 && len(removedInputs) == 0 && len(addedOutputs) == 0 && len(removedOutputs) == 0 {
+		log.Printf("I! [agent] No plugin changes found")
+		discardOutputs(cfg.Outputs)
+		return nil
+	}
+
+	// Initialize the new plugins first so the running agent is not touched
+	// if any of the plugins fails.
+	for _, input := range addedInputs {
+		// Share the snmp translator setting with plugins that need it.
+		if tp, ok := input.Input.(snmp.TranslatorPlugin); ok {
+			tp.SetTranslator(a.Config.Agent.SnmpTranslator)
+		}
+		if err := input.Init(); err != nil {
+			discardOutputs(cfg.Outputs)
+			return fmt.Errorf("could not initialize input %s: %w", input.LogName(), err)
+		}
+	}
+	for _, output := range addedOutputs {
+		if err := output.Init(); err != nil {
+			discardOutputs(cfg.Outputs)
+			return fmt.Errorf("could not initialize output %s: %w", output.LogName(), err)
+		}
+		// Only open the buffers of new outputs as the buffers of unchanged
+		// outputs are in use by the running instances.
+		if err := output.OpenBuffer(); err != nil {
+			discardOutputs(cfg.Outputs)
+			return fmt.Errorf("could not initialize output %s: %w", output.LogName(), err)
+		}
+	}
+
+	// Restore the states of the new plugins before starting them, the same
+	// way as on startup.
+	if err := a.restoreStates(addedInputs, addedOutputs); err != nil {
+		discardOutputs(cfg.Outputs)
+		return err
+	}
+
+	// The new instances of unchanged outputs are superseded by the running
+	// ones. They only hold a buffer if the configuration was not loaded with
+	// deferred output buffers.
+	for _, output := range cfg.Outputs {
+		if !containsPlugin(addedOutputs, output) {
+			output.Discard()
+		}
+	}
+
+	// Connect the new outputs before removing the old ones to not lose any
+	// metric in between.
+	connected := make([]*models.RunningOutput, 0, len(addedOutputs))
+	for i, output := range addedOutputs {
+		err := a.addOutput(ctx, output)
+		if errors.Is(err, models.ErrPluginSkipped) {
+			log.Printf("W! [agent] Removing output %s: %v", output.LogName(), err)
+			continue
+		}
+		if err != nil {
+			for _, o := range connected {
+				a.removeOutput(o, false)
+			}
+			discardOutputs(addedOutputs[i+1:])
+			return fmt.Errorf("connecting output %s: %w", output.LogName(), err)
+		}
+		connected = append(connected, output)
+	}
+	for _, output := range removedOutputs {
+		a.removeOutput(output, true)
+	}
+
+	for _, input := range removedInputs {
+		a.removeInput(input)
+	}
+	var errs []error
+	started := make([]*models.RunningInput, 0, len(addedInputs))
+	for _, input := range addedInputs {
+		err := a.addInput(input)
+		if errors.Is(err, models.ErrPluginSkipped) {
+			log.Printf("W! [agent] Removing input: %v", err)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		started = append(started, input)
+	}
+
+	a.Config.Inputs = append(keptInputs, started...)
+	a.Config.Outputs = append(keptOutputs, connected...)
+
+	if err := a.updatePersister(removedInputs, started, removedOutputs, connected); err != nil {
+		errs = append(errs, err)
+	}
+
+	log.Printf("I! [agent] Reloaded plugins: %d inputs and %d outputs started, %d inputs and %d outputs stopped",
+		len(started), len(connected), len(removedInputs), len(removedOutputs))
+
+	return errors.Join(errs...)
+}
+
+// identifiable is the common interface of running plugins used for matching.
+type identifiable interface {
+	comparable
+	ID() string
+}
+
+// restartReason returns the reason why the changes between the current and the
+// new configuration require a restart of the agent or an empty string if the
+// changes can be applied to the running agent.
+func restartReason(current, cfg *config.Config) string {
+	if !reflect.DeepEqual(current.Agent, cfg.Agent) {
+		return "agent settings changed"
+	}
+	if !reflect.DeepEqual(current.Tags, cfg.Tags) {
+		return "global tags changed"
+	}
+	if !reflect.DeepEqual(current.SecretStoreHashes, cfg.SecretStoreHashes) {
+		return "secret-stores changed"
+	}
+	if !sameIDs(current.Processors, cfg.Processors) {
+		return "processors changed"
+	}
+	if !sameIDs(current.AggProcessors, cfg.AggProcessors) {
+		return "aggregator processors changed"
+	}
+	if !sameIDs(current.Aggregators, cfg.Aggregators) {
+		return "aggregators changed"
+	}
+	if !reflect.DeepEqual(current.OutputGroups, cfg.OutputGroups) {
+		return "output groups changed"
+	}
+	return ""
+}
+
+// sameIDs checks if both plugin lists contain plugins with the same IDs in
+// the same order.
+func sameIDs[T identifiable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID() != b[i].ID() {
+			return false
+		}
+	}
+	return true
+}
+
+// diffPlugins matches the current and the desired plugins by their ID and
+// returns the current plugins to keep, the desired plugins to add and the
+// current plugins to remove. Plugins with identical IDs are matched in order.
+func diffPlugins[T identifiable](current, desired []T) (kept, added, removed []T) {
+	available := make(map[string][]T, len(current))
+	for _, p := range current {
+		available[p.ID()] = append(available[p.ID()], p)
+	}
+
+	for _, p := range desired {
+		id := p.ID()
+		if candidates := available[id]; len(candidates) > 0 {
+			kept = append(kept, candidates[0])
+			available[id] = candidates[1:]
+			continue
+		}
+		added = append(added, p)
+	}
+
+	for _, p := range current {
+		id := p.ID()
+		if candidates := available[id]; len(candidates) > 0 && candidates[0] == p {
+			removed = append(removed, p)
+			available[id] = candidates[1:]
+		}
+	}
+
+	return kept, added, removed
+}
+
+func containsPlugin[T comparable](plugins []T, plugin T) bool {
+	for _, p := range plugins {
+		if p == plugin {
+			return true
+		}
+	}
+	return false
+}
+
+// discardOutputs releases the resources of outputs not used by the agent.
+func discardOutputs(outputs []*models.RunningOutput) {
+	for _, output := range outputs {
+		output.Discard()
+	}
+}
+
+// addInput starts the given input and adds it to the running input unit.
+func (a *Agent) addInput(input *models.RunningInput) error {
+	unit := a.iu
+
+	// Prevent the unit from closing its destination while starting the input
+	unit.Lock()
+	if unit.closed {
+		unit.Unlock()
+		return fmt.Errorf("adding input %s: agent is stopping", input.LogName())
+	}
+	unit.wg.Add(1)
+	unit.Unlock()
+	defer unit.wg.Done()
+
+	if err := startServiceInput(unit.dst, input); err != nil {
+		return err
+	}
+
+	unit.Lock()
+	defer unit.Unlock()
+	if unit.closed {
+		stopServiceInputs([]*models.RunningInput{input})
+		return fmt.Errorf("adding input %s: agent is stopping", input.LogName())
+	}
+	unit.inputs = append(unit.inputs, input)
+	a.runInput(unit, input)
+
+	log.Printf("D! [agent] Started input %s", input.LogName())
+	return nil
+}
+
+// removeInput stops the given input and removes it from the running input
+// unit.
+func (a *Agent) removeInput(input *models.RunningInput) {
+	unit := a.iu
+
+	unit.Lock()
+	runner, found := unit.running[input]
+	if unit.closed || !found {
+		unit.Unlock()
+		return
+	}
+	delete(unit.running, input)
+	for i, in := range unit.inputs {
+		if in == input {
+			unit.inputs = append(unit.inputs[:i], unit.inputs[i+1:]...)
+			break
+		}
+	}
+	// Prevent the unit from closing its destination while stopping the input
+	unit.wg.Add(1)
+	unit.Unlock()
+	defer unit.wg.Done()
+
+	runner.cancel()
+	<-runner.done
+	stopServiceInputs([]*models.RunningInput{input})
+
+	log.Printf("D! [agent] Stopped input %s", input.LogName())
+}
+
+// addOutput connects the given output and adds it to the running output unit.
+func (a *Agent) addOutput(ctx context.Context, output *models.RunningOutput) error {
+	unit := a.ou
+
+	if err := a.connectOutput(ctx, output); err != nil {
+		output.Discard()
+		return err
+	}
+
+	unit.Lock()
+	defer unit.Unlock()
+	if unit.closed {
+		output.Close()
+		return fmt.Errorf("adding output %s: agent is stopping", output.LogName())
+	}
+	unit.outputs = append(unit.outputs, output)
+	a.runOutput(unit, output)
+	unit.updateRoutes()
+
+	log.Printf("D! [agent] Started output %s", output.LogName())
+	return nil
+}
+
+// removeOutput flushes and closes the given output and removes it from the
+// running output unit. If drop is set, the disk buffer of the output is
+// deleted as it is not used by any configured output anymore.
+func (a *Agent) removeOutput(output *models.RunningOutput, drop bool) {
+	unit := a.ou
+
+	unit.Lock()
+	runner, found := unit.running[output]
+	if unit.closed || !found {
+		unit.Unlock()
+		return
+	}
+	delete(unit.running, output)
+	for i, o := range unit.outputs {
+		if o == output {
+			unit.outputs = append(unit.outputs[:i], unit.outputs[i+1:]...)
+			break
+		}
+	}
+	unit.updateRoutes()
+	unit.wg.Add(1)
+	unit.Unlock()
+	defer unit.wg.Done()
+
+	// Stopping the flush loop writes the buffered metrics one last time
+	runner.cancel()
+	<-runner.done
+	if drop {
+		output.Remove()
+	} else {
+		output.Close()
+	}
+
+	log.Printf("D! [agent] Stopped output %s", output.LogName())
+}
+
+// restoreStates sets the persisted states of the given stateful plugins.
+func (a *Agent) restoreStates(inputs []*models.RunningInput, outputs []*models.RunningOutput) error {
+	if a.Config.Persister == nil {
+		return nil
+	}
+
+	for _, input := range inputs {
+		if plugin, ok := input.Input.(telegraf.StatefulPlugin); ok {
+			if err := a.Config.Persister.Restore(input.ID(), plugin); err != nil {
+				return fmt.Errorf("could not restore state of input %s: %w", input.LogName(), err)
+			}
+		}
+	}
+	for _, output := range outputs {
+		if plugin, ok := output.Output.(telegraf.StatefulPlugin); ok {
+			if err := a.Config.Persister.Restore(output.ID(), plugin); err != nil {
+				return fmt.Errorf("could not restore state of output %s: %w", output.LogName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// updatePersister registers the states of the started plugins and removes the
+// ones of the stopped plugins.
+func (a *Agent) updatePersister(
+	removedInputs, addedInputs []*models.RunningInput,
+	removedOutputs, addedOutputs []*models.RunningOutput,
+) error {
+	if a.Config.Persister == nil {
+		return nil
+	}
+
+	for _, input := range removedInputs {
+		if _, ok := input.Input.(telegraf.StatefulPlugin); ok {
+			a.Config.Persister.Unregister(input.ID())
+		}
+	}
+	for _, output := range removedOutputs {
+		if _, ok := output.Output.(telegraf.StatefulPlugin); ok {
+			a.Config.Persister.Unregister(output.ID())
+		}
+	}
+
+	var errs []error
+	for _, input := range addedInputs {
+		if plugin, ok := input.Input.(telegraf.StatefulPlugin); ok {
+			if err := a.Config.Persister.Register(input.ID(), plugin); err != nil {
+				errs = append(errs, fmt.Errorf("could not register input %s: %w", input.LogName(), err))
+			}
+		}
+	}
+	for _, output := range addedOutputs {
+		if plugin, ok := output.Output.(telegraf.StatefulPlugin); ok {
+			if err := a.Config.Persister.Register(output.ID(), plugin); err != nil {
+				errs = append(errs, fmt.Errorf("could not register output %s: %w", output.LogName(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
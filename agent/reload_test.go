@@ -0,0 +1,332 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+	_ "github.com/influxdata/telegraf/plugins/processors/all"
+	_ "github.com/influxdata/telegraf/plugins/secretstores/directory"
+	"github.com/influxdata/telegraf/selfstat"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func inputByName(inputs []*models.RunningInput, name string) *models.RunningInput {
+	for _, input := range inputs {
+		if input.Config.Name == name {
+			return input
+		}
+	}
+	return nil
+}
+
+func loadTestConfig(t *testing.T, data string) *config.Config {
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(data)))
+	return c
+}
+
+func TestReload(t *testing.T) {
+	a := NewAgent(loadTestConfig(t, `
+[agent]
+  interval = "1s"
+  flush_interval = "1s"
+[[inputs.mem]]
+[[inputs.swap]]
+[[outputs.discard]]
+[[outputs.discard]]
+  alias = "old"
+`))
+	mem := inputByName(a.Config.Inputs, "mem")
+	swap := inputByName(a.Config.Inputs, "swap")
+	discard := a.Config.Outputs[0]
+
+	// Reloading requires a running agent
+	require.ErrorIs(t, a.Reload(context.Background(), a.Config), ErrRestartRequired)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- a.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	require.Eventually(t, func() bool {
+		a.reloadLock.Lock()
+		defer a.reloadLock.Unlock()
+		return a.iu != nil && a.ou != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cfg := loadTestConfig(t, `
+[agent]
+  interval = "1s"
+  flush_interval = "1s"
+[[inputs.mem]]
+[[inputs.swap]]
+  name_suffix = "_new"
+[[outputs.discard]]
+[[outputs.discard]]
+  alias = "new"
+`)
+	require.NoError(t, a.Reload(ctx, cfg))
+
+	// Unchanged plugins must keep running, changed ones must be replaced
+	require.Len(t, a.Config.Inputs, 2)
+	require.Same(t, mem, inputByName(a.Config.Inputs, "mem"))
+	require.NotSame(t, swap, inputByName(a.Config.Inputs, "swap"))
+	require.Same(t, inputByName(cfg.Inputs, "swap"), inputByName(a.Config.Inputs, "swap"))
+	require.Len(t, a.Config.Outputs, 2)
+	require.Same(t, discard, a.Config.Outputs[0])
+	require.Equal(t, "new", a.Config.Outputs[1].Config.Alias)
+
+	a.iu.Lock()
+	require.Len(t, a.iu.inputs, 2)
+	require.Contains(t, a.iu.running, mem)
+	require.NotContains(t, a.iu.running, swap)
+	a.iu.Unlock()
+
+	a.ou.RLock()
+	require.Len(t, a.ou.outputs, 2)
+	require.Contains(t, a.ou.running, discard)
+	a.ou.RUnlock()
+
+	// Changes of the agent settings require a restart
+	cfg = loadTestConfig(t, `
+[agent]
+  interval = "2s"
+  flush_interval = "1s"
+[[inputs.mem]]
+[[outputs.discard]]
+`)
+	require.ErrorIs(t, a.Reload(ctx, cfg), ErrRestartRequired)
+	require.Len(t, a.Config.Inputs, 2)
+}
+
+func TestReloadKeepsOutputBuffers(t *testing.T) {
+	base := `
+[agent]
+  interval = "1s"
+  flush_interval = "1h"
+[[inputs.mem]]
+[[outputs.discard]]
+  alias = "reload_buffer"
+`
+	a := NewAgent(loadTestConfig(t, base))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- a.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	require.Eventually(t, func() bool {
+		a.reloadLock.Lock()
+		defer a.reloadLock.Unlock()
+		return a.iu != nil && a.ou != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	output := a.Config.Outputs[0]
+	for i := 0; i < 3; i++ {
+		output.AddMetric(testutil.TestMetric(i))
+	}
+	size := selfstat.Register("write", "buffer_size", map[string]string{"output": "discard", "alias": "reload_buffer"})
+	require.EqualValues(t, 3, size.Get())
+
+	// Loading the configuration must not touch the buffer of the running
+	// output and the unchanged output must keep its buffer
+	cfg := config.NewConfig()
+	cfg.DeferOutputBuffers = true
+	require.NoError(t, cfg.LoadConfigData([]byte(base+"[[inputs.swap]]\n")))
+	require.EqualValues(t, 3, size.Get())
+
+	require.NoError(t, a.Reload(ctx, cfg))
+	require.Same(t, output, a.Config.Outputs[0])
+	require.Equal(t, 3, output.BufferLength())
+	require.EqualValues(t, 3, size.Get())
+}
+
+func TestReloadRemovesDiskBuffers(t *testing.T) {
+	dir := t.TempDir()
+	a := NewAgent(loadTestConfig(t, `
+[agent]
+  interval = "1s"
+  flush_interval = "1h"
+[[inputs.mem]]
+[[outputs.discard]]
+  alias = "old"
+  buffer_strategy = "disk"
+  buffer_directory = '`+dir+`'
+`))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- a.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	require.Eventually(t, func() bool {
+		a.reloadLock.Lock()
+		defer a.reloadLock.Unlock()
+		return a.iu != nil && a.ou != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	path := filepath.Join(dir, a.Config.Outputs[0].Config.ID)
+	require.DirExists(t, path)
+
+	// The write-ahead log of the removed output must be deleted
+	cfg := config.NewConfig()
+	cfg.DeferOutputBuffers = true
+	require.NoError(t, cfg.LoadConfigData([]byte(`
+[agent]
+  interval = "1s"
+  flush_interval = "1h"
+[[inputs.mem]]
+[[outputs.discard]]
+  alias = "new"
+  buffer_strategy = "disk"
+  buffer_directory = '`+dir+`'
+`)))
+	require.NoError(t, a.Reload(ctx, cfg))
+	require.NoDirExists(t, path)
+	require.DirExists(t, filepath.Join(dir, a.Config.Outputs[0].Config.ID))
+}
+
+func TestDiffPlugins(t *testing.T) {
+	// Use a single plugin type as the order of different plugins is random
+	c := loadTestConfig(t, `
+[[inputs.mem]]
+[[inputs.mem]]
+[[inputs.mem]]
+  alias = "removed"
+`)
+	desired := loadTestConfig(t, `
+[[inputs.mem]]
+[[inputs.mem]]
+  alias = "added"
+`)
+
+	kept, added, removed := diffPlugins(c.Inputs, desired.Inputs)
+	require.Len(t, kept, 1)
+	require.Same(t, c.Inputs[0], kept[0])
+	require.Len(t, added, 1)
+	require.Same(t, desired.Inputs[1], added[0])
+	require.Len(t, removed, 2)
+	require.Same(t, c.Inputs[1], removed[0])
+	require.Same(t, c.Inputs[2], removed[1])
+}
+
+func TestRestartReason(t *testing.T) {
+	base := `
+[agent]
+  interval = "10s"
+[[inputs.mem]]
+[[processors.rename]]
+[[outputs.discard]]
+`
+
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name:   "unchanged",
+			config: base,
+		},
+		{
+			name: "changed input",
+			config: `
+[agent]
+  interval = "10s"
+[[inputs.swap]]
+[[processors.rename]]
+[[outputs.discard]]
+`,
+		},
+		{
+			name: "changed agent",
+			config: `
+[agent]
+  interval = "20s"
+[[inputs.mem]]
+[[processors.rename]]
+[[outputs.discard]]
+`,
+			expected: "agent settings changed",
+		},
+		{
+			name: "changed global tags",
+			config: `
+[global_tags]
+  dc = "us-east-1"
+[agent]
+  interval = "10s"
+[[inputs.mem]]
+[[processors.rename]]
+[[outputs.discard]]
+`,
+			expected: "global tags changed",
+		},
+		{
+			name: "changed processor",
+			config: `
+[agent]
+  interval = "10s"
+[[inputs.mem]]
+[[processors.rename]]
+  order = 1
+[[outputs.discard]]
+`,
+			expected: "processors changed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := loadTestConfig(t, base)
+			cfg := loadTestConfig(t, tt.config)
+			require.Equal(t, tt.expected, restartReason(current, cfg))
+		})
+	}
+}
+
+func TestRestartReasonSecretStores(t *testing.T) {
+	current := loadTestConfig(t, `
+[[secretstores.directory]]
+  id = "store"
+  path = "."
+[[outputs.discard]]
+`)
+
+	// Changing the settings of a secret-store must be detected even if the
+	// ID stays the same
+	cfg := loadTestConfig(t, `
+[[secretstores.directory]]
+  id = "store"
+  path = "."
+  trim_whitespace = true
+[[outputs.discard]]
+`)
+	require.Equal(t, "secret-stores changed", restartReason(current, cfg))
+
+	cfg = loadTestConfig(t, `
+[[secretstores.directory]]
+  path = "."
+  id = "store"
+[[outputs.discard]]
+`)
+	require.Empty(t, restartReason(current, cfg))
+}
@@ -0,0 +1,210 @@
+// Command handling for configuration "config" command
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/logger"
+)
+
+func getConfigCommands(pluginFilterFlags []cli.Flag, outputBuffer io.Writer) []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "config",
+			Usage: "commands for generating, checking and migrating configurations",
+			Flags: pluginFilterFlags,
+			Action: func(cCtx *cli.Context) error {
+				// The sub_Filters are populated when the filter flags are set after the subcommand config
+				// e.g. telegraf config --section-filter inputs
+				filters := processFilterFlags(cCtx)
+
+				printSampleConfig(outputBuffer, filters)
+				return nil
+			},
+			Subcommands: []*cli.Command{
+				{
+					Name:  "check",
+					Usage: "check the configuration(s) without running any plugin",
+					Description: `
+The 'check' command loads the configuration files specified via the
+'--config' or '--config-directory' flags or the given arguments and
+initializes all plugins without starting or connecting them. All
+problems found, like unknown options, invalid values, deprecated
+settings or invalid filters, are reported with their file and line.
+The command fails if any error is found.
+
+To check the default configuration files, run
+
+> telegraf config check
+
+To check a specific file and get a JSON report, e.g. in CI pipelines, run
+
+> telegraf --config /etc/telegraf/telegraf.conf config check --format json
+`,
+					ArgsUsage: "[config file]...[config file]",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "format",
+							Usage: "format of the report, either 'text' or 'json'",
+							Value: "text",
+						},
+						&cli.BoolFlag{
+							Name:  "resolve-secrets",
+							Usage: "resolve all secrets using the configured secret-stores",
+						},
+					},
+					Action: func(cCtx *cli.Context) error {
+						format := cCtx.String("format")
+						if format != "text" && format != "json" {
+							return fmt.Errorf("invalid report format %q", format)
+						}
+
+						if err := logger.SetupLogging(logger.LogConfig{Debug: cCtx.Bool("debug")}); err != nil {
+							return err
+						}
+
+						files, err := getConfigFiles(cCtx)
+						if err != nil {
+							return err
+						}
+						report := config.CheckConfigFiles(files, cCtx.Bool("resolve-secrets"))
+
+						switch format {
+						case "json":
+							buf, err := json.MarshalIndent(report, "", "  ")
+							if err != nil {
+								return err
+							}
+							fmt.Fprintln(outputBuffer, string(buf))
+						default:
+							for _, issue := range report.Issues {
+								fmt.Fprintln(outputBuffer, issue.String())
+							}
+							fmt.Fprintf(outputBuffer, "%d file(s) checked, %d error(s), %d warning(s)\n",
+								len(report.Files), report.Errors, report.Warnings)
+						}
+
+						if report.Failed() {
+							return errors.New("configuration check failed")
+						}
+						return nil
+					},
+				},
+				{
+					Name:  "migrate",
+					Usage: "migrate deprecated plugins and options of the configuration(s)",
+					Description: `
+The 'migrate' command reads the configuration files specified via the
+'--config' or '--config-directory' flags or the given arguments and
+rewrites deprecated plugins and options into their replacements where
+a mapping exists. Comments and unchanged parts of the configuration
+are kept. Deprecated settings which cannot be migrated automatically
+are reported.
+
+The migrated configuration is written to a file next to the original
+one with the '.migrated' suffix. The original files are not modified.
+If no migration applies, no file is written.
+
+To migrate the default configuration files, run
+
+> telegraf config migrate
+
+To migrate a specific file, run
+
+> telegraf --config /etc/telegraf/telegraf.conf config migrate
+`,
+					ArgsUsage: "[config file]...[config file]",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "overwrite existing migration files",
+						},
+					},
+					Action: func(cCtx *cli.Context) error {
+						if err := logger.SetupLogging(logger.LogConfig{Debug: cCtx.Bool("debug")}); err != nil {
+							return err
+						}
+
+						files, err := getConfigFiles(cCtx)
+						if err != nil {
+							return err
+						}
+						for _, fn := range files {
+							if err := migrateConfigFile(fn, cCtx.Bool("force")); err != nil {
+								return err
+							}
+						}
+						return nil
+					},
+				},
+			},
+		},
+	}
+}
+
+// getConfigFiles returns the configuration files given via the global flags
+// and the arguments or the default configuration files if none are given.
+func getConfigFiles(cCtx *cli.Context) ([]string, error) {
+	files := append(cCtx.StringSlice("config"), cCtx.Args().Slice()...)
+	for _, dir := range cCtx.StringSlice("config-directory") {
+		dirFiles, err := config.WalkDirectory(dir)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, dirFiles...)
+	}
+
+	if len(files) == 0 {
+		return config.GetDefaultConfigPath()
+	}
+	return files, nil
+}
+
+// migrateConfigFile migrates the given configuration file and writes the
+// result next to the original file.
+func migrateConfigFile(fn string, force bool) error {
+	if u, err := url.Parse(fn); err == nil && u.Scheme != "" && u.Host != "" {
+		log.Printf("W! Skipping remote configuration %q", fn)
+		return nil
+	}
+
+	log.Printf("D! Migrating %q...", fn)
+	data, err := config.LoadConfigFile(fn)
+	if err != nil {
+		return fmt.Errorf("reading %q failed: %w", fn, err)
+	}
+
+	migrated, applied, err := config.MigrateConfigData(data)
+	if err != nil {
+		return fmt.Errorf("migrating %q failed: %w", fn, err)
+	}
+	if len(applied) == 0 {
+		log.Printf("I! No migration applied for %q", fn)
+		return nil
+	}
+	for _, msg := range applied {
+		log.Printf("I! %s: %s", fn, msg)
+	}
+
+	outfn := fn + ".migrated"
+	if !force {
+		if _, err := os.Stat(outfn); !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("output file %q already exists, use '--force' to overwrite", outfn)
+		}
+	}
+
+	log.Printf("I! %d migration(s) applied for %q, writing result to %q", len(applied), fn, outfn)
+	if err := os.WriteFile(outfn, migrated, 0640); err != nil {
+		return fmt.Errorf("writing %q failed: %w", outfn, err)
+	}
+	return nil
+}
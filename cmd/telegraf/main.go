@@ -340,29 +340,15 @@ func runApp(args []string, outputBuffer io.Writer, pprof Server, c TelegrafConfi
 				// !!!
 			}, extraFlags...),
 		Action: action,
-		Commands: append([]*cli.Command{
-			{
-				Name:  "config",
-				Usage: "print out full sample configuration to stdout",
-				Flags: pluginFilterFlags,
-				Action: func(cCtx *cli.Context) error {
-					// The sub_Filters are populated when the filter flags are set after the subcommand config
-					// e.g. telegraf config --section-filter inputs
-					filters := processFilterFlags(cCtx)
-
-					printSampleConfig(outputBuffer, filters)
-					return nil
-				},
-			},
-			{
+		Commands: append(
+			append(getConfigCommands(pluginFilterFlags, outputBuffer), &cli.Command{
 				Name:  "version",
 				Usage: "print current version to stdout",
 				Action: func(cCtx *cli.Context) error {
 					outputBuffer.Write([]byte(fmt.Sprintf("%s\n", internal.FormatFullVersion())))
 					return nil
 				},
-			},
-		},
+			}),
 			getSecretStoreCommands(m)...,
 		),
 	}
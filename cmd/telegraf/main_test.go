@@ -2,10 +2,12 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -418,6 +420,62 @@ func TestCommandConfig(t *testing.T) {
 	}
 }
 
+func TestCommandConfigMigrate(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "telegraf.conf")
+	require.NoError(t, os.WriteFile(fn, []byte("[[inputs.io]]\n  devices = [\"sda\"]\n"), 0600))
+
+	buf := new(bytes.Buffer)
+	args := os.Args[0:1]
+	args = append(args, "--config", fn, "config", "migrate")
+	err := runApp(args, buf, NewMockServer(), NewMockConfig(buf), NewMockTelegraf())
+	require.NoError(t, err)
+
+	migrated, err := os.ReadFile(fn + ".migrated")
+	require.NoError(t, err)
+	require.Equal(t, "[[inputs.diskio]]\n  devices = [\"sda\"]\n", string(migrated))
+
+	// Do not overwrite existing migrations without force
+	err = runApp(args, buf, NewMockServer(), NewMockConfig(buf), NewMockTelegraf())
+	require.ErrorContains(t, err, "already exists")
+
+	args = append(args, "--force")
+	err = runApp(args, buf, NewMockServer(), NewMockConfig(buf), NewMockTelegraf())
+	require.NoError(t, err)
+}
+
+func TestCommandConfigCheck(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "telegraf.conf")
+	require.NoError(t, os.WriteFile(fn, []byte("[[inputs.cpu]]\n  unknown = true\n"), 0600))
+
+	buf := new(bytes.Buffer)
+	args := os.Args[0:1]
+	args = append(args, "--config", fn, "config", "check", "--format", "json")
+	err := runApp(args, buf, NewMockServer(), NewMockConfig(buf), NewMockTelegraf())
+	require.ErrorContains(t, err, "configuration check failed")
+
+	var report config.CheckReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	require.Equal(t, []string{fn}, report.Files)
+	require.Equal(t, 1, report.Errors)
+	require.Equal(t, []config.CheckIssue{
+		{
+			File:     fn,
+			Line:     2,
+			Plugin:   "inputs.cpu",
+			Severity: config.CheckError,
+			Kind:     "unknown_option",
+			Message:  `unknown option "unknown"`,
+		},
+	}, report.Issues)
+
+	require.NoError(t, os.WriteFile(fn, []byte("[[inputs.cpu]]\n"), 0600))
+	buf.Reset()
+	args = append(os.Args[0:1], "--config", fn, "config", "check")
+	err = runApp(args, buf, NewMockServer(), NewMockConfig(buf), NewMockTelegraf())
+	require.NoError(t, err)
+	require.Equal(t, "1 file(s) checked, 0 error(s), 0 warning(s)\n", buf.String())
+}
+
 func TestCommandVersion(t *testing.T) {
 	tests := []struct {
 		Version        string
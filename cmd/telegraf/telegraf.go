@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -71,6 +72,10 @@ type Telegraf struct {
 	configFiles        []string
 	secretstoreFilters []string
 
+	// The running agent used for reloading plugins
+	agent     *agent.Agent
+	agentLock sync.Mutex
+
 	GlobalFlags
 	WindowFlags
 }
@@ -132,33 +137,32 @@ func (t *Telegraf) reloadLoop() error {
 		signals := make(chan os.Signal, 1)
 		signal.Notify(signals, os.Interrupt, syscall.SIGHUP,
 			syscall.SIGTERM, syscall.SIGINT)
-		if t.watchConfig != "" {
-			for _, fConfig := range t.configFiles {
-				if _, err := os.Stat(fConfig); err == nil {
-					go t.watchLocalConfig(signals, fConfig)
-				} else {
-					log.Printf("W! Cannot watch config %s: %s", fConfig, err)
-				}
-			}
-		}
+		t.watchConfigFiles(ctx, signals)
 		go func() {
-			select {
-			case sig := <-signals:
-				if sig == syscall.SIGHUP {
-					log.Printf("I! Reloading Telegraf config")
-					<-reload
-					reload <- true
+			for {
+				select {
+				case sig := <-signals:
+					if sig == syscall.SIGHUP {
+						log.Printf("I! Reloading Telegraf config")
+						if t.reloadPlugins(ctx) {
+							continue
+						}
+						<-reload
+						reload <- true
+					}
+					cancel()
+				case err := <-t.pprofErr:
+					log.Printf("E! pprof server failed: %v", err)
+					cancel()
+				case <-stop:
+					cancel()
 				}
-				cancel()
-			case err := <-t.pprofErr:
-				log.Printf("E! pprof server failed: %v", err)
-				cancel()
-			case <-stop:
-				cancel()
+				return
 			}
 		}()
 
 		err := t.runAgent(ctx, cfg, reloadConfig)
+		cancel()
 		if err != nil && !errors.Is(err, context.Canceled) {
 			return fmt.Errorf("[telegraf] Error running agent: %w", err)
 		}
@@ -168,8 +172,74 @@ func (t *Telegraf) reloadLoop() error {
 	return nil
 }
 
-func (t *Telegraf) watchLocalConfig(signals chan os.Signal, fConfig string) {
+// reloadPlugins applies the current configuration to the running agent and
+// returns false if the agent needs to be restarted instead.
+func (t *Telegraf) reloadPlugins(ctx context.Context) bool {
+	t.agentLock.Lock()
+	ag := t.agent
+	t.agentLock.Unlock()
+	if ag == nil {
+		return false
+	}
+
+	c, err := t.loadConfiguration()
+	if err != nil {
+		log.Printf("E! Loading config failed: %v", err)
+		return false
+	}
+
+	if err := ag.Reload(ctx, c); err != nil {
+		if errors.Is(err, agent.ErrRestartRequired) {
+			log.Printf("I! Restarting agent: %v", err)
+		} else {
+			log.Printf("E! Reloading plugins failed, restarting agent: %v", err)
+		}
+		return false
+	}
+	return true
+}
+
+// watchConfigFiles watches all config files for changes until the context
+// is done.
+func (t *Telegraf) watchConfigFiles(ctx context.Context, signals chan os.Signal) {
+	if t.watchConfig == "" {
+		return
+	}
+
+	for _, fConfig := range t.configFiles {
+		if _, err := os.Stat(fConfig); err == nil {
+			go t.watchLocalConfig(ctx, signals, fConfig)
+		} else {
+			log.Printf("W! Cannot watch config %s: %s", fConfig, err)
+		}
+	}
+}
+
+// watchLocalConfig sends a SIGHUP for every change of the given config file
+// until the context is done.
+func (t *Telegraf) watchLocalConfig(ctx context.Context, signals chan os.Signal, fConfig string) {
+	for t.watchLocalConfigOnce(ctx, fConfig) {
+		select {
+		case signals <- syscall.SIGHUP:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// watchLocalConfigOnce waits for a change of the given config file and returns
+// false if watching failed or the context is done.
+func (t *Telegraf) watchLocalConfigOnce(ctx context.Context, fConfig string) bool {
 	var mytomb tomb.Tomb
+	defer mytomb.Done()
+	go func() {
+		select {
+		case <-ctx.Done():
+			mytomb.Kill(nil)
+		case <-mytomb.Dead():
+		}
+	}()
+
 	var watcher watch.FileWatcher
 	if t.watchConfig == "poll" {
 		watcher = watch.NewPollingFileWatcher(fConfig)
@@ -179,7 +249,7 @@ func (t *Telegraf) watchLocalConfig(signals chan os.Signal, fConfig string) {
 	changes, err := watcher.ChangeEvents(&mytomb, 0)
 	if err != nil {
 		log.Printf("E! Error watching config: %s\n", err)
-		return
+		return false
 	}
 	log.Println("I! Config watcher started")
 	select {
@@ -193,8 +263,10 @@ func (t *Telegraf) watchLocalConfig(signals chan os.Signal, fConfig string) {
 		} else {
 			log.Println("W! Config file deleted")
 			if err := watcher.BlockUntilExists(&mytomb); err != nil {
-				log.Printf("E! Cannot watch for config: %s\n", err.Error())
-				return
+				if ctx.Err() == nil {
+					log.Printf("E! Cannot watch for config: %s\n", err.Error())
+				}
+				return false
 			}
 			log.Println("I! Config file appeared")
 		}
@@ -202,16 +274,18 @@ func (t *Telegraf) watchLocalConfig(signals chan os.Signal, fConfig string) {
 		log.Println("I! Config file truncated")
 	case <-mytomb.Dying():
 		log.Println("I! Config watcher ended")
-		return
+		return false
 	}
-	mytomb.Done()
-	signals <- syscall.SIGHUP
+	return true
 }
 
 func (t *Telegraf) loadConfiguration() (*config.Config, error) {
 	// If no other options are specified, load the config file and run.
 	c := config.NewConfig()
 	c.Agent.Quiet = t.quiet
+	// The agent opens the buffers of the outputs it starts, so reloading
+	// does not touch the buffers of the running outputs.
+	c.DeferOutputBuffers = true
 	c.OutputFilters = t.outputFilters
 	c.InputFilters = t.inputFilters
 	c.SecretStoreFilters = t.secretstoreFilters
@@ -269,6 +343,7 @@ func (t *Telegraf) runAgent(ctx context.Context, c *config.Config, reloadConfig
 		Debug:               telegraf.Debug,
 		Quiet:               c.Agent.Quiet || t.quiet,
 		LogTarget:           c.Agent.LogTarget,
+		LogFormat:           c.Agent.LogFormat,
 		Logfile:             c.Agent.Logfile,
 		RotationInterval:    c.Agent.LogfileRotationInterval,
 		RotationMaxSize:     c.Agent.LogfileRotationMaxSize,
@@ -367,5 +442,14 @@ func (t *Telegraf) runAgent(ctx context.Context, c *config.Config, reloadConfig
 		}
 	}
 
+	t.agentLock.Lock()
+	t.agent = ag
+	t.agentLock.Unlock()
+	defer func() {
+		t.agentLock.Lock()
+		t.agent = nil
+		t.agentLock.Unlock()
+	}()
+
 	return ag.Run(ctx)
 }
@@ -0,0 +1,423 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/toml"
+	"github.com/influxdata/toml/ast"
+
+	"github.com/influxdata/telegraf/models"
+)
+
+// Severities of check issues
+const (
+	CheckError   = "error"
+	CheckWarning = "warning"
+)
+
+// CheckIssue describes a single problem found when checking a configuration.
+type CheckIssue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Plugin   string `json:"plugin,omitempty"`
+	Severity string `json:"severity"`
+	// Kind is one of "load", "parse", "unknown_option", "type", "filter",
+	// "deprecated", "init", "secret" or "config" for all other errors.
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+func (i CheckIssue) String() string {
+	msg := i.Message
+	if i.Plugin != "" {
+		msg = i.Plugin + ": " + msg
+	}
+
+	// Issues spanning multiple files have no position
+	if i.File == "" {
+		return fmt.Sprintf("%s: %s", i.Severity, msg)
+	}
+	pos := i.File
+	if i.Line > 0 {
+		pos += ":" + strconv.Itoa(i.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s", pos, i.Severity, msg)
+}
+
+// CheckReport holds the result of checking a set of configuration files.
+type CheckReport struct {
+	Files    []string     `json:"files"`
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+	Issues   []CheckIssue `json:"issues"`
+}
+
+// Failed returns true if the report contains any error.
+func (r *CheckReport) Failed() bool {
+	return r.Errors > 0
+}
+
+func (r *CheckReport) add(issue CheckIssue) {
+	switch issue.Severity {
+	case CheckError:
+		r.Errors++
+	case CheckWarning:
+		r.Warnings++
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+// checkedPlugin is a plugin successfully created during the check.
+type checkedPlugin struct {
+	file   string
+	id     string
+	table  *ast.Table
+	plugin interface{}
+}
+
+type checker struct {
+	cfg     *Config
+	report  *CheckReport
+	plugins []checkedPlugin
+}
+
+// CheckConfigFiles loads the given configuration files and initializes all
+// plugins without starting or connecting them. Instead of stopping at the
+// first problem, all issues found are collected in the returned report.
+// Secrets are only resolved if requested, otherwise only their references
+// to secret-stores are checked.
+func CheckConfigFiles(files []string, resolveSecrets bool) *CheckReport {
+	// Do not leak the secrets created during the check into the secrets
+	// linked when loading the configuration
+	defer func(n int) {
+		unlinkedSecrets = unlinkedSecrets[:n]
+	}(len(unlinkedSecrets))
+
+	c := &checker{
+		cfg: NewConfig(),
+		report: &CheckReport{
+			Files:  files,
+			Issues: make([]CheckIssue, 0),
+		},
+	}
+	// Do not touch the buffer directories of the outputs
+	c.cfg.DeferOutputBuffers = true
+	for _, fn := range files {
+		c.checkFile(fn)
+	}
+	c.checkSecrets(resolveSecrets)
+	if err := c.cfg.checkOutputGroups(); err != nil {
+		c.addIssue("", 0, "", CheckError, "config", err.Error())
+	}
+
+	// Order the issues by their position in the configuration
+	order := make(map[string]int, len(files))
+	for i, fn := range files {
+		order[fn] = i
+	}
+	sort.SliceStable(c.report.Issues, func(i, j int) bool {
+		a, b := c.report.Issues[i], c.report.Issues[j]
+		if a.File != b.File {
+			return order[a.File] < order[b.File]
+		}
+		return a.Line < b.Line
+	})
+
+	return c.report
+}
+
+func (c *checker) checkFile(fn string) {
+	data, err := LoadConfigFile(fn)
+	if err != nil {
+		c.addError(fn, nil, "", "load", err)
+		return
+	}
+
+	tbl, err := parseConfig(data)
+	if err != nil {
+		c.addError(fn, nil, "", "parse", err)
+		return
+	}
+
+	// Check the non-plugin tables first as the plugins depend on them
+	for _, name := range []string{"agent", "global_tags", "tags"} {
+		val, ok := tbl.Fields[name]
+		if !ok {
+			continue
+		}
+		subTable, ok := val.(*ast.Table)
+		if !ok {
+			c.addIssue(fn, 0, "", CheckError, "config", fmt.Sprintf("invalid configuration, bad table name %q", name))
+			continue
+		}
+		var target interface{} = c.cfg.Tags
+		if name == "agent" {
+			target = c.cfg.Agent
+		}
+		if err := c.cfg.toml.UnmarshalTable(subTable, target); err != nil {
+			c.addError(fn, subTable, name, "type", err)
+		}
+		c.checkUnusedFields(fn, subTable, name)
+	}
+
+	// Check the plugins in a stable order
+	for _, name := range sortedFieldNames(tbl) {
+		subTable, ok := tbl.Fields[name].(*ast.Table)
+		if !ok {
+			c.addIssue(fn, 0, "", CheckError, "config", fmt.Sprintf("invalid configuration, error parsing field %q as table", name))
+			continue
+		}
+
+		switch name {
+		case "agent", "global_tags", "tags":
+		case "inputs", "plugins", "outputs", "processors", "aggregators", "secretstores":
+			category := name
+			if category == "plugins" {
+				category = "inputs"
+			}
+			for _, pluginName := range sortedFieldNames(subTable) {
+				switch pluginSubTable := subTable.Fields[pluginName].(type) {
+				case *ast.Table:
+					// Only inputs and outputs support the legacy single table
+					if category != "inputs" && category != "outputs" {
+						c.addIssue(fn, pluginSubTable.Line, category+"."+pluginName, CheckError, "config", "unsupported config format")
+						continue
+					}
+					c.checkPlugin(fn, category, pluginName, pluginSubTable)
+				case []*ast.Table:
+					for _, t := range pluginSubTable {
+						c.checkPlugin(fn, category, pluginName, t)
+					}
+				default:
+					c.addIssue(fn, subTable.Line, category+"."+pluginName, CheckError, "config", "unsupported config format")
+				}
+			}
+		case "output_groups":
+			for _, groupName := range sortedFieldNames(subTable) {
+				groupTable, ok := subTable.Fields[groupName].(*ast.Table)
+				if !ok {
+					c.addIssue(fn, subTable.Line, "output_groups."+groupName, CheckError, "config", "unsupported config format")
+					continue
+				}
+				if err := c.cfg.addOutputGroup(groupName, groupTable); err != nil {
+					c.addError(fn, groupTable, "output_groups."+groupName, "config", err)
+				}
+				c.checkUnusedFields(fn, groupTable, "output_groups."+groupName)
+			}
+		default:
+			// Assume it's an input for legacy config file support
+			c.checkPlugin(fn, "inputs", name, subTable)
+		}
+	}
+}
+
+func (c *checker) checkPlugin(fn, category, name string, tbl *ast.Table) {
+	id := category + "." + name
+
+	// Check the filters separately to be able to report them as such
+	if category != "secretstores" {
+		_, err := c.cfg.buildFilter(tbl)
+		c.cfg.errs = nil
+		if err != nil {
+			c.addError(fn, tbl, id, "filter", err)
+			return
+		}
+	}
+
+	var err error
+	switch category {
+	case "inputs":
+		err = c.cfg.addInput(name, tbl)
+	case "outputs":
+		err = c.cfg.addOutput(name, tbl)
+	case "processors":
+		err = c.cfg.addProcessor(name, tbl)
+	case "aggregators":
+		err = c.cfg.addAggregator(name, tbl)
+	case "secretstores":
+		err = c.cfg.addSecretStore(name, tbl)
+	}
+	c.cfg.errs = nil
+	c.checkUnusedFields(fn, tbl, id)
+	if err != nil {
+		var lineErr *toml.LineError
+		switch {
+		case errors.Is(err, errDeprecated):
+			c.addError(fn, tbl, id, "deprecated", err)
+		case errors.As(err, &lineErr):
+			c.addError(fn, tbl, id, "type", err)
+		default:
+			c.addError(fn, tbl, id, "config", err)
+		}
+		return
+	}
+
+	// Get the created plugin and initialize it
+	var plugin interface{}
+	var initErr error
+	switch category {
+	case "inputs":
+		ri := c.cfg.Inputs[len(c.cfg.Inputs)-1]
+		plugin = ri.Input
+		initErr = ri.Init()
+	case "outputs":
+		ro := c.cfg.Outputs[len(c.cfg.Outputs)-1]
+		plugin = ro.Output
+		initErr = ro.Init()
+	case "processors":
+		rp := c.cfg.fileProcessors[len(c.cfg.fileProcessors)-1].plugin.(*models.RunningProcessor)
+		if p, ok := rp.Processor.(unwrappable); ok {
+			plugin = p.Unwrap()
+		} else {
+			plugin = rp.Processor
+		}
+		initErr = rp.Init()
+	case "aggregators":
+		ra := c.cfg.Aggregators[len(c.cfg.Aggregators)-1]
+		plugin = ra.Aggregator
+		initErr = ra.Init()
+	case "secretstores":
+		// Secret-stores are initialized when being added
+		var storeid string
+		c.cfg.getFieldString(tbl, "id", &storeid)
+		plugin = c.cfg.SecretStores[storeid]
+	}
+	c.checkDeprecations(fn, tbl, category, name, plugin)
+	if initErr != nil {
+		c.addError(fn, tbl, id, "init", initErr)
+	}
+
+	c.plugins = append(c.plugins, checkedPlugin{file: fn, id: id, table: tbl, plugin: plugin})
+}
+
+func (c *checker) checkUnusedFields(fn string, tbl *ast.Table, id string) {
+	unused := keys(c.cfg.UnusedFields)
+	sort.Strings(unused)
+	for _, key := range unused {
+		line := findKeyLine(tbl, key)
+		c.addIssue(fn, line, id, CheckError, "unknown_option", fmt.Sprintf("unknown option %q", key))
+	}
+	c.cfg.UnusedFields = make(map[string]bool)
+}
+
+func (c *checker) checkDeprecations(fn string, tbl *ast.Table, category, name string, plugin interface{}) {
+	id := category + "." + name
+	info := c.cfg.collectDeprecationInfo(category, name, plugin, false)
+	if info.info.Since != "" {
+		msg := fmt.Sprintf("plugin deprecated since version %s and will be removed in %s: %s",
+			info.info.Since, info.info.RemovalIn, info.info.Notice)
+		c.addIssue(fn, tbl.Line, id, CheckWarning, "deprecated", msg)
+	}
+	for _, option := range info.Options {
+		if option.info.Since == "" {
+			continue
+		}
+		msg := fmt.Sprintf("option %q deprecated since version %s and will be removed in %s: %s",
+			option.Name, option.info.Since, option.info.RemovalIn, option.info.Notice)
+		c.addIssue(fn, findKeyLine(tbl, option.Name), id, CheckWarning, "deprecated", msg)
+	}
+}
+
+// checkSecrets checks that all secrets of the created plugins reference
+// known secret-stores and optionally resolves the secrets.
+func (c *checker) checkSecrets(resolve bool) {
+	secretType := reflect.TypeOf(Secret{})
+	for _, p := range c.plugins {
+		walkPluginStruct(reflect.ValueOf(p.plugin), func(field reflect.StructField, value reflect.Value) {
+			if value.Type() != secretType || !value.CanAddr() {
+				return
+			}
+			secret := value.Addr().Interface().(*Secret)
+			defer secret.Destroy()
+			line := findKeyLine(p.table, field.Tag.Get("toml"))
+
+			if !resolve {
+				for _, ref := range secret.GetUnlinked() {
+					storeid, _ := splitLink(ref)
+					if _, found := c.cfg.SecretStores[storeid]; !found {
+						c.addIssue(p.file, line, p.id, CheckError, "secret", fmt.Sprintf("unknown secret-store for %q", ref))
+					}
+				}
+				return
+			}
+
+			if err := c.cfg.linkSecret(secret); err != nil {
+				c.addIssue(p.file, line, p.id, CheckError, "secret", err.Error())
+				return
+			}
+			buf, err := secret.Get()
+			if buf == nil && err != nil {
+				c.addIssue(p.file, line, p.id, CheckError, "secret", err.Error())
+				return
+			}
+			ReleaseSecret(buf)
+		})
+	}
+}
+
+func (c *checker) addIssue(fn string, line int, plugin, severity, kind, msg string) {
+	c.report.add(CheckIssue{
+		File:     fn,
+		Line:     line,
+		Plugin:   plugin,
+		Severity: severity,
+		Kind:     kind,
+		Message:  msg,
+	})
+}
+
+// addError adds an error issue using the line of the error if known or the
+// line of the given table otherwise.
+func (c *checker) addError(fn string, tbl *ast.Table, plugin, kind string, err error) {
+	var line int
+	var lineErr *toml.LineError
+	if errors.As(err, &lineErr) {
+		line = lineErr.Line
+	} else if tbl != nil {
+		line = tbl.Line
+	}
+	c.addIssue(fn, line, plugin, CheckError, kind, err.Error())
+}
+
+// findKeyLine returns the line of the given key in the table or any of its
+// sub-tables falling back to the line of the table itself.
+func findKeyLine(tbl *ast.Table, key string) int {
+	if line := searchKeyLine(tbl, key); line > 0 {
+		return line
+	}
+	return tbl.Line
+}
+
+func searchKeyLine(tbl *ast.Table, key string) int {
+	if kv, ok := tbl.Fields[key].(*ast.KeyValue); ok {
+		return kv.Line
+	}
+	for _, name := range sortedFieldNames(tbl) {
+		switch v := tbl.Fields[name].(type) {
+		case *ast.Table:
+			if line := searchKeyLine(v, key); line > 0 {
+				return line
+			}
+		case []*ast.Table:
+			for _, t := range v {
+				if line := searchKeyLine(t, key); line > 0 {
+					return line
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func sortedFieldNames(tbl *ast.Table) []string {
+	names := make([]string, 0, len(tbl.Fields))
+	for name := range tbl.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
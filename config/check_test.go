@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConfigFiles(t *testing.T) {
+	fn := filepath.Join("testdata", "check.toml")
+	expected := []CheckIssue{
+		{
+			Line:     3,
+			Plugin:   "agent",
+			Severity: CheckError,
+			Kind:     "unknown_option",
+			Message:  `unknown option "unknown_agent_option"`,
+		},
+		{
+			Line:     7,
+			Plugin:   "inputs.memcached",
+			Severity: CheckError,
+			Kind:     "unknown_option",
+			Message:  `unknown option "foo"`,
+		},
+		{
+			Line:     9,
+			Plugin:   "inputs.memcached",
+			Severity: CheckError,
+			Kind:     "filter",
+			Message:  "error compiling 'namepass', unexpected end of input",
+		},
+		{
+			Line:     13,
+			Plugin:   "inputs.http_listener_v2",
+			Severity: CheckError,
+			Kind:     "type",
+			Message:  "line 13: (config.MockupInputPlugin.Port) cannot unmarshal TOML string into int",
+		},
+		{
+			Line:     16,
+			Plugin:   "inputs.migration_test",
+			Severity: CheckWarning,
+			Kind:     "deprecated",
+			Message:  `option "manual" deprecated since version 1.0.0 and will be removed in 2.0.0: use 'other' instead`,
+		},
+		{
+			Line:     19,
+			Plugin:   "inputs.mockup",
+			Severity: CheckError,
+			Kind:     "secret",
+			Message:  `unknown secret-store for "@{unknown:key}"`,
+		},
+		{
+			Line:     24,
+			Plugin:   "inputs.undefined",
+			Severity: CheckError,
+			Kind:     "config",
+			Message:  "undefined but requested input: undefined",
+		},
+	}
+	for i := range expected {
+		expected[i].File = fn
+	}
+
+	report := CheckConfigFiles([]string{fn}, false)
+	require.Equal(t, []string{fn}, report.Files)
+	require.Equal(t, expected, report.Issues)
+	require.Equal(t, 6, report.Errors)
+	require.Equal(t, 1, report.Warnings)
+	require.True(t, report.Failed())
+}
+
+func TestCheckConfigFilesResolveSecrets(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "telegraf.conf")
+	cfg := []byte(`
+[[inputs.mockup]]
+  secret = "@{mock:key}"
+
+[[secretstores.mockup]]
+  id = "mock"
+`)
+	require.NoError(t, os.WriteFile(fn, cfg, 0600))
+
+	// Without resolving, the reference to the store is valid
+	report := CheckConfigFiles([]string{fn}, false)
+	require.Empty(t, report.Issues)
+	require.False(t, report.Failed())
+
+	// The store does not contain the secret
+	report = CheckConfigFiles([]string{fn}, true)
+	require.Len(t, report.Issues, 1)
+	require.Equal(t, 3, report.Issues[0].Line)
+	require.Equal(t, "secret", report.Issues[0].Kind)
+	require.Contains(t, report.Issues[0].Message, `resolving "@{mock:key}" failed: not found`)
+}
+
+func TestCheckConfigFilesInvalid(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "telegraf.conf")
+	require.NoError(t, os.WriteFile(fn, []byte("[[inputs.memcached]\n"), 0600))
+
+	report := CheckConfigFiles([]string{fn, filepath.Join("testdata", "non_existing.toml")}, false)
+	require.Len(t, report.Issues, 2)
+	require.Equal(t, "parse", report.Issues[0].Kind)
+	require.Equal(t, 1, report.Issues[0].Line)
+	require.Equal(t, "load", report.Issues[1].Kind)
+	require.Equal(t, 2, report.Errors)
+}
+
+func TestCheckConfigFilesOutputBuffer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "buffer")
+	fn := filepath.Join(t.TempDir(), "telegraf.conf")
+	cfg := []byte(`
+[agent]
+  buffer_strategy = "disk"
+  buffer_directory = "` + filepath.ToSlash(dir) + `"
+
+[[outputs.http]]
+
+[[outputs.http]]
+  buffer_strategy = "foo"
+`)
+	require.NoError(t, os.WriteFile(fn, cfg, 0600))
+
+	// The buffer settings are checked without creating the buffer
+	report := CheckConfigFiles([]string{fn}, false)
+	require.Len(t, report.Issues, 1)
+	require.Equal(t, 8, report.Issues[0].Line)
+	require.Equal(t, "config", report.Issues[0].Kind)
+	require.Equal(t, `invalid buffer strategy "foo"`, report.Issues[0].Message)
+	require.NoDirExists(t, dir)
+}
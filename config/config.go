@@ -65,12 +65,22 @@ type Config struct {
 	OutputFilters      []string
 	SecretStoreFilters []string
 
+	// Create the outputs without their metric buffers, e.g. for checking
+	// the configuration or comparing it to the running agent. The buffers
+	// are opened when the outputs are started.
+	DeferOutputBuffers bool
+
 	SecretStores map[string]telegraf.SecretStore
+	// Configuration hashes of the secret-stores by their ID for detecting
+	// changed settings
+	SecretStoreHashes map[string]string
 
 	Agent       *AgentConfig
 	Inputs      []*models.RunningInput
 	Outputs     []*models.RunningOutput
 	Aggregators []*models.RunningAggregator
+	// Output groups distributing metrics among their member outputs
+	OutputGroups []*models.OutputGroupConfig
 	// Processors have a slice wrapper type because they need to be sorted
 	Processors        models.RunningProcessors
 	AggProcessors     models.RunningProcessors
@@ -119,9 +129,11 @@ func NewConfig() *Config {
 		Tags:               make(map[string]string),
 		Inputs:             make([]*models.RunningInput, 0),
 		Outputs:            make([]*models.RunningOutput, 0),
+		OutputGroups:       make([]*models.OutputGroupConfig, 0),
 		Processors:         make([]*models.RunningProcessor, 0),
 		AggProcessors:      make([]*models.RunningProcessor, 0),
 		SecretStores:       make(map[string]telegraf.SecretStore),
+		SecretStoreHashes:  make(map[string]string),
 		fileProcessors:     make([]*OrderedPlugin, 0),
 		fileAggProcessors:  make([]*OrderedPlugin, 0),
 		InputFilters:       make([]string, 0),
@@ -200,6 +212,16 @@ type AgentConfig struct {
 	// not be less than 2 times MetricBatchSize.
 	MetricBufferLimit int
 
+	// BufferStrategy is the type of buffer used by the outputs to store
+	// unwritten metrics. Can be "memory" (default) or "disk" to persist the
+	// metrics in a write-ahead log in BufferDirectory and replay them on the
+	// next start.
+	BufferStrategy string `toml:"buffer_strategy"`
+
+	// BufferDirectory is the directory used by the "disk" buffer strategy.
+	// Each output stores its metrics in a sub-directory named by its ID.
+	BufferDirectory string `toml:"buffer_directory"`
+
 	// FlushBufferWhenFull tells Telegraf to flush the metric buffer whenever
 	// it fills up, regardless of FlushInterval. Setting this option to true
 	// does _not_ deactivate FlushInterval.
@@ -221,6 +243,10 @@ type AgentConfig struct {
 	// is determined by the "logfile" setting.
 	LogTarget string `toml:"logtarget"`
 
+	// Log format controls the format of the log messages and can be one of
+	// "text" or "json".
+	LogFormat string `toml:"logformat"`
+
 	// Name of the file to be logged to when using the "file" logtarget.  If set to
 	// the empty string then logs are written to stderr.
 	Logfile string `toml:"logfile"`
@@ -252,6 +278,18 @@ type AgentConfig struct {
 	// stateful plugins on termination of Telegraf. If the file exists on start,
 	// the state in the file will be restored for the plugins.
 	Statefile string `toml:"statefile"`
+
+	// Interval for periodically storing the state of plugins to the statefile
+	// while running. When set to 0 the states are only stored on termination.
+	StatefileCheckpointInterval Duration `toml:"statefile_checkpoint_interval"`
+
+	// Address to serve the admin API on for inspecting and controlling the
+	// plugins of the running agent. The API is disabled if empty.
+	AdminAddress string `toml:"admin_address"`
+
+	// Address to serve the internal statistics of the agent on in the
+	// Prometheus exposition format. The endpoint is disabled if empty.
+	MetricsAddress string `toml:"metrics_address"`
 }
 
 // InputNames returns a list of strings of the configured inputs.
@@ -368,11 +406,12 @@ func WalkDirectory(path string) ([]string, error) {
 	return files, filepath.Walk(path, walkfn)
 }
 
-// Try to find a default config file at these locations (in order):
+// GetDefaultConfigPath tries to find a default config file at these locations
+// (in order):
 //  1. $TELEGRAF_CONFIG_PATH
 //  2. $HOME/.telegraf/telegraf.conf
 //  3. /etc/telegraf/telegraf.conf and /etc/telegraf/telegraf.d/*.conf
-func getDefaultConfigPath() ([]string, error) {
+func GetDefaultConfigPath() ([]string, error) {
 	envfile := os.Getenv("TELEGRAF_CONFIG_PATH")
 	homefile := os.ExpandEnv("${HOME}/.telegraf/telegraf.conf")
 	etcfile := "/etc/telegraf/telegraf.conf"
@@ -430,7 +469,7 @@ func (c *Config) LoadConfig(path string) error {
 	paths := []string{}
 
 	if path == "" {
-		if paths, err = getDefaultConfigPath(); err != nil {
+		if paths, err = GetDefaultConfigPath(); err != nil {
 			return err
 		}
 	} else {
@@ -467,6 +506,10 @@ func (c *Config) LoadAll(configFiles ...string) error {
 	sort.Stable(c.Processors)
 	sort.Stable(c.AggProcessors)
 
+	if err := c.checkOutputGroups(); err != nil {
+		return err
+	}
+
 	// Set snmp agent translator default
 	if c.Agent.SnmpTranslator == "" {
 		c.Agent.SnmpTranslator = "netsnmp"
@@ -643,6 +686,20 @@ func (c *Config) LoadConfigData(data []byte) error {
 						name, pluginName, subTable.Line, keys(c.UnusedFields))
 				}
 			}
+		case "output_groups":
+			for groupName, groupVal := range subTable.Fields {
+				groupSubTable, ok := groupVal.(*ast.Table)
+				if !ok {
+					return fmt.Errorf("unsupported config format: %s", groupName)
+				}
+				if err = c.addOutputGroup(groupName, groupSubTable); err != nil {
+					return fmt.Errorf("error parsing output group %s, %w", groupName, err)
+				}
+				if len(c.UnusedFields) > 0 {
+					return fmt.Errorf("output group %s: line %d: configuration specified the fields %q, but they weren't used",
+						groupName, groupSubTable.Line, keys(c.UnusedFields))
+				}
+			}
 		case "secretstores":
 			for pluginName, pluginVal := range subTable.Fields {
 				switch pluginSubTable := pluginVal.(type) {
@@ -861,7 +918,7 @@ func (c *Config) addAggregator(name string, table *ast.Table) error {
 		// Handle removed, deprecated plugins
 		if di, deprecated := aggregators.Deprecations[name]; deprecated {
 			printHistoricPluginDeprecationNotice("aggregators", name, di)
-			return fmt.Errorf("plugin deprecated")
+			return fmt.Errorf("plugin %w", errDeprecated)
 		}
 		return fmt.Errorf("undefined but requested aggregator: %s", name)
 	}
@@ -884,6 +941,56 @@ func (c *Config) addAggregator(name string, table *ast.Table) error {
 	return nil
 }
 
+func (c *Config) addOutputGroup(name string, table *ast.Table) error {
+	for _, group := range c.OutputGroups {
+		if group.Name == name {
+			return errors.New("duplicate output group")
+		}
+	}
+
+	group := &models.OutputGroupConfig{Name: name}
+	if err := c.toml.UnmarshalTable(table, group); err != nil {
+		return err
+	}
+	if err := group.Check(); err != nil {
+		return err
+	}
+
+	c.OutputGroups = append(c.OutputGroups, group)
+	return nil
+}
+
+// checkOutputGroups checks that the members of all output groups refer to
+// exactly one loaded output and that no output is member of multiple groups.
+// Members removed by the output filters are ignored.
+func (c *Config) checkOutputGroups() error {
+	grouped := make(map[string]string)
+	for _, group := range c.OutputGroups {
+		for _, alias := range group.Outputs {
+			var count int
+			for _, output := range c.Outputs {
+				if output.Config.Alias == alias {
+					count++
+				}
+			}
+			switch {
+			case count == 0 && len(c.OutputFilters) > 0:
+				log.Printf("W! Output %q of output group %q not loaded", alias, group.Name)
+			case count == 0:
+				return fmt.Errorf("output group %q: no output with alias %q", group.Name, alias)
+			case count > 1:
+				return fmt.Errorf("output group %q: multiple outputs with alias %q", group.Name, alias)
+			}
+
+			if other, found := grouped[alias]; found {
+				return fmt.Errorf("output %q is member of output groups %q and %q", alias, other, group.Name)
+			}
+			grouped[alias] = group.Name
+		}
+	}
+	return nil
+}
+
 func (c *Config) addSecretStore(name string, table *ast.Table) error {
 	if len(c.SecretStoreFilters) > 0 && !sliceContains(name, c.SecretStoreFilters) {
 		return nil
@@ -903,7 +1010,7 @@ func (c *Config) addSecretStore(name string, table *ast.Table) error {
 		// Handle removed, deprecated plugins
 		if di, deprecated := secretstores.Deprecations[name]; deprecated {
 			printHistoricPluginDeprecationNotice("secretstores", name, di)
-			return errors.New("plugin deprecated")
+			return fmt.Errorf("plugin %w", errDeprecated)
 		}
 		return fmt.Errorf("undefined but requested secretstores: %s", name)
 	}
@@ -917,6 +1024,9 @@ func (c *Config) addSecretStore(name string, table *ast.Table) error {
 		return err
 	}
 
+	logger := models.NewLogger("secretstores", name, storeid)
+	models.SetLoggerOnPlugin(store, logger)
+
 	if err := store.Init(); err != nil {
 		return fmt.Errorf("error initializing secret-store %q: %w", storeid, err)
 	}
@@ -924,30 +1034,56 @@ func (c *Config) addSecretStore(name string, table *ast.Table) error {
 	if _, found := c.SecretStores[storeid]; found {
 		return fmt.Errorf("duplicate ID %q for secretstore %q", storeid, name)
 	}
+
+	hash, err := generatePluginID("secretstores."+name, table)
+	if err != nil {
+		return err
+	}
 	c.SecretStores[storeid] = store
+	c.SecretStoreHashes[storeid] = hash
 	return nil
 }
 
 func (c *Config) LinkSecrets() error {
 	for _, s := range unlinkedSecrets {
-		resolvers := make(map[string]telegraf.ResolveFunc)
-		for _, ref := range s.GetUnlinked() {
-			// Split the reference and lookup the resolver
-			storeid, key := splitLink(ref)
-			store, found := c.SecretStores[storeid]
-			if !found {
-				return fmt.Errorf("unknown secret-store for %q", ref)
-			}
-			resolver, err := store.GetResolver(key)
-			if err != nil {
-				return fmt.Errorf("retrieving resolver for %q failed: %w", ref, err)
-			}
-			resolvers[ref] = resolver
+		if err := c.linkSecret(s); err != nil {
+			return err
 		}
-		// Inject the resolver list into the secret
-		if err := s.Link(resolvers); err != nil {
-			return fmt.Errorf("retrieving resolver failed: %w", err)
+	}
+	return nil
+}
+
+// linkSecret links the given secret to the resolvers of the configured
+// secret-stores.
+func (c *Config) linkSecret(s *Secret) error {
+	resolvers := make(map[string]telegraf.ResolveFunc)
+	rotators := make(map[string]func() error)
+	for _, ref := range s.GetUnlinked() {
+		// Split the reference and lookup the resolver
+		storeid, key := splitLink(ref)
+		store, found := c.SecretStores[storeid]
+		if !found {
+			return fmt.Errorf("unknown secret-store for %q", ref)
+		}
+		resolver, err := store.GetResolver(key)
+		if err != nil {
+			return fmt.Errorf("retrieving resolver for %q failed: %w", ref, err)
 		}
+
+		// Keep rotatable secrets refreshable instead of resolving them once
+		if rs, ok := store.(telegraf.RotatableSecretStore); ok && rs.Rotatable(key) {
+			r := &rotatingResolver{store: rs, key: key, resolver: resolver}
+			resolver = r.resolve
+			rotators[ref] = r.refresh
+		}
+		resolvers[ref] = resolver
+	}
+	// Inject the resolver list into the secret
+	if err := s.Link(resolvers); err != nil {
+		return fmt.Errorf("retrieving resolver failed: %w", err)
+	}
+	if len(rotators) > 0 {
+		s.rotators = rotators
 	}
 	return nil
 }
@@ -1044,7 +1180,7 @@ func (c *Config) addProcessor(name string, table *ast.Table) error {
 		// Handle removed, deprecated plugins
 		if di, deprecated := processors.Deprecations[name]; deprecated {
 			printHistoricPluginDeprecationNotice("processors", name, di)
-			return fmt.Errorf("plugin deprecated")
+			return fmt.Errorf("plugin %w", errDeprecated)
 		}
 		return fmt.Errorf("undefined but requested processor: %s", name)
 	}
@@ -1061,7 +1197,11 @@ func (c *Config) addProcessor(name string, table *ast.Table) error {
 	defer c.resetMissingTomlFieldTracker()
 
 	// Setup the processor running before the aggregators
-	processorBeforeConfig, err := c.buildProcessor("processors", name, table)
+	var probe interface{} = creator()
+	if p, ok := probe.(unwrappable); ok {
+		probe = p.Unwrap()
+	}
+	processorBeforeConfig, err := c.buildProcessor("processors", name, table, probe)
 	if err != nil {
 		return err
 	}
@@ -1073,7 +1213,7 @@ func (c *Config) addProcessor(name string, table *ast.Table) error {
 	c.fileProcessors = append(c.fileProcessors, &OrderedPlugin{table.Line, rf})
 
 	// Setup another (new) processor instance running after the aggregator
-	processorAfterConfig, err := c.buildProcessor("aggprocessors", name, table)
+	processorAfterConfig, err := c.buildProcessor("aggprocessors", name, table, probe)
 	if err != nil {
 		return err
 	}
@@ -1163,7 +1303,7 @@ func (c *Config) addOutput(name string, table *ast.Table) error {
 		// Handle removed, deprecated plugins
 		if di, deprecated := outputs.Deprecations[name]; deprecated {
 			printHistoricPluginDeprecationNotice("outputs", name, di)
-			return fmt.Errorf("plugin deprecated")
+			return fmt.Errorf("plugin %w", errDeprecated)
 		}
 		return fmt.Errorf("undefined but requested output: %s", name)
 	}
@@ -1201,7 +1341,7 @@ func (c *Config) addOutput(name string, table *ast.Table) error {
 		}
 	}
 
-	outputConfig, err := c.buildOutput(name, table)
+	outputConfig, err := c.buildOutput(name, table, output)
 	if err != nil {
 		return err
 	}
@@ -1230,7 +1370,26 @@ func (c *Config) addOutput(name string, table *ast.Table) error {
 		}
 	}
 
-	ro := models.NewRunningOutput(output, outputConfig, c.Agent.MetricBatchSize, c.Agent.MetricBufferLimit)
+	// The disk buffer is stored in a directory named after the plugin ID, so
+	// identically configured outputs would share the same write-ahead log
+	if outputConfig.BufferStrategy == "disk" {
+		for _, o := range c.Outputs {
+			if o.Config.ID == outputConfig.ID && o.Config.BufferDirectory == outputConfig.BufferDirectory {
+				return fmt.Errorf("duplicate output %q using the disk buffer, set a unique alias", name)
+			}
+		}
+	}
+
+	if c.DeferOutputBuffers {
+		ro := models.NewDeferredRunningOutput(output, outputConfig, c.Agent.MetricBatchSize, c.Agent.MetricBufferLimit)
+		c.Outputs = append(c.Outputs, ro)
+		return nil
+	}
+
+	ro, err := models.NewRunningOutput(output, outputConfig, c.Agent.MetricBatchSize, c.Agent.MetricBufferLimit)
+	if err != nil {
+		return err
+	}
 	c.Outputs = append(c.Outputs, ro)
 
 	return nil
@@ -1257,7 +1416,7 @@ func (c *Config) addInput(name string, table *ast.Table) error {
 		// Handle removed, deprecated plugins
 		if di, deprecated := inputs.Deprecations[name]; deprecated {
 			printHistoricPluginDeprecationNotice("inputs", name, di)
-			return fmt.Errorf("plugin deprecated")
+			return fmt.Errorf("plugin %w", errDeprecated)
 		}
 
 		return fmt.Errorf("undefined but requested input: %s", name)
@@ -1281,11 +1440,17 @@ func (c *Config) addInput(name string, table *ast.Table) error {
 			return errors.New("parser not found")
 		}
 		t.SetParserFunc(func() (telegraf.Parser, error) {
-			return c.addParser("inputs", name, table)
+			parser, err := c.addParser("inputs", name, table)
+			if err == nil {
+				if _, ok := parser.Parser.(telegraf.StreamParser); ok {
+					return &models.RunningStreamParser{RunningParser: parser}, nil
+				}
+			}
+			return parser, err
 		})
 	}
 
-	pluginConfig, err := c.buildInput(name, table)
+	pluginConfig, err := c.buildInput(name, table, input)
 	if err != nil {
 		return err
 	}
@@ -1368,16 +1533,28 @@ func (c *Config) buildAggregator(name string, tbl *ast.Table) (*models.Aggregato
 // buildProcessor parses Processor specific items from the ast.Table,
 // builds the filter and returns a
 // models.ProcessorConfig to be inserted into models.RunningProcessor
-func (c *Config) buildProcessor(category, name string, tbl *ast.Table) (*models.ProcessorConfig, error) {
+func (c *Config) buildProcessor(category, name string, tbl *ast.Table, plugin interface{}) (*models.ProcessorConfig, error) {
 	conf := &models.ProcessorConfig{Name: name}
 
 	c.getFieldInt64(tbl, "order", &conf.Order)
 	c.getFieldString(tbl, "alias", &conf.Alias)
+	if !c.hasPluginOption(plugin, "log_level") {
+		c.getFieldString(tbl, "log_level", &conf.LogLevel)
+	}
+	c.getFieldInt(tbl, "batch_size", &conf.BatchSize)
+	c.getFieldDuration(tbl, "batch_timeout", &conf.BatchTimeout)
 
 	if c.hasErrs() {
 		return nil, c.firstErr()
 	}
 
+	if _, err := models.ParseLogLevel(conf.LogLevel); err != nil {
+		return nil, err
+	}
+	if conf.BatchSize < 0 {
+		return nil, errors.New("batch_size must not be negative")
+	}
+
 	var err error
 	conf.Filter, err = c.buildFilter(tbl)
 	if err != nil {
@@ -1427,7 +1604,7 @@ func (c *Config) buildFilter(tbl *ast.Table) (models.Filter, error) {
 // buildInput parses input specific items from the ast.Table,
 // builds the filter and returns a
 // models.InputConfig to be inserted into models.RunningInput
-func (c *Config) buildInput(name string, tbl *ast.Table) (*models.InputConfig, error) {
+func (c *Config) buildInput(name string, tbl *ast.Table, plugin interface{}) (*models.InputConfig, error) {
 	cp := &models.InputConfig{Name: name}
 	c.getFieldDuration(tbl, "interval", &cp.Interval)
 	c.getFieldDuration(tbl, "precision", &cp.Precision)
@@ -1437,6 +1614,10 @@ func (c *Config) buildInput(name string, tbl *ast.Table) (*models.InputConfig, e
 	c.getFieldString(tbl, "name_suffix", &cp.MeasurementSuffix)
 	c.getFieldString(tbl, "name_override", &cp.NameOverride)
 	c.getFieldString(tbl, "alias", &cp.Alias)
+	c.getFieldString(tbl, "startup_error_behavior", &cp.StartupErrorBehavior)
+	if !c.hasPluginOption(plugin, "log_level") {
+		c.getFieldString(tbl, "log_level", &cp.LogLevel)
+	}
 
 	cp.Tags = make(map[string]string)
 	if node, ok := tbl.Fields["tags"]; ok {
@@ -1451,6 +1632,10 @@ func (c *Config) buildInput(name string, tbl *ast.Table) (*models.InputConfig, e
 		return nil, c.firstErr()
 	}
 
+	if _, err := models.ParseLogLevel(cp.LogLevel); err != nil {
+		return nil, err
+	}
+
 	var err error
 	cp.Filter, err = c.buildFilter(tbl)
 	if err != nil {
@@ -1511,14 +1696,16 @@ func (c *Config) buildSerializerOld(tbl *ast.Table) (telegraf.Serializer, error)
 // builds the filter and returns a
 // models.OutputConfig to be inserted into models.RunningInput
 // Note: error exists in the return for future calls that might require error
-func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig, error) {
+func (c *Config) buildOutput(name string, tbl *ast.Table, plugin interface{}) (*models.OutputConfig, error) {
 	filter, err := c.buildFilter(tbl)
 	if err != nil {
 		return nil, err
 	}
 	oc := &models.OutputConfig{
-		Name:   name,
-		Filter: filter,
+		Name:            name,
+		Filter:          filter,
+		BufferStrategy:  c.Agent.BufferStrategy,
+		BufferDirectory: c.Agent.BufferDirectory,
 	}
 
 	// TODO: support FieldPass/FieldDrop on outputs
@@ -1528,7 +1715,32 @@ func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig,
 
 	c.getFieldInt(tbl, "metric_buffer_limit", &oc.MetricBufferLimit)
 	c.getFieldInt(tbl, "metric_batch_size", &oc.MetricBatchSize)
+	c.getFieldString(tbl, "buffer_strategy", &oc.BufferStrategy)
+	c.getFieldString(tbl, "buffer_directory", &oc.BufferDirectory)
 	c.getFieldString(tbl, "alias", &oc.Alias)
+	c.getFieldString(tbl, "startup_error_behavior", &oc.StartupErrorBehavior)
+	if !c.hasPluginOption(plugin, "log_level") {
+		c.getFieldString(tbl, "log_level", &oc.LogLevel)
+	}
+
+	// Options of the plugin with the same name take precedence over the
+	// general retry options
+	if !c.hasPluginOption(plugin, "retry_initial_backoff") {
+		c.getFieldDuration(tbl, "retry_initial_backoff", &oc.RetryInitialBackoff)
+	}
+	if !c.hasPluginOption(plugin, "retry_max_backoff") {
+		c.getFieldDuration(tbl, "retry_max_backoff", &oc.RetryMaxBackoff)
+	}
+	if !c.hasPluginOption(plugin, "retry_jitter") {
+		c.getFieldDuration(tbl, "retry_jitter", &oc.RetryJitter)
+	}
+	if !c.hasPluginOption(plugin, "circuit_breaker_threshold") {
+		c.getFieldInt(tbl, "circuit_breaker_threshold", &oc.CircuitBreakerThreshold)
+	}
+	if !c.hasPluginOption(plugin, "circuit_breaker_reset_timeout") {
+		c.getFieldDuration(tbl, "circuit_breaker_reset_timeout", &oc.CircuitBreakerResetTimeout)
+	}
+
 	c.getFieldString(tbl, "name_override", &oc.NameOverride)
 	c.getFieldString(tbl, "name_suffix", &oc.NameSuffix)
 	c.getFieldString(tbl, "name_prefix", &oc.NamePrefix)
@@ -1537,25 +1749,82 @@ func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig,
 		return nil, c.firstErr()
 	}
 
+	switch oc.BufferStrategy {
+	case "", "memory":
+	case "disk":
+		if oc.BufferDirectory == "" {
+			return nil, errors.New("buffer_directory required for buffer strategy \"disk\"")
+		}
+	default:
+		return nil, fmt.Errorf("invalid buffer strategy %q", oc.BufferStrategy)
+	}
+
+	if _, err := models.ParseLogLevel(oc.LogLevel); err != nil {
+		return nil, err
+	}
+
 	// Generate an ID for the plugin
 	oc.ID, err = generatePluginID("outputs."+name, tbl)
 	return oc, err
 }
 
+// hasPluginOption returns true if the plugin defines an option with the given
+// key itself. General options of the same name are not applied in this case
+// to keep the plugin's option working as documented.
+func (c *Config) hasPluginOption(plugin interface{}, key string) bool {
+	t := reflect.TypeOf(plugin)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "-":
+		case name != "":
+			if name == key {
+				return true
+			}
+		case field.Anonymous:
+			if c.hasPluginOption(reflect.New(field.Type).Interface(), key) {
+				return true
+			}
+		case c.toml.NormFieldName(t, field.Name) == c.toml.NormFieldName(t, key):
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Config) missingTomlField(_ reflect.Type, key string) error {
 	switch key {
 	// General options to ignore
 	case "alias",
+		"batch_size", "batch_timeout",
+		"buffer_directory", "buffer_strategy",
+		"circuit_breaker_reset_timeout", "circuit_breaker_threshold",
 		"collection_jitter", "collection_offset",
 		"data_format", "delay", "drop", "drop_original",
 		"fielddrop", "fieldpass", "flush_interval", "flush_jitter",
 		"grace",
 		"interval",
+		"log_level",
 		"lvm", // What is this used for?
 		"metric_batch_size", "metric_buffer_limit", "metricpass",
 		"name_override", "name_prefix", "name_suffix", "namedrop", "namepass",
 		"order",
 		"pass", "period", "precision",
+		"retry_initial_backoff", "retry_jitter", "retry_max_backoff",
+		"startup_error_behavior",
 		"tagdrop", "tagexclude", "taginclude", "tagpass", "tags":
 
 	// Secret-store options to ignore
@@ -542,6 +542,246 @@ func TestConfig_AzureMonitorNamespacePrefix(t *testing.T) {
 	}
 }
 
+func TestConfig_StreamParser(t *testing.T) {
+	c := NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[inputs.parser_test_new]]
+  data_format = "parquet"
+
+[[inputs.parser_test_new]]
+  data_format = "influx"
+`)))
+	require.Len(t, c.Inputs, 2)
+
+	// Parsers supporting streaming keep the interface when wrapped
+	input, ok := c.Inputs[0].Input.(*MockupInputPluginParserNew)
+	require.True(t, ok)
+	parser, err := input.ParserFunc()
+	require.NoError(t, err)
+	require.Implements(t, (*telegraf.StreamParser)(nil), parser)
+
+	input, ok = c.Inputs[1].Input.(*MockupInputPluginParserNew)
+	require.True(t, ok)
+	parser, err = input.ParserFunc()
+	require.NoError(t, err)
+	_, ok = parser.(telegraf.StreamParser)
+	require.False(t, ok)
+}
+
+func TestConfig_OutputBufferStrategy(t *testing.T) {
+	c := NewConfig()
+	c.Agent.BufferDirectory = t.TempDir()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[outputs.http]]
+
+[[outputs.http]]
+  buffer_strategy = "disk"
+`)))
+	require.Len(t, c.Outputs, 2)
+	require.Equal(t, "", c.Outputs[0].Config.BufferStrategy)
+	require.Equal(t, "disk", c.Outputs[1].Config.BufferStrategy)
+	require.Equal(t, c.Agent.BufferDirectory, c.Outputs[1].Config.BufferDirectory)
+	for _, o := range c.Outputs {
+		o.Close()
+	}
+
+	c = NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[outputs.http]]
+  buffer_strategy = "disk"
+`))
+	require.ErrorContains(t, err, "buffer_directory required")
+
+	c = NewConfig()
+	err = c.LoadConfigData([]byte(`
+[[outputs.http]]
+  buffer_strategy = "foo"
+`))
+	require.ErrorContains(t, err, `invalid buffer strategy "foo"`)
+
+	// Identical outputs would share the write-ahead log of the disk buffer
+	c = NewConfig()
+	c.Agent.BufferDirectory = t.TempDir()
+	err = c.LoadConfigData([]byte(`
+[[outputs.http]]
+  buffer_strategy = "disk"
+
+[[outputs.http]]
+  buffer_strategy = "disk"
+`))
+	require.ErrorContains(t, err, `duplicate output "http" using the disk buffer`)
+	for _, o := range c.Outputs {
+		o.Close()
+	}
+
+	c = NewConfig()
+	c.Agent.BufferDirectory = t.TempDir()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[outputs.http]]
+  buffer_strategy = "disk"
+
+[[outputs.http]]
+  alias = "second"
+  buffer_strategy = "disk"
+`)))
+	require.Len(t, c.Outputs, 2)
+	require.NotEqual(t, c.Outputs[0].Config.ID, c.Outputs[1].Config.ID)
+	for _, o := range c.Outputs {
+		o.Close()
+	}
+}
+
+func TestConfig_StartupErrorBehavior(t *testing.T) {
+	c := NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  startup_error_behavior = "ignore"
+
+[[outputs.http]]
+  startup_error_behavior = "retry"
+`)))
+	require.Len(t, c.Inputs, 1)
+	require.Equal(t, "ignore", c.Inputs[0].Config.StartupErrorBehavior)
+	require.Len(t, c.Outputs, 1)
+	require.Equal(t, "retry", c.Outputs[0].Config.StartupErrorBehavior)
+}
+
+func TestConfig_OutputRetryPolicy(t *testing.T) {
+	c := NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[outputs.http]]
+  retry_initial_backoff = "1s"
+  retry_max_backoff = "1m"
+  retry_jitter = "500ms"
+  circuit_breaker_threshold = 5
+  circuit_breaker_reset_timeout = "2m"
+`)))
+	require.Len(t, c.Outputs, 1)
+	conf := c.Outputs[0].Config
+	require.Equal(t, time.Second, conf.RetryInitialBackoff)
+	require.Equal(t, time.Minute, conf.RetryMaxBackoff)
+	require.Equal(t, 500*time.Millisecond, conf.RetryJitter)
+	require.Equal(t, 5, conf.CircuitBreakerThreshold)
+	require.Equal(t, 2*time.Minute, conf.CircuitBreakerResetTimeout)
+}
+
+func TestConfig_LogLevel(t *testing.T) {
+	c := NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  log_level = "debug"
+
+[[processors.processor]]
+  log_level = "off"
+
+[[outputs.http]]
+  log_level = "WARN"
+`)))
+	require.Len(t, c.Inputs, 1)
+	require.Equal(t, "debug", c.Inputs[0].Config.LogLevel)
+	require.Len(t, c.Processors, 1)
+	require.Equal(t, "off", c.Processors[0].Config.LogLevel)
+	require.Len(t, c.Outputs, 1)
+	require.Equal(t, "WARN", c.Outputs[0].Config.LogLevel)
+
+	c = NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  log_level = "verbose"
+`))
+	require.ErrorContains(t, err, `invalid log level "verbose"`)
+}
+
+func TestConfig_OutputGroups(t *testing.T) {
+	c := NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[outputs.http]]
+  alias = "primary"
+
+[[outputs.http]]
+  alias = "secondary"
+
+[output_groups.backend]
+  mode = "failover"
+  outputs = ["primary", "secondary"]
+`)))
+	require.NoError(t, c.checkOutputGroups())
+	require.Equal(t, []*models.OutputGroupConfig{
+		{
+			Name:    "backend",
+			Mode:    "failover",
+			Outputs: []string{"primary", "secondary"},
+		},
+	}, c.OutputGroups)
+}
+
+func TestConfig_OutputGroupsInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      string
+		expected string
+	}{
+		{
+			name: "invalid mode",
+			cfg: `
+[output_groups.backend]
+  mode = "random"
+  outputs = ["primary"]
+`,
+			expected: `invalid mode "random" for output group "backend"`,
+		},
+		{
+			name: "unknown option",
+			cfg: `
+[output_groups.backend]
+  mode = "hash"
+  outputs = ["primary"]
+  foo = "bar"
+`,
+			expected: `configuration specified the fields ["foo"], but they weren't used`,
+		},
+		{
+			name: "unknown output",
+			cfg: `
+[[outputs.http]]
+  alias = "primary"
+
+[output_groups.backend]
+  mode = "hash"
+  outputs = ["primary", "secondary"]
+`,
+			expected: `output group "backend": no output with alias "secondary"`,
+		},
+		{
+			name: "multiple groups",
+			cfg: `
+[[outputs.http]]
+  alias = "primary"
+
+[output_groups.a]
+  mode = "hash"
+  outputs = ["primary"]
+
+[output_groups.b]
+  mode = "failover"
+  outputs = ["primary"]
+`,
+			expected: `is member of output groups`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			err := c.LoadConfigData([]byte(tt.cfg))
+			if err == nil {
+				err = c.checkOutputGroups()
+			}
+			require.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
 func TestConfig_URLRetries3Fails(t *testing.T) {
 	httpLoadConfigRetryInterval = 0 * time.Second
 	responseCounter := 0
@@ -586,7 +826,7 @@ func TestConfig_getDefaultConfigPathFromEnvURL(t *testing.T) {
 
 	c := NewConfig()
 	t.Setenv("TELEGRAF_CONFIG_PATH", ts.URL)
-	configPath, err := getDefaultConfigPath()
+	configPath, err := GetDefaultConfigPath()
 	require.NoError(t, err)
 	require.Equal(t, []string{ts.URL}, configPath)
 	err = c.LoadConfig("")
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
@@ -18,6 +19,10 @@ import (
 	"github.com/influxdata/telegraf/plugins/processors"
 )
 
+// errDeprecated is returned when loading plugins or options deprecated beyond
+// their removal version.
+var errDeprecated = errors.New("deprecated")
+
 // DeprecationInfo contains all important information to describe a deprecated entity
 type DeprecationInfo struct {
 	// Name of the plugin or plugin option
@@ -171,7 +176,7 @@ func (c *Config) printUserDeprecation(category, name string, plugin interface{})
 	models.PrintPluginDeprecationNotice(info.LogLevel, info.Name, info.info)
 
 	if info.LogLevel == telegraf.Error {
-		return fmt.Errorf("plugin deprecated")
+		return fmt.Errorf("plugin %w", errDeprecated)
 	}
 
 	// Print deprecated options
@@ -184,7 +189,7 @@ func (c *Config) printUserDeprecation(category, name string, plugin interface{})
 	}
 
 	if len(deprecatedOptions) > 0 {
-		return fmt.Errorf("plugin options %q deprecated", strings.Join(deprecatedOptions, ","))
+		return fmt.Errorf("plugin options %q %w", strings.Join(deprecatedOptions, ","), errDeprecated)
 	}
 
 	return nil
@@ -0,0 +1,505 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/template"
+	"github.com/compose-spec/compose-go/utils"
+	"github.com/influxdata/toml"
+	"github.com/influxdata/toml/ast"
+
+	"github.com/influxdata/telegraf/plugins/aggregators"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/influxdata/telegraf/plugins/parsers"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+// optionMigration describes the replacement of a deprecated plugin option.
+type optionMigration struct {
+	// Name of the replacing option
+	replacement string
+	// Wrap the value into a list as the replacing option accepts multiple values
+	list bool
+}
+
+// pluginRenames maps deprecated plugins to their successors accepting the same
+// options.
+var pluginRenames = map[string]string{
+	"inputs.KNXListener":          "inputs.knx_listener",
+	"inputs.cisco_telemetry_gnmi": "inputs.gnmi",
+	"inputs.http_listener":        "inputs.influxdb_listener",
+	"inputs.io":                   "inputs.diskio",
+}
+
+// optionMigrations maps deprecated plugin options to their replacements.
+// Options listed for the empty plugin name apply to all plugins.
+var optionMigrations = map[string]map[string]optionMigration{
+	"": {
+		"ssl_ca":   {replacement: "tls_ca"},
+		"ssl_cert": {replacement: "tls_cert"},
+		"ssl_key":  {replacement: "tls_key"},
+	},
+	"inputs.aerospike":        {"enable_ssl": {replacement: "enable_tls"}},
+	"inputs.amqp_consumer":    {"url": {replacement: "brokers", list: true}},
+	"inputs.cloudwatch":       {"namespace": {replacement: "namespaces", list: true}},
+	"inputs.consul":           {"datacentre": {replacement: "datacenter"}},
+	"inputs.disk":             {"mountpoints": {replacement: "mount_points"}},
+	"inputs.docker":           {"container_names": {replacement: "container_name_include"}},
+	"inputs.filecount":        {"directory": {replacement: "directories", list: true}},
+	"inputs.http_listener_v2": {"path": {replacement: "paths", list: true}},
+	"inputs.http_response":    {"address": {replacement: "urls", list: true}},
+	"inputs.icinga2":          {"object_type": {replacement: "objects", list: true}},
+	"inputs.nsq_consumer":     {"server": {replacement: "nsqd", list: true}},
+	"inputs.openldap":         {"ssl": {replacement: "tls"}},
+	"inputs.prometheus":       {"response_timeout": {replacement: "timeout"}},
+	"inputs.rabbitmq":         {"queues": {replacement: "queue_name_include"}},
+	"inputs.smart":            {"path": {replacement: "path_smartctl"}},
+	"inputs.statsd":           {"parse_data_dog_tags": {replacement: "datadog_extensions"}},
+	"inputs.zookeeper":        {"enable_ssl": {replacement: "enable_tls"}},
+	"outputs.amqp":            {"url": {replacement: "brokers", list: true}},
+	"outputs.influxdb":        {"url": {replacement: "urls", list: true}},
+}
+
+// tableMigrations maps deprecated sub-tables of all plugins, e.g. of parsers,
+// to their replacements.
+var tableMigrations = map[string]string{
+	"xml":            "xpath",
+	"xpath_json":     "xpath",
+	"xpath_msgpack":  "xpath",
+	"xpath_protobuf": "xpath",
+}
+
+// envPattern matches the references to environment variables replaced by
+// template.Substitute when loading the configuration.
+var envPattern = regexp.MustCompile(
+	`\$(?i:(?P<escaped>\$)|(?P<named>[_a-z][_a-z0-9]*)|{(?:(?P<braced>[_a-z][_a-z0-9]*(?::?[-+?](.*}|[^}]*))?)}|(?P<invalid>)))`,
+)
+
+// envSpan is a reference to an environment variable in the original
+// configuration and its substituted value in the parsed configuration.
+type envSpan struct {
+	begin      int
+	end        int
+	valueBegin int
+	valueEnd   int
+}
+
+// textEdit replaces the runes between begin and end of the configuration.
+type textEdit struct {
+	begin int
+	end   int
+	text  string
+}
+
+// migrator collects the edits required to migrate a configuration. Positions
+// refer to the configuration with environment variables substituted, the edits
+// are applied to the original configuration.
+type migrator struct {
+	cfg     *Config
+	src     []rune
+	orig    []rune
+	spans   []envSpan
+	edits   []textEdit
+	headers map[int]textEdit
+	applied []string
+}
+
+// MigrateConfigData rewrites deprecated plugins and options in the given TOML
+// configuration into their replacements where a mapping exists. All other
+// parts of the configuration including comments are kept unchanged. Options
+// ignored by the plugins are removed. Deprecated settings which cannot be
+// migrated automatically are logged. The function returns the migrated
+// configuration and a description of each applied migration.
+func MigrateConfigData(data []byte) ([]byte, []string, error) {
+	data = trimBOM(data)
+	orig := []rune(string(data))
+	src, spans := substituteEnvironmentSpans(orig)
+	root, err := toml.Parse([]byte(string(src)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing configuration failed: %w", err)
+	}
+
+	m := &migrator{
+		cfg:     NewConfig(),
+		src:     src,
+		orig:    orig,
+		spans:   spans,
+		headers: make(map[int]textEdit),
+	}
+	for _, category := range []string{"inputs", "outputs", "processors", "aggregators"} {
+		val, found := root.Fields[category]
+		if !found {
+			continue
+		}
+		categoryTbl, ok := val.(*ast.Table)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid configuration: %q is not a table", category)
+		}
+
+		names := make([]string, 0, len(categoryTbl.Fields))
+		for name := range categoryTbl.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			tables, err := asTables(categoryTbl.Fields[name])
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid configuration of %s.%s: %w", category, name, err)
+			}
+			for _, tbl := range tables {
+				if err := m.migratePlugin(category, name, tbl); err != nil {
+					return nil, nil, fmt.Errorf("migrating %s.%s in line %d failed: %w", category, name, tbl.Line, err)
+				}
+			}
+		}
+	}
+
+	return []byte(m.apply()), m.applied, nil
+}
+
+// substituteEnvironmentSpans replaces the references to environment variables
+// like the configuration loader does and returns the positions of the
+// references to map the substituted configuration back to the original one.
+// References failing to substitute are kept.
+func substituteEnvironmentSpans(orig []rune) ([]rune, []envSpan) {
+	envMap := utils.GetAsEqualsMap(os.Environ())
+	mapping := func(k string) (string, bool) {
+		v, ok := envMap[k]
+		return v, ok
+	}
+
+	text := string(orig)
+	src := make([]rune, 0, len(orig))
+	var spans []envSpan
+	var last, pos int
+	for _, match := range envPattern.FindAllStringIndex(text, -1) {
+		before := []rune(text[last:match[0]])
+		src = append(src, before...)
+		pos += len(before)
+
+		ref := text[match[0]:match[1]]
+		value, err := template.Substitute(ref, mapping)
+		if err != nil {
+			value = ref
+		}
+		span := envSpan{
+			begin:      pos,
+			end:        pos + len([]rune(ref)),
+			valueBegin: len(src),
+		}
+		src = append(src, []rune(value)...)
+		span.valueEnd = len(src)
+		spans = append(spans, span)
+
+		pos = span.end
+		last = match[1]
+	}
+	src = append(src, []rune(text[last:])...)
+	return src, spans
+}
+
+// origPos maps a position in the substituted configuration to the original
+// configuration. Positions within a substituted value map to the begin of the
+// reference.
+func (m *migrator) origPos(pos int) int {
+	offset := 0
+	for _, span := range m.spans {
+		if pos < span.valueBegin {
+			break
+		}
+		if pos < span.valueEnd {
+			return span.begin
+		}
+		offset = span.end - span.valueEnd
+	}
+	return pos + offset
+}
+
+// origText returns the original text between the given positions of the
+// substituted configuration.
+func (m *migrator) origText(begin, end int) string {
+	return string(m.orig[m.origPos(begin):m.origPos(end)])
+}
+
+func asTables(val interface{}) ([]*ast.Table, error) {
+	switch v := val.(type) {
+	case *ast.Table:
+		return []*ast.Table{v}, nil
+	case []*ast.Table:
+		return v, nil
+	}
+	return nil, fmt.Errorf("unexpected type %T", val)
+}
+
+func (m *migrator) migratePlugin(category, name string, tbl *ast.Table) error {
+	pluginName := category + "." + name
+	if successor, found := pluginRenames[pluginName]; found {
+		if err := m.renameTable(tbl, pluginName, successor); err != nil {
+			return err
+		}
+		m.addApplied(tbl, "replaced plugin %q by %q", pluginName, successor)
+		pluginName = successor
+		name = strings.TrimPrefix(successor, category+".")
+	}
+
+	// Rename deprecated options and sub-tables
+	handled := make(map[string]bool)
+	for _, migrations := range []map[string]optionMigration{optionMigrations[""], optionMigrations[pluginName]} {
+		keys := make([]string, 0, len(migrations))
+		for key := range migrations {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			kv, ok := tbl.Fields[key].(*ast.KeyValue)
+			if !ok {
+				continue
+			}
+			handled[key] = true
+
+			migration := migrations[key]
+			if _, exists := tbl.Fields[migration.replacement]; exists {
+				if err := m.removeOption(kv); err != nil {
+					return err
+				}
+				m.addApplied(tbl, "removed option %q of %q superseded by %q", key, pluginName, migration.replacement)
+				continue
+			}
+			if err := m.renameOption(kv, migration); err != nil {
+				return err
+			}
+			m.addApplied(tbl, "replaced option %q of %q by %q", key, pluginName, migration.replacement)
+		}
+	}
+	tableKeys := make([]string, 0, len(tableMigrations))
+	for key := range tableMigrations {
+		tableKeys = append(tableKeys, key)
+	}
+	sort.Strings(tableKeys)
+	for _, key := range tableKeys {
+		val, found := tbl.Fields[key]
+		if !found {
+			continue
+		}
+		replacement := tableMigrations[key]
+		subtables, err := asTables(val)
+		if err != nil {
+			return fmt.Errorf("invalid sub-table %q: %w", key, err)
+		}
+		handled[key] = true
+
+		for _, subtbl := range subtables {
+			if err := m.renameTable(subtbl, pluginName+"."+key, pluginName+"."+replacement); err != nil {
+				return err
+			}
+		}
+		m.addApplied(tbl, "replaced sub-table %q of %q by %q", key, pluginName, replacement)
+	}
+
+	// Remove the options ignored by the plugin and report all deprecated
+	// settings left over
+	for _, info := range m.deprecationInfos(category, name, tbl) {
+		if info.info.Since != "" {
+			log.Printf("W! Plugin %q in line %d is deprecated and must be migrated manually: %s",
+				info.Name, tbl.Line, info.info.Notice)
+		}
+
+		for _, option := range info.Options {
+			if handled[option.Name] {
+				continue
+			}
+			kv, ok := tbl.Fields[option.Name].(*ast.KeyValue)
+			if !ok {
+				continue
+			}
+			handled[option.Name] = true
+
+			notice := option.info.Notice
+			if strings.HasSuffix(notice, "option is ignored") || notice == "unused option" {
+				if err := m.removeOption(kv); err != nil {
+					return err
+				}
+				m.addApplied(tbl, "removed ignored option %q of %q", option.Name, pluginName)
+				continue
+			}
+			log.Printf("W! Option %q of plugin %q in line %d is deprecated and must be migrated manually: %s",
+				option.Name, pluginName, kv.Line, notice)
+		}
+	}
+
+	return nil
+}
+
+// deprecationInfos returns the deprecation information of the given plugin
+// and of its parser if any.
+func (m *migrator) deprecationInfos(category, name string, tbl *ast.Table) []PluginDeprecationInfo {
+	var plugin interface{}
+	switch category {
+	case "inputs":
+		if creator, found := inputs.Inputs[name]; found {
+			plugin = creator()
+		}
+	case "outputs":
+		if creator, found := outputs.Outputs[name]; found {
+			plugin = creator()
+		}
+	case "processors":
+		if creator, found := processors.Processors[name]; found {
+			p := creator()
+			if up, ok := p.(unwrappable); ok {
+				plugin = up.Unwrap()
+			} else {
+				plugin = p
+			}
+		}
+	case "aggregators":
+		if creator, found := aggregators.Aggregators[name]; found {
+			plugin = creator()
+		}
+	}
+	infos := []PluginDeprecationInfo{m.cfg.collectDeprecationInfo(category, name, plugin, true)}
+
+	if kv, ok := tbl.Fields["data_format"].(*ast.KeyValue); ok {
+		if format, ok := kv.Value.(*ast.String); ok {
+			if creator, found := parsers.Parsers[format.Value]; found {
+				infos = append(infos, m.cfg.collectDeprecationInfo("parsers", format.Value, creator(""), true))
+			}
+		}
+	}
+
+	return infos
+}
+
+func (m *migrator) addApplied(tbl *ast.Table, format string, args ...interface{}) {
+	m.applied = append(m.applied, fmt.Sprintf("line %d: ", tbl.Line)+fmt.Sprintf(format, args...))
+}
+
+// renameOption replaces the key of the given option and wraps its value into a
+// list if required.
+func (m *migrator) renameOption(kv *ast.KeyValue, migration optionMigration) error {
+	begin, end, err := m.findKey(kv)
+	if err != nil {
+		return err
+	}
+	m.edits = append(m.edits, textEdit{begin: begin, end: end, text: migration.replacement})
+
+	if _, isArray := kv.Value.(*ast.Array); migration.list && !isArray {
+		value := m.origText(kv.Value.Pos(), kv.Value.End())
+		m.edits = append(m.edits, textEdit{begin: kv.Value.Pos(), end: kv.Value.End(), text: "[" + value + "]"})
+	}
+	return nil
+}
+
+// removeOption removes the lines of the given option.
+func (m *migrator) removeOption(kv *ast.KeyValue) error {
+	begin, _, err := m.findKey(kv)
+	if err != nil {
+		return err
+	}
+
+	// Remove the indentation and everything up to the next line
+	for begin > 0 && (m.src[begin-1] == ' ' || m.src[begin-1] == '\t') {
+		begin--
+	}
+	end := kv.Value.End()
+	for end < len(m.src) && m.src[end] != '\n' {
+		end++
+	}
+	if end < len(m.src) {
+		end++
+	}
+	m.edits = append(m.edits, textEdit{begin: begin, end: end})
+	return nil
+}
+
+// findKey returns the position of the key of the given option.
+func (m *migrator) findKey(kv *ast.KeyValue) (begin, end int, err error) {
+	pos := kv.Value.Pos() - 1
+	for pos >= 0 && (m.src[pos] == ' ' || m.src[pos] == '\t') {
+		pos--
+	}
+	if pos < 0 || m.src[pos] != '=' {
+		return 0, 0, fmt.Errorf("cannot locate option %q in line %d", kv.Key, kv.Line)
+	}
+	pos--
+	for pos >= 0 && (m.src[pos] == ' ' || m.src[pos] == '\t') {
+		pos--
+	}
+
+	end = pos + 1
+	begin = end - len([]rune(kv.Key))
+	if m.src[pos] == '"' || m.src[pos] == '\'' {
+		begin -= 2
+	}
+	if begin < 0 || strings.Trim(string(m.src[begin:end]), `"'`) != kv.Key {
+		return 0, 0, fmt.Errorf("cannot locate option %q in line %d", kv.Key, kv.Line)
+	}
+	return begin, end, nil
+}
+
+// renameTable replaces the given name in the headers of the table and all its
+// sub-tables. The name is matched against the already renamed headers.
+func (m *migrator) renameTable(tbl *ast.Table, name, replacement string) error {
+	// Implicitly defined tables do not have a header
+	if tbl.Position.End > tbl.Position.Begin {
+		header, found := m.headers[tbl.Position.Begin]
+		if !found {
+			end := tbl.Position.Begin
+			for end < len(m.src) && m.src[end] != '\n' {
+				end++
+			}
+			header = textEdit{
+				begin: tbl.Position.Begin,
+				end:   end,
+				text:  m.origText(tbl.Position.Begin, end),
+			}
+		}
+
+		re := regexp.MustCompile(`^(\s*\[\[?\s*)` + regexp.QuoteMeta(name) + `(\s*\]|\.)`)
+		if !re.MatchString(header.text) {
+			return fmt.Errorf("cannot locate header of table %q in line %d", name, tbl.Line)
+		}
+		header.text = re.ReplaceAllString(header.text, "${1}"+replacement+"${2}")
+		m.headers[tbl.Position.Begin] = header
+	}
+
+	for key, val := range tbl.Fields {
+		subtables, err := asTables(val)
+		if err != nil {
+			continue
+		}
+		for _, subtbl := range subtables {
+			if err := m.renameTable(subtbl, name+"."+key, replacement+"."+key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// apply returns the configuration with all edits applied.
+func (m *migrator) apply() string {
+	for _, header := range m.headers {
+		m.edits = append(m.edits, header)
+	}
+	sort.Slice(m.edits, func(i, j int) bool { return m.edits[i].begin > m.edits[j].begin })
+
+	src := m.orig
+	for _, edit := range m.edits {
+		begin, end := m.origPos(edit.begin), m.origPos(edit.end)
+		replaced := make([]rune, 0, len(src)-(end-begin)+len(edit.text))
+		replaced = append(replaced, src[:begin]...)
+		replaced = append(replaced, []rune(edit.text)...)
+		replaced = append(replaced, src[end:]...)
+		src = replaced
+	}
+	return string(src)
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+type MockupMigrationPlugin struct {
+	Ignored string `toml:"ignored" deprecated:"1.0.0;option is ignored"`
+	Manual  string `toml:"manual" deprecated:"1.0.0;use 'other' instead"`
+	tls.ClientConfig
+}
+
+func (m *MockupMigrationPlugin) SampleConfig() string {
+	return "Mockup test plugin"
+}
+
+func (m *MockupMigrationPlugin) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+func init() {
+	inputs.Add("migration_test", func() telegraf.Input {
+		return &MockupMigrationPlugin{}
+	})
+}
+
+func TestMigrateConfigData(t *testing.T) {
+	data, err := os.ReadFile("testdata/migration.toml")
+	require.NoError(t, err)
+	expected, err := os.ReadFile("testdata/migration_expected.toml")
+	require.NoError(t, err)
+
+	actual, applied, err := MigrateConfigData(data)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+	require.Equal(t, []string{
+		`line 12: replaced sub-table "xml" of "inputs.file" by "xpath"`,
+		`line 6: replaced plugin "inputs.io" by "inputs.diskio"`,
+		`line 21: replaced option "ssl_ca" of "inputs.migration_test" by "tls_ca"`,
+		`line 21: removed ignored option "ignored" of "inputs.migration_test"`,
+		`line 26: removed option "ssl_ca" of "outputs.influxdb" superseded by "tls_ca"`,
+		`line 26: replaced option "url" of "outputs.influxdb" by "urls"`,
+	}, applied)
+
+	// The migrated configuration must not require further migrations
+	_, applied, err = MigrateConfigData(actual)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+}
+
+func TestMigrateConfigDataUnchanged(t *testing.T) {
+	data, err := os.ReadFile("testdata/single_plugin.toml")
+	require.NoError(t, err)
+
+	actual, applied, err := MigrateConfigData(data)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+	require.Equal(t, string(data), string(actual))
+}
+
+func TestMigrateConfigDataInvalid(t *testing.T) {
+	_, _, err := MigrateConfigData([]byte("[[inputs.file]\n"))
+	require.ErrorContains(t, err, "parsing configuration failed")
+}
+
+func TestMigrateConfigDataEnvironment(t *testing.T) {
+	t.Setenv("PERCPU", "true")
+	t.Setenv("MANUAL", `"a value longer than the reference"`)
+	t.Setenv("INFLUX_URL", "http://localhost:8086")
+
+	data := `
+[[inputs.cpu]]
+  percpu = ${PERCPU}
+
+[[inputs.migration_test]]
+  manual = ${MANUAL}
+  ssl_ca = "/etc/telegraf/ca.pem"
+
+[[outputs.influxdb]]
+  url = "${INFLUX_URL}"
+`
+	expected := `
+[[inputs.cpu]]
+  percpu = ${PERCPU}
+
+[[inputs.migration_test]]
+  manual = ${MANUAL}
+  tls_ca = "/etc/telegraf/ca.pem"
+
+[[outputs.influxdb]]
+  urls = ["${INFLUX_URL}"]
+`
+
+	// References to environment variables are kept
+	actual, applied, err := MigrateConfigData([]byte(data))
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	require.Equal(t, expected, string(actual))
+}
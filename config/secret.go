@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/awnumar/memguard"
@@ -32,6 +33,9 @@ var secretCount atomic.Int64
 type Secret struct {
 	enclave   *memguard.Enclave
 	resolvers map[string]telegraf.ResolveFunc
+	// rotators contains the functions to refresh references to rotatable
+	// secrets of secret-stores.
+	rotators map[string]func() error
 	// unlinked contains all references in the secret that are not yet
 	// linked to the corresponding secret store.
 	unlinked []string
@@ -75,11 +79,13 @@ func (s *Secret) init(secret []byte) {
 	// Setup the enclave
 	s.enclave = memguard.NewEnclave(secret)
 	s.resolvers = nil
+	s.rotators = nil
 }
 
 // Destroy the secret content
 func (s *Secret) Destroy() {
 	s.resolvers = nil
+	s.rotators = nil
 	s.unlinked = nil
 	s.notempty = false
 
@@ -189,6 +195,27 @@ func (s *Secret) Set(value []byte) error {
 	return nil
 }
 
+// Rotate refreshes all references to rotatable secrets, so the current values
+// are used the next time the secret is accessed. This should be called if
+// authentication using the secret failed. The returned flag is false if the
+// secret does not contain any rotatable references.
+func (s *Secret) Rotate() (bool, error) {
+	if len(s.rotators) == 0 {
+		return false, nil
+	}
+
+	errs := make([]string, 0)
+	for ref, rotate := range s.rotators {
+		if err := rotate(); err != nil {
+			errs = append(errs, fmt.Sprintf("refreshing %q failed: %v", ref, err))
+		}
+	}
+	if len(errs) > 0 {
+		return true, fmt.Errorf("rotating secret failed: %s", strings.Join(errs, ";"))
+	}
+	return true, nil
+}
+
 // GetUnlinked return the parts of the secret that is not yet linked to a resolver
 func (s *Secret) GetUnlinked() []string {
 	return s.unlinked
@@ -256,6 +283,54 @@ func resolve(secret []byte, resolvers map[string]telegraf.ResolveFunc) ([]byte,
 	return newsecret, remaining, replaceErrs
 }
 
+// rotatingResolver resolves a rotatable secret once and caches the value until
+// refreshed. It is handled as dynamic resolver to keep the reference in the
+// secret instead of replacing it with the current value.
+type rotatingResolver struct {
+	store    telegraf.RotatableSecretStore
+	key      string
+	resolver telegraf.ResolveFunc
+
+	sync.Mutex
+	resolved bool
+	value    *memguard.Enclave
+}
+
+func (r *rotatingResolver) resolve() ([]byte, bool, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.resolved {
+		value, _, err := r.resolver()
+		if err != nil {
+			return nil, true, err
+		}
+		// Copy the value as the enclave wipes the given buffer
+		r.value = memguard.NewEnclave(append([]byte{}, value...))
+		r.resolved = true
+	}
+
+	// Empty secrets are not stored
+	if r.value == nil {
+		return nil, true, nil
+	}
+	lockbuf, err := r.value.Open()
+	if err != nil {
+		return nil, true, fmt.Errorf("opening enclave failed: %w", err)
+	}
+	defer lockbuf.Destroy()
+	return append([]byte{}, lockbuf.Bytes()...), true, nil
+}
+
+func (r *rotatingResolver) refresh() error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.resolved = false
+	r.value = nil
+	return r.store.Refresh(r.key)
+}
+
 func splitLink(s string) (storeid string, key string) {
 	// There should _ALWAYS_ be two parts due to the regular expression match
 	parts := strings.SplitN(s[2:len(s)-1], ":", 2)
@@ -558,6 +558,65 @@ func TestSecretStoreDynamic(t *testing.T) {
 	}
 }
 
+func TestSecretStoreRotatable(t *testing.T) {
+	defer func() { unlinkedSecrets = make([]*Secret, 0) }()
+
+	cfg := []byte(
+		`
+[[inputs.mockup]]
+	secret = "@{mock:user}:@{mock:password}"
+`)
+
+	c := NewConfig()
+	require.NoError(t, c.LoadConfigData(cfg))
+	require.Len(t, c.Inputs, 1)
+
+	// Create a mockup secretstore with only the password being rotatable
+	store := &MockupRotatableSecretStore{
+		MockupSecretStore: MockupSecretStore{
+			Secrets: map[string][]byte{
+				"user":     []byte("Ood Bnar"),
+				"password": []byte("Thon"),
+			},
+		},
+		RotatableKeys: []string{"password"},
+	}
+	require.NoError(t, store.Init())
+	c.SecretStores["mock"] = store
+	require.NoError(t, c.LinkSecrets())
+
+	plugin := c.Inputs[0].Input.(*MockupSecretPlugin)
+	secret, err := plugin.Secret.Get()
+	require.NoError(t, err)
+	require.EqualValues(t, "Ood Bnar:Thon", secret)
+	ReleaseSecret(secret)
+
+	// The secret should not change until rotated
+	store.Secrets["user"] = []byte("Obi-Wan Kenobi")
+	store.Secrets["password"] = []byte("Arca Jeth")
+	secret, err = plugin.Secret.Get()
+	require.NoError(t, err)
+	require.EqualValues(t, "Ood Bnar:Thon", secret)
+	ReleaseSecret(secret)
+
+	rotated, err := plugin.Secret.Rotate()
+	require.NoError(t, err)
+	require.True(t, rotated)
+	require.Equal(t, []string{"password"}, store.Refreshed)
+
+	secret, err = plugin.Secret.Get()
+	require.NoError(t, err)
+	require.EqualValues(t, "Ood Bnar:Arca Jeth", secret)
+	ReleaseSecret(secret)
+}
+
+func TestSecretRotateStatic(t *testing.T) {
+	secret := NewSecret([]byte("a secret"))
+	rotated, err := secret.Rotate()
+	require.NoError(t, err)
+	require.False(t, rotated)
+}
+
 func TestSecretStoreDeclarationMissingID(t *testing.T) {
 	defer func() { unlinkedSecrets = make([]*Secret, 0) }()
 
@@ -743,6 +802,26 @@ func (s *MockupSecretStore) GetResolver(key string) (telegraf.ResolveFunc, error
 	}, nil
 }
 
+type MockupRotatableSecretStore struct {
+	MockupSecretStore
+	RotatableKeys []string
+	Refreshed     []string
+}
+
+func (s *MockupRotatableSecretStore) Rotatable(key string) bool {
+	for _, k := range s.RotatableKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MockupRotatableSecretStore) Refresh(key string) error {
+	s.Refreshed = append(s.Refreshed, key)
+	return nil
+}
+
 // Register the mockup plugin on loading
 func init() {
 	// Register the mockup input plugin for the required names
@@ -0,0 +1,83 @@
+package telegraf
+
+// HistogramValue is a field value holding a complete histogram. Histograms
+// either consist of explicit buckets, of exponential buckets or of both.
+type HistogramValue struct {
+	// Count is the total number of observations.
+	Count uint64
+	// Sum is the sum of all observed values.
+	Sum float64
+	// Buckets contains the explicit buckets sorted by their upper bound.
+	// The counts are cumulative, i.e. include the counts of all buckets
+	// with smaller bounds.
+	Buckets []Bucket
+	// Exponential contains the sparse, exponential buckets if any.
+	Exponential *ExponentialBuckets
+}
+
+// Bucket is an explicit histogram bucket with its cumulative count.
+type Bucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// ExponentialBuckets are histogram buckets with exponentially growing
+// boundaries as used by Prometheus native histograms and OpenTelemetry
+// exponential histograms. The boundaries are powers of
+// base = 2^(2^-Schema) and the bucket with index i covers the range
+// (base^i, base^(i+1)] for positive or [-base^(i+1), -base^i) for negative
+// values.
+type ExponentialBuckets struct {
+	Schema        int32
+	ZeroThreshold float64
+	ZeroCount     uint64
+	Positive      BucketRange
+	Negative      BucketRange
+}
+
+// BucketRange is a dense range of non-cumulative exponential bucket counts
+// starting at the bucket with index Offset.
+type BucketRange struct {
+	Offset int32
+	Counts []uint64
+}
+
+// SummaryValue is a field value holding a complete summary.
+type SummaryValue struct {
+	// Count is the total number of observations.
+	Count uint64
+	// Sum is the sum of all observed values.
+	Sum float64
+	// Quantiles contains the quantiles sorted by their rank.
+	Quantiles []Quantile
+}
+
+// Quantile is the value of a summary at the given rank between 0 and 1.
+type Quantile struct {
+	Quantile float64
+	Value    float64
+}
+
+// Copy returns a deep copy of the histogram.
+func (h *HistogramValue) Copy() *HistogramValue {
+	c := &HistogramValue{Count: h.Count, Sum: h.Sum}
+	if h.Buckets != nil {
+		c.Buckets = append(make([]Bucket, 0, len(h.Buckets)), h.Buckets...)
+	}
+	if h.Exponential != nil {
+		e := *h.Exponential
+		e.Positive.Counts = append([]uint64(nil), e.Positive.Counts...)
+		e.Negative.Counts = append([]uint64(nil), e.Negative.Counts...)
+		c.Exponential = &e
+	}
+	return c
+}
+
+// Copy returns a deep copy of the summary.
+func (s *SummaryValue) Copy() *SummaryValue {
+	c := &SummaryValue{Count: s.Count, Sum: s.Sum}
+	if s.Quantiles != nil {
+		c.Quantiles = append(make([]Quantile, 0, len(s.Quantiles)), s.Quantiles...)
+	}
+	return c
+}
@@ -0,0 +1,226 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// The acknowledgement protocol between the execd plugins and external
+// processes uses comment lines of the influx line protocol. Every metric
+// written to the process is preceded by an ID line and the process reports
+// the result for the ID with an ack or nack line, the latter optionally
+// followed by an error message. Metrics emitted by a processor after an ID
+// line are derived from the metric with that ID.
+//
+//	#telegraf:id=42
+//	#telegraf:ack=42
+//	#telegraf:nack=42 reason
+const (
+	ackIDPrefix   = "#telegraf:id="
+	ackPrefix     = "#telegraf:ack="
+	ackNackPrefix = "#telegraf:nack="
+)
+
+// ErrAckTimeout is reported for metrics not acknowledged in time.
+var ErrAckTimeout = errors.New("acknowledgement timed out")
+
+// AckType distinguishes the lines of the acknowledgement protocol
+type AckType int
+
+const (
+	AckID AckType = iota
+	Ack
+	Nack
+)
+
+// AckMessage is a parsed line of the acknowledgement protocol
+type AckMessage struct {
+	Type    AckType
+	ID      uint64
+	Message string
+}
+
+// FormatAckID returns the line announcing the ID of the following metric.
+func FormatAckID(id uint64) []byte {
+	return []byte(ackIDPrefix + strconv.FormatUint(id, 10) + "\n")
+}
+
+// FormatAck returns the line reporting the result for the given ID.
+func FormatAck(id uint64, err error) []byte {
+	if err == nil {
+		return []byte(ackPrefix + strconv.FormatUint(id, 10) + "\n")
+	}
+	return []byte(ackNackPrefix + strconv.FormatUint(id, 10) + " " + strconv.Quote(err.Error()) + "\n")
+}
+
+// ParseAck parses a line of the acknowledgement protocol. The second return
+// value is false for all other lines.
+func ParseAck(line []byte) (AckMessage, bool) {
+	line = bytes.TrimSpace(line)
+
+	var msg AckMessage
+	var value []byte
+	switch {
+	case bytes.HasPrefix(line, []byte(ackIDPrefix)):
+		msg.Type = AckID
+		value = line[len(ackIDPrefix):]
+	case bytes.HasPrefix(line, []byte(ackPrefix)):
+		msg.Type = Ack
+		value = line[len(ackPrefix):]
+	case bytes.HasPrefix(line, []byte(ackNackPrefix)):
+		msg.Type = Nack
+		value = line[len(ackNackPrefix):]
+		if idx := bytes.IndexByte(value, ' '); idx >= 0 {
+			msg.Message = string(bytes.TrimSpace(value[idx+1:]))
+			if unquoted, err := strconv.Unquote(msg.Message); err == nil {
+				msg.Message = unquoted
+			}
+			value = value[:idx]
+		}
+	default:
+		return msg, false
+	}
+
+	id, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return msg, false
+	}
+	msg.ID = id
+	return msg, true
+}
+
+// AckEntry is a metric waiting for its acknowledgement
+type AckEntry struct {
+	ID     uint64
+	Metric telegraf.Metric
+
+	added time.Time
+	done  chan struct{}
+	err   error
+}
+
+// Done returns a channel closed when the metric is acknowledged, rejected or
+// expired.
+func (e *AckEntry) Done() <-chan struct{} {
+	return e.done
+}
+
+// Err returns the reason for rejecting the metric, if any. It must only be
+// called after Done is closed.
+func (e *AckEntry) Err() error {
+	return e.err
+}
+
+// AckTracker keeps track of the metrics written to a process until they are
+// acknowledged.
+type AckTracker struct {
+	mu      sync.Mutex
+	next    uint64
+	pending map[uint64]*AckEntry
+}
+
+func NewAckTracker() *AckTracker {
+	return &AckTracker{pending: make(map[uint64]*AckEntry)}
+}
+
+// Add registers the metric and returns the entry with the ID to use.
+func (t *AckTracker) Add(m telegraf.Metric) *AckEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.next++
+	e := &AckEntry{
+		ID:     t.next,
+		Metric: m,
+		added:  time.Now(),
+		done:   make(chan struct{}),
+	}
+	t.pending[e.ID] = e
+	return e
+}
+
+// Derive returns a metric with the content of the given metric sharing the
+// tracking information of the pending metric with the given ID. This way the
+// pending metric is only delivered if all derived metrics are delivered.
+func (t *AckTracker) Derive(id uint64, m telegraf.Metric) (telegraf.Metric, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, found := t.pending[id]
+	if !found {
+		return nil, false
+	}
+
+	derived := e.Metric.Copy()
+	derived.SetName(m.Name())
+	derived.SetTime(m.Time())
+	for _, tag := range e.Metric.TagList() {
+		derived.RemoveTag(tag.Key)
+	}
+	for _, tag := range m.TagList() {
+		derived.AddTag(tag.Key, tag.Value)
+	}
+	for _, field := range e.Metric.FieldList() {
+		derived.RemoveField(field.Key)
+	}
+	for _, field := range m.FieldList() {
+		derived.AddField(field.Key, field.Value)
+	}
+	return derived, true
+}
+
+// Resolve removes the entry with the given ID, records the given error and
+// notifies the waiting callers. The entry is returned if it was pending.
+func (t *AckTracker) Resolve(id uint64, err error) (*AckEntry, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, found := t.pending[id]
+	if !found {
+		return nil, false
+	}
+	t.resolve(e, err)
+	return e, true
+}
+
+// Expire resolves all entries pending for longer than the given timeout with
+// ErrAckTimeout and returns them.
+func (t *AckTracker) Expire(timeout time.Duration) []*AckEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var expired []*AckEntry
+	deadline := time.Now().Add(-timeout)
+	for _, e := range t.pending {
+		if e.added.Before(deadline) {
+			t.resolve(e, ErrAckTimeout)
+			expired = append(expired, e)
+		}
+	}
+	return expired
+}
+
+// ResolveAll resolves all pending entries with the given error, e.g. if the
+// process terminated, and returns them.
+func (t *AckTracker) ResolveAll(err error) []*AckEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resolved := make([]*AckEntry, 0, len(t.pending))
+	for _, e := range t.pending {
+		t.resolve(e, err)
+		resolved = append(resolved, e)
+	}
+	return resolved
+}
+
+func (t *AckTracker) resolve(e *AckEntry, err error) {
+	delete(t.pending, e.ID)
+	e.err = err
+	close(e.done)
+}
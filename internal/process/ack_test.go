@@ -0,0 +1,88 @@
+package process
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+func TestAckLines(t *testing.T) {
+	msg, ok := ParseAck(FormatAckID(42))
+	require.True(t, ok)
+	require.Equal(t, AckMessage{Type: AckID, ID: 42}, msg)
+
+	msg, ok = ParseAck(FormatAck(42, nil))
+	require.True(t, ok)
+	require.Equal(t, AckMessage{Type: Ack, ID: 42}, msg)
+
+	msg, ok = ParseAck(FormatAck(42, errors.New("write failed: \"timeout\"")))
+	require.True(t, ok)
+	require.Equal(t, AckMessage{Type: Nack, ID: 42, Message: "write failed: \"timeout\""}, msg)
+
+	_, ok = ParseAck([]byte("cpu value=42 0\n"))
+	require.False(t, ok)
+	_, ok = ParseAck([]byte("# a comment\n"))
+	require.False(t, ok)
+	_, ok = ParseAck([]byte("#telegraf:ack=foo\n"))
+	require.False(t, ok)
+}
+
+func TestAckTracker(t *testing.T) {
+	var delivered telegraf.DeliveryInfo
+	m, _ := metric.WithTracking(
+		metric.New("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 42}, time.Unix(0, 0)),
+		func(di telegraf.DeliveryInfo) { delivered = di },
+	)
+
+	tracker := NewAckTracker()
+	entry := tracker.Add(m)
+
+	// Derived metrics keep the pending metric from being delivered
+	derived, found := tracker.Derive(entry.ID, metric.New("mem", map[string]string{}, map[string]interface{}{"free": 1}, time.Unix(1, 0)))
+	require.True(t, found)
+	require.Equal(t, "mem", derived.Name())
+	require.Empty(t, derived.TagList())
+	require.Equal(t, map[string]interface{}{"free": int64(1)}, derived.Fields())
+	require.Equal(t, time.Unix(1, 0), derived.Time())
+
+	resolved, found := tracker.Resolve(entry.ID, nil)
+	require.True(t, found)
+	resolved.Metric.Accept()
+	require.Nil(t, delivered)
+	derived.Accept()
+	require.NotNil(t, delivered)
+	require.True(t, delivered.Delivered())
+
+	select {
+	case <-entry.Done():
+	default:
+		require.Fail(t, "entry not done")
+	}
+	require.NoError(t, entry.Err())
+
+	_, found = tracker.Resolve(entry.ID, nil)
+	require.False(t, found)
+	_, found = tracker.Derive(entry.ID, m)
+	require.False(t, found)
+}
+
+func TestAckTrackerExpire(t *testing.T) {
+	m := metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 42}, time.Unix(0, 0))
+
+	tracker := NewAckTracker()
+	first := tracker.Add(m)
+	require.Empty(t, tracker.Expire(time.Hour))
+	time.Sleep(time.Millisecond)
+	require.Equal(t, []*AckEntry{first}, tracker.Expire(time.Nanosecond))
+	require.ErrorIs(t, first.Err(), ErrAckTimeout)
+
+	second := tracker.Add(m)
+	require.NotEqual(t, first.ID, second.ID)
+	require.Equal(t, []*AckEntry{second}, tracker.ResolveAll(errors.New("terminated")))
+	require.EqualError(t, second.Err(), "terminated")
+}
@@ -7,7 +7,6 @@ import (
 	"log"
 	"strings"
 
-	"github.com/influxdata/wlog"
 	"golang.org/x/sys/windows/svc/eventlog"
 )
 
@@ -23,6 +22,14 @@ type eventLogger struct {
 }
 
 func (t *eventLogger) Write(b []byte) (int, error) {
+	if e := parseEntry(b); !e.enabled() {
+		return len(b), nil
+	}
+	return t.WriteUnfiltered(b)
+}
+
+// WriteUnfiltered writes a message already filtered by the plugin's logger.
+func (t *eventLogger) WriteUnfiltered(b []byte) (int, error) {
 	var err error
 
 	loc := prefixRegex.FindIndex(b)
@@ -49,7 +56,7 @@ type eventLoggerCreator struct {
 }
 
 func (e *eventLoggerCreator) CreateLogger(config LogConfig) (io.Writer, error) {
-	return wlog.NewWriter(&eventLogger{logger: e.logger}), nil
+	return &eventLogger{logger: e.logger}, nil
 }
 
 func RegisterEventLogger(name string) error {
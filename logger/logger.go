@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"log"
@@ -22,6 +24,11 @@ const (
 	LogTargetStderr = "stderr"
 )
 
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 // LogConfig contains the log configuration settings
 type LogConfig struct {
 	// will set the log level to DEBUG
@@ -30,6 +37,8 @@ type LogConfig struct {
 	Quiet bool
 	//stderr, stdout, file or eventlog (Windows only)
 	LogTarget string
+	// text or json, ignored for the eventlog
+	LogFormat string
 	// will direct the logging output to a file. Empty string is
 	// interpreted as stderr. If there is an error opening the file the
 	// logger will fall back to stderr
@@ -57,16 +66,82 @@ func registerLogger(name string, loggerCreator creator) {
 	loggerRegistry[name] = loggerCreator
 }
 
+// entry is a log message split into its parts
+type entry struct {
+	level  wlog.Level
+	source string
+	msg    []byte
+}
+
+// parseEntry splits a message of the form "L! [source] message" where both
+// the level and the source are optional. Messages without a level are
+// treated as information.
+func parseEntry(b []byte) entry {
+	e := entry{level: wlog.INFO, msg: b}
+	if prefixRegex.Match(b) {
+		e.level = wlog.Levels[b[0]]
+		e.msg = bytes.TrimLeft(b[2:], " ")
+	}
+	if len(e.msg) > 0 && e.msg[0] == '[' {
+		if end := bytes.IndexByte(e.msg, ']'); end > 0 {
+			e.source = string(e.msg[1:end])
+		}
+	}
+	return e
+}
+
+// enabled returns true if the message should be logged according to the
+// global log level. Messages of plugins overriding the level are filtered by
+// the plugin's logger instead.
+func (e *entry) enabled() bool {
+	return e.level >= wlog.LogLevel()
+}
+
+// jsonEntry is the representation of a message in the "json" log format
+type jsonEntry struct {
+	Time     string `json:"time"`
+	Level    string `json:"level"`
+	Category string `json:"category,omitempty"`
+	Plugin   string `json:"plugin,omitempty"`
+	Alias    string `json:"alias,omitempty"`
+	Message  string `json:"msg"`
+}
+
+var levelNames = map[wlog.Level]string{
+	wlog.DEBUG: "debug",
+	wlog.INFO:  "info",
+	wlog.WARN:  "warn",
+	wlog.ERROR: "error",
+}
+
 type telegrafLog struct {
 	writer         io.Writer
 	internalWriter io.Writer
 	timezone       *time.Location
+	format         string
 }
 
 func (t *telegrafLog) Write(b []byte) (n int, err error) {
-	var line []byte
+	return t.write(b, true)
+}
+
+// WriteUnfiltered writes a message already filtered by the plugin's logger.
+func (t *telegrafLog) WriteUnfiltered(b []byte) (n int, err error) {
+	return t.write(b, false)
+}
+
+func (t *telegrafLog) write(b []byte, filter bool) (n int, err error) {
+	e := parseEntry(b)
+	if filter && !e.enabled() {
+		return len(b), nil
+	}
+
 	timeToPrint := time.Now().In(t.timezone)
+	if t.format == LogFormatJSON {
+		return len(b), t.writeJSON(timeToPrint, e)
+	}
 
+	var line []byte
 	if !prefixRegex.Match(b) {
 		line = append([]byte(timeToPrint.Format(time.RFC3339)+" I! "), b...)
 	} else {
@@ -76,6 +151,30 @@ func (t *telegrafLog) Write(b []byte) (n int, err error) {
 	return t.writer.Write(line)
 }
 
+func (t *telegrafLog) writeJSON(ts time.Time, e entry) error {
+	msg := e.msg
+	if e.source != "" {
+		msg = bytes.TrimLeft(msg[len(e.source)+2:], " ")
+	}
+	je := jsonEntry{
+		Time:    ts.Format(time.RFC3339),
+		Level:   levelNames[e.level],
+		Message: string(bytes.TrimRight(msg, " \t\r\n")),
+	}
+
+	// Sources are of the form "category.plugin::alias"
+	var plugin string
+	je.Category, plugin, _ = strings.Cut(e.source, ".")
+	je.Plugin, je.Alias, _ = strings.Cut(plugin, "::")
+
+	line, err := json.Marshal(je)
+	if err != nil {
+		return err
+	}
+	_, err = t.writer.Write(append(line, '\n'))
+	return err
+}
+
 func (t *telegrafLog) Close() error {
 	stdErrWriter := os.Stderr
 	// avoid closing stderr
@@ -103,10 +202,21 @@ func newTelegrafWriter(w io.Writer, c LogConfig) (io.Writer, error) {
 		return nil, errors.New("error while setting logging timezone: " + err.Error())
 	}
 
+	format := c.LogFormat
+	switch format {
+	case LogFormatText, LogFormatJSON:
+	case "":
+		format = LogFormatText
+	default:
+		log.Printf("E! Unsupported logformat: %s, using text", format)
+		format = LogFormatText
+	}
+
 	return &telegrafLog{
-		writer:         wlog.NewWriter(w),
+		writer:         w,
 		internalWriter: w,
 		timezone:       tz,
+		format:         format,
 	}, nil
 }
 
@@ -2,15 +2,18 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/influxdata/wlog"
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
 )
 
 func TestWriteLogToFile(t *testing.T) {
@@ -145,6 +148,70 @@ func TestLogTargetSettings(t *testing.T) {
 	require.Equal(t, logger.internalWriter, os.Stderr)
 }
 
+func TestWriteLogJSON(t *testing.T) {
+	wlog.SetLevel(wlog.INFO)
+
+	var buf bytes.Buffer
+	w, err := newTelegrafWriter(&buf, LogConfig{LogFormat: LogFormatJSON})
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("W! [inputs.cpu::mycpu] something happened\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("E! [agent] Error running agent\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("TEST\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("D! [outputs.file] ignored\n"))
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+
+	expected := []map[string]interface{}{
+		{"level": "warn", "category": "inputs", "plugin": "cpu", "alias": "mycpu", "msg": "something happened"},
+		{"level": "error", "category": "agent", "msg": "Error running agent"},
+		{"level": "info", "msg": "TEST"},
+	}
+	for i, line := range lines {
+		var actual map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &actual))
+		require.Contains(t, actual, "time")
+		delete(actual, "time")
+		require.Equal(t, expected[i], actual)
+	}
+}
+
+func TestPluginLogLevel(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+
+	cfg := createBasicLogConfig(tmpfile.Name())
+	require.NoError(t, SetupLogging(cfg))
+
+	// Instances of the same plugin keep their own log level
+	debugged := models.NewLogger("inputs", "test", "")
+	require.NoError(t, debugged.SetLogLevel("debug"))
+	silenced := models.NewLogger("inputs", "test", "")
+	require.NoError(t, silenced.SetLogLevel("error"))
+	other := models.NewLogger("inputs", "test", "")
+	require.NoError(t, other.SetLogLevel(""))
+
+	debugged.Debug("TEST 1")
+	other.Debug("TEST 2")   // <- should be ignored
+	silenced.Warn("TEST 3") // <- should be ignored
+	silenced.Error("TEST 4")
+	other.Info("TEST 5")
+
+	f, err := os.ReadFile(tmpfile.Name())
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(f), []byte("\n"))
+	require.Len(t, lines, 3)
+	require.Equal(t, []byte("Z D! [inputs.test] TEST 1"), lines[0][19:])
+	require.Equal(t, []byte("Z E! [inputs.test] TEST 4"), lines[1][19:])
+	require.Equal(t, []byte("Z I! [inputs.test] TEST 5"), lines[2][19:])
+}
+
 func BenchmarkTelegrafLogWrite(b *testing.B) {
 	var msg = []byte("test")
 	var buf bytes.Buffer
@@ -255,7 +255,7 @@ func (m *metric) Copy() telegraf.Metric {
 	}
 
 	for i, field := range m.fields {
-		m2.fields[i] = &telegraf.Field{Key: field.Key, Value: field.Value}
+		m2.fields[i] = &telegraf.Field{Key: field.Key, Value: copyValue(field.Value)}
 	}
 	return m2
 }
@@ -282,6 +282,17 @@ func (m *metric) Reject() {
 func (m *metric) Drop() {
 }
 
+// copyValue returns a deep copy of values referencing mutable data
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *telegraf.HistogramValue:
+		return v.Copy()
+	case *telegraf.SummaryValue:
+		return v.Copy()
+	}
+	return v
+}
+
 // Convert field to a supported type or nil if inconvertible
 func convertField(v interface{}) interface{} {
 	switch v := v.(type) {
@@ -375,6 +386,14 @@ func convertField(v interface{}) interface{} {
 		if v != nil {
 			return float64(*v)
 		}
+	case *telegraf.HistogramValue:
+		if v != nil {
+			return v
+		}
+	case *telegraf.SummaryValue:
+		if v != nil {
+			return v
+		}
 	default:
 		return nil
 	}
@@ -326,3 +326,40 @@ func TestValueType(t *testing.T) {
 
 	require.Equal(t, telegraf.Gauge, m.Type())
 }
+
+func TestDistributionValues(t *testing.T) {
+	histogram := &telegraf.HistogramValue{
+		Count:   3,
+		Sum:     4.5,
+		Buckets: []telegraf.Bucket{{UpperBound: 1, Count: 1}, {UpperBound: 5, Count: 3}},
+		Exponential: &telegraf.ExponentialBuckets{
+			Schema:   0,
+			Positive: telegraf.BucketRange{Offset: -1, Counts: []uint64{1, 2}},
+		},
+	}
+	summary := &telegraf.SummaryValue{
+		Count:     3,
+		Sum:       4.5,
+		Quantiles: []telegraf.Quantile{{Quantile: 0.5, Value: 1.5}},
+	}
+	m := New(
+		"request_duration",
+		map[string]string{"host": "localhost"},
+		map[string]interface{}{"histogram": histogram, "summary": summary},
+		time.Now(),
+	)
+	require.Same(t, histogram, m.Fields()["histogram"])
+	require.Same(t, summary, m.Fields()["summary"])
+
+	// Copies must not share the underlying data
+	m2 := m.Copy()
+	require.Equal(t, m, m2)
+	h2 := m2.Fields()["histogram"].(*telegraf.HistogramValue)
+	h2.Buckets[0].Count = 2
+	h2.Exponential.Positive.Counts[0] = 2
+	s2 := m2.Fields()["summary"].(*telegraf.SummaryValue)
+	s2.Quantiles[0].Value = 2
+	require.EqualValues(t, 1, histogram.Buckets[0].Count)
+	require.EqualValues(t, 1, histogram.Exponential.Positive.Counts[0])
+	require.EqualValues(t, 1.5, summary.Quantiles[0].Value)
+}
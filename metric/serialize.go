@@ -0,0 +1,299 @@
+package metric
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// Version of the binary representation written by ToBytes
+const serializeVersion = 1
+
+// Kinds of field values in the binary representation
+const (
+	kindInt64 byte = iota + 1
+	kindUint64
+	kindFloat64
+	kindString
+	kindBool
+	kindHistogram
+	kindSummary
+)
+
+var errTruncated = errors.New("unexpected end of data")
+
+// ToBytes encodes the given metric into a binary form that can be restored
+// using FromBytes. Any tracking information of the metric is not encoded.
+// Each encoded metric is self-contained and does not carry any type
+// information, so it is suited for storing single metrics e.g. in a
+// write-ahead log.
+func ToBytes(m telegraf.Metric) ([]byte, error) {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, serializeVersion, byte(m.Type()))
+	buf = appendString(buf, m.Name())
+	buf = binary.AppendVarint(buf, m.Time().UnixNano())
+
+	tags := m.TagList()
+	buf = binary.AppendUvarint(buf, uint64(len(tags)))
+	for _, tag := range tags {
+		buf = appendString(buf, tag.Key)
+		buf = appendString(buf, tag.Value)
+	}
+
+	fields := m.FieldList()
+	buf = binary.AppendUvarint(buf, uint64(len(fields)))
+	for _, field := range fields {
+		buf = appendString(buf, field.Key)
+
+		var err error
+		if buf, err = appendValue(buf, field.Value); err != nil {
+			return nil, fmt.Errorf("encoding field %q failed: %w", field.Key, err)
+		}
+	}
+	return buf, nil
+}
+
+// FromBytes decodes a metric previously encoded using ToBytes.
+func FromBytes(b []byte) (telegraf.Metric, error) {
+	d := &decoder{buf: b}
+
+	if version := d.byte(); version != serializeVersion {
+		if d.err != nil {
+			return nil, d.err
+		}
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+	m := &metric{tp: telegraf.ValueType(d.byte())}
+	m.name = d.string()
+	m.tm = time.Unix(0, d.varint())
+
+	if n := d.length(); n > 0 {
+		m.tags = make([]*telegraf.Tag, 0, n)
+		for i := 0; i < n && d.err == nil; i++ {
+			m.tags = append(m.tags, &telegraf.Tag{Key: d.string(), Value: d.string()})
+		}
+	}
+	if n := d.length(); n > 0 {
+		m.fields = make([]*telegraf.Field, 0, n)
+		for i := 0; i < n && d.err == nil; i++ {
+			key := d.string()
+			m.fields = append(m.fields, &telegraf.Field{Key: key, Value: d.value()})
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.buf) > 0 {
+		return nil, fmt.Errorf("%d trailing bytes", len(d.buf))
+	}
+	return m, nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendFloat(buf []byte, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+}
+
+func appendValue(buf []byte, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case int64:
+		buf = append(buf, kindInt64)
+		buf = binary.AppendVarint(buf, v)
+	case uint64:
+		buf = append(buf, kindUint64)
+		buf = binary.AppendUvarint(buf, v)
+	case float64:
+		buf = append(buf, kindFloat64)
+		buf = appendFloat(buf, v)
+	case string:
+		buf = append(buf, kindString)
+		buf = appendString(buf, v)
+	case bool:
+		buf = append(buf, kindBool)
+		if v {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+	case *telegraf.HistogramValue:
+		buf = append(buf, kindHistogram)
+		buf = binary.AppendUvarint(buf, v.Count)
+		buf = appendFloat(buf, v.Sum)
+		buf = binary.AppendUvarint(buf, uint64(len(v.Buckets)))
+		for _, bucket := range v.Buckets {
+			buf = appendFloat(buf, bucket.UpperBound)
+			buf = binary.AppendUvarint(buf, bucket.Count)
+		}
+		if v.Exponential == nil {
+			buf = append(buf, 0)
+			break
+		}
+		e := v.Exponential
+		buf = append(buf, 1)
+		buf = binary.AppendVarint(buf, int64(e.Schema))
+		buf = appendFloat(buf, e.ZeroThreshold)
+		buf = binary.AppendUvarint(buf, e.ZeroCount)
+		buf = appendBucketRange(buf, e.Positive)
+		buf = appendBucketRange(buf, e.Negative)
+	case *telegraf.SummaryValue:
+		buf = append(buf, kindSummary)
+		buf = binary.AppendUvarint(buf, v.Count)
+		buf = appendFloat(buf, v.Sum)
+		buf = binary.AppendUvarint(buf, uint64(len(v.Quantiles)))
+		for _, q := range v.Quantiles {
+			buf = appendFloat(buf, q.Quantile)
+			buf = appendFloat(buf, q.Value)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %T", value)
+	}
+	return buf, nil
+}
+
+func appendBucketRange(buf []byte, r telegraf.BucketRange) []byte {
+	buf = binary.AppendVarint(buf, int64(r.Offset))
+	buf = binary.AppendUvarint(buf, uint64(len(r.Counts)))
+	for _, c := range r.Counts {
+		buf = binary.AppendUvarint(buf, c)
+	}
+	return buf
+}
+
+// decoder reads the binary representation of a metric. After the first error
+// all reads return zero values and the error is kept.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.buf = nil
+}
+
+func (d *decoder) byte() byte {
+	if len(d.buf) < 1 {
+		d.fail(errTruncated)
+		return 0
+	}
+	v := d.buf[0]
+	d.buf = d.buf[1:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// length reads the number of following elements, each being at least one
+// byte long, and checks it against the remaining data.
+func (d *decoder) length() int {
+	n := d.uvarint()
+	if n > uint64(len(d.buf)) {
+		d.fail(errTruncated)
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) float() float64 {
+	if len(d.buf) < 8 {
+		d.fail(errTruncated)
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.buf))
+	d.buf = d.buf[8:]
+	return v
+}
+
+func (d *decoder) string() string {
+	n := d.length()
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
+
+func (d *decoder) value() interface{} {
+	switch kind := d.byte(); kind {
+	case kindInt64:
+		return d.varint()
+	case kindUint64:
+		return d.uvarint()
+	case kindFloat64:
+		return d.float()
+	case kindString:
+		return d.string()
+	case kindBool:
+		return d.byte() != 0
+	case kindHistogram:
+		h := &telegraf.HistogramValue{Count: d.uvarint(), Sum: d.float()}
+		if n := d.length(); n > 0 {
+			h.Buckets = make([]telegraf.Bucket, 0, n)
+			for i := 0; i < n && d.err == nil; i++ {
+				h.Buckets = append(h.Buckets, telegraf.Bucket{UpperBound: d.float(), Count: d.uvarint()})
+			}
+		}
+		if d.byte() != 0 {
+			h.Exponential = &telegraf.ExponentialBuckets{
+				Schema:        int32(d.varint()),
+				ZeroThreshold: d.float(),
+				ZeroCount:     d.uvarint(),
+				Positive:      d.bucketRange(),
+				Negative:      d.bucketRange(),
+			}
+		}
+		return h
+	case kindSummary:
+		s := &telegraf.SummaryValue{Count: d.uvarint(), Sum: d.float()}
+		if n := d.length(); n > 0 {
+			s.Quantiles = make([]telegraf.Quantile, 0, n)
+			for i := 0; i < n && d.err == nil; i++ {
+				s.Quantiles = append(s.Quantiles, telegraf.Quantile{Quantile: d.float(), Value: d.float()})
+			}
+		}
+		return s
+	default:
+		if d.err == nil {
+			d.fail(fmt.Errorf("unknown value kind %d", kind))
+		}
+		return nil
+	}
+}
+
+func (d *decoder) bucketRange() telegraf.BucketRange {
+	r := telegraf.BucketRange{Offset: int32(d.varint())}
+	if n := d.length(); n > 0 {
+		r.Counts = make([]uint64, 0, n)
+		for i := 0; i < n && d.err == nil; i++ {
+			r.Counts = append(r.Counts, d.uvarint())
+		}
+	}
+	return r
+}
@@ -0,0 +1,92 @@
+package metric
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+)
+
+func TestSerializeRoundTrip(t *testing.T) {
+	m := New(
+		"cpu",
+		map[string]string{"host": "localhost", "cpu": "cpu0"},
+		map[string]interface{}{
+			"float":    42.0,
+			"int":      int64(-42),
+			"uint":     uint64(42),
+			"string":   "value",
+			"bool":     true,
+			"negative": -1.5,
+		},
+		time.Unix(1700000000, 123),
+		telegraf.Counter,
+	)
+
+	buf, err := ToBytes(m)
+	require.NoError(t, err)
+
+	actual, err := FromBytes(buf)
+	require.NoError(t, err)
+	require.Equal(t, m.Name(), actual.Name())
+	require.Equal(t, m.Tags(), actual.Tags())
+	require.Equal(t, m.Fields(), actual.Fields())
+	require.Equal(t, m.Time().UnixNano(), actual.Time().UnixNano())
+	require.Equal(t, m.Type(), actual.Type())
+}
+
+func TestSerializeDistributions(t *testing.T) {
+	m := New(
+		"latency",
+		map[string]string{"host": "localhost"},
+		map[string]interface{}{
+			"histogram": &telegraf.HistogramValue{
+				Count:   5,
+				Sum:     12.5,
+				Buckets: []telegraf.Bucket{{UpperBound: 0.5, Count: 2}, {UpperBound: math.Inf(1), Count: 5}},
+				Exponential: &telegraf.ExponentialBuckets{
+					Schema:        -1,
+					ZeroThreshold: 1e-128,
+					ZeroCount:     1,
+					Positive:      telegraf.BucketRange{Offset: 3, Counts: []uint64{1, 2}},
+					Negative:      telegraf.BucketRange{Offset: -1, Counts: []uint64{1}},
+				},
+			},
+			"summary": &telegraf.SummaryValue{
+				Count:     5,
+				Sum:       12.5,
+				Quantiles: []telegraf.Quantile{{Quantile: 0.5, Value: 2}, {Quantile: 0.9, Value: 4}},
+			},
+		},
+		time.Unix(1700000000, 0),
+		telegraf.Histogram,
+	)
+
+	buf, err := ToBytes(m)
+	require.NoError(t, err)
+
+	actual, err := FromBytes(buf)
+	require.NoError(t, err)
+	require.Equal(t, m.Fields(), actual.Fields())
+	require.Equal(t, telegraf.Histogram, actual.Type())
+}
+
+func TestSerializeInvalidData(t *testing.T) {
+	_, err := FromBytes([]byte("invalid"))
+	require.Error(t, err)
+
+	m := New("cpu", map[string]string{"host": "localhost"}, map[string]interface{}{"value": "some text"}, time.Unix(0, 0))
+	buf, err := ToBytes(m)
+	require.NoError(t, err)
+
+	// Truncated data must not be decoded
+	for i := 0; i < len(buf); i++ {
+		_, err := FromBytes(buf[:i])
+		require.Error(t, err, "length %d", i)
+	}
+	_, err = FromBytes(append(buf, 0))
+	require.ErrorContains(t, err, "trailing bytes")
+}
@@ -0,0 +1,328 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tidwall/wal"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// DiskBuffer stores metrics in a segmented write-ahead log on disk so that
+// unsent metrics survive restarts of the agent.
+type DiskBuffer struct {
+	sync.Mutex
+	BufferStats
+
+	file *wal.Log
+	path string
+	cap  int
+	log  telegraf.Logger
+
+	first uint64 // index of the first/oldest entry in the log
+	next  uint64 // index of the next entry to be written to the log
+
+	batchEnd  uint64 // one after the index of the last entry in the batch
+	batchSize int    // number of metrics currently in the batch
+
+	// The log can only be truncated at the front, so metrics dropped from
+	// the middle of the log are masked until they are truncated.
+	mask map[uint64]bool
+}
+
+// NewDiskBuffer returns a buffer with the given capacity stored in the
+// directory of the given plugin id below path. Metrics already present in the
+// directory are replayed.
+func NewDiskBuffer(name, id, alias, path string, capacity int, stats BufferStats) (*DiskBuffer, error) {
+	if path == "" {
+		return nil, errors.New("no buffer directory specified")
+	}
+	if id == "" {
+		return nil, errors.New("no plugin id specified")
+	}
+
+	b := &DiskBuffer{
+		BufferStats: stats,
+		path:        filepath.Join(path, id),
+		cap:         capacity,
+		log:         NewLogger("outputs", name, alias),
+		mask:        make(map[uint64]bool),
+	}
+	if err := b.open(); err != nil {
+		return nil, fmt.Errorf("opening buffer in %q failed: %w", b.path, err)
+	}
+
+	if n := b.length(); n > 0 {
+		b.log.Infof("Restored %d metrics from buffer in %q", n, b.path)
+	}
+	b.BufferSize.Set(int64(b.length()))
+	return b, nil
+}
+
+func (b *DiskBuffer) open() error {
+	file, err := wal.Open(b.path, nil)
+	if err != nil {
+		return err
+	}
+
+	first, err := file.FirstIndex()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	last, err := file.LastIndex()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	b.file = file
+	if last == 0 {
+		// The log is empty and starts with index one
+		b.first = 1
+		b.next = 1
+	} else {
+		b.first = first
+		b.next = last + 1
+	}
+	return nil
+}
+
+// reset removes all entries by recreating the log as it cannot be truncated
+// to zero length.
+func (b *DiskBuffer) reset() error {
+	if err := b.file.Close(); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(b.path); err != nil {
+		return err
+	}
+	return b.open()
+}
+
+// Len returns the number of metrics currently in the buffer.
+func (b *DiskBuffer) Len() int {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.length()
+}
+
+func (b *DiskBuffer) length() int {
+	return int(b.next-b.first) - len(b.mask)
+}
+
+// entryDropped accounts for an entry that cannot be decoded anymore.
+func (b *DiskBuffer) entryDropped(index uint64, err error) {
+	b.log.Errorf("Dropping unreadable buffer entry %d: %v", index, err)
+	AgentMetricsDropped.Incr(1)
+	b.MetricsDropped.Incr(1)
+}
+
+func (b *DiskBuffer) read(index uint64) (telegraf.Metric, error) {
+	data, err := b.file.Read(index)
+	if err != nil {
+		return nil, err
+	}
+	return metric.FromBytes(data)
+}
+
+// dropOldest drops the oldest metric not part of the current batch and
+// returns false if there is no such metric.
+func (b *DiskBuffer) dropOldest() bool {
+	start := b.first
+	if b.batchSize > 0 {
+		start = b.batchEnd
+	}
+
+	for index := start; index < b.next; index++ {
+		if b.mask[index] {
+			continue
+		}
+
+		if m, err := b.read(index); err != nil {
+			b.entryDropped(index, err)
+		} else {
+			b.metricDropped(m)
+		}
+
+		if index == b.first {
+			if err := b.truncate(index + 1); err != nil {
+				b.log.Errorf("Truncating buffer failed: %v", err)
+				b.mask[index] = true
+			}
+		} else {
+			b.mask[index] = true
+		}
+		return true
+	}
+	return false
+}
+
+// truncate removes all entries before the given index from the log.
+func (b *DiskBuffer) truncate(index uint64) error {
+	// Skip masked entries at the front as they are dropped anyway
+	for index < b.next && b.mask[index] {
+		index++
+	}
+	for i := range b.mask {
+		if i < index {
+			delete(b.mask, i)
+		}
+	}
+
+	if index >= b.next {
+		return b.reset()
+	}
+	if err := b.file.TruncateFront(index); err != nil {
+		return err
+	}
+	b.first = index
+	return nil
+}
+
+func (b *DiskBuffer) addMetric(m telegraf.Metric) int {
+	dropped := 0
+	// Check if Buffer is full
+	if b.length() >= b.cap {
+		if !b.dropOldest() {
+			// All metrics are part of the current batch so drop the new one
+			b.metricDropped(m)
+			return 1
+		}
+		dropped++
+	}
+
+	data, err := metric.ToBytes(m)
+	if err != nil {
+		b.log.Errorf("Encoding metric failed: %v", err)
+		b.metricDropped(m)
+		return dropped + 1
+	}
+	if err := b.file.Write(b.next, data); err != nil {
+		b.log.Errorf("Writing metric to buffer failed: %v", err)
+		b.metricDropped(m)
+		return dropped + 1
+	}
+	b.next++
+	b.metricAdded()
+
+	// The metric is persisted now so we are done with the original one
+	m.Accept()
+
+	return dropped
+}
+
+// Add adds metrics to the buffer and returns number of dropped metrics.
+func (b *DiskBuffer) Add(metrics ...telegraf.Metric) int {
+	b.Lock()
+	defer b.Unlock()
+
+	dropped := 0
+	for i := range metrics {
+		if n := b.addMetric(metrics[i]); n != 0 {
+			dropped += n
+		}
+	}
+
+	b.BufferSize.Set(int64(b.length()))
+	return dropped
+}
+
+// Batch returns a slice containing up to batchSize of the oldest metrics not
+// yet dropped.  Metrics are ordered from oldest to newest in the batch.  The
+// batch must not be modified by the client.
+func (b *DiskBuffer) Batch(batchSize int) []telegraf.Metric {
+	b.Lock()
+	defer b.Unlock()
+
+	out := make([]telegraf.Metric, 0, min(b.length(), batchSize))
+
+	index := b.first
+	for ; index < b.next && len(out) < batchSize; index++ {
+		if b.mask[index] {
+			continue
+		}
+
+		m, err := b.read(index)
+		if err != nil {
+			b.entryDropped(index, err)
+			b.mask[index] = true
+			continue
+		}
+		out = append(out, m)
+	}
+
+	if len(out) > 0 {
+		b.batchEnd = index
+		b.batchSize = len(out)
+	}
+	b.BufferSize.Set(int64(b.length()))
+	return out
+}
+
+// Accept marks the batch, acquired from Batch(), as successfully written and
+// removes it from the disk.
+func (b *DiskBuffer) Accept(batch []telegraf.Metric) {
+	b.Lock()
+	defer b.Unlock()
+
+	for _, m := range batch {
+		b.metricWritten(m)
+	}
+
+	if b.batchSize > 0 {
+		if err := b.truncate(b.batchEnd); err != nil {
+			b.log.Errorf("Removing written metrics from buffer failed: %v", err)
+		}
+	}
+
+	b.resetBatch()
+	b.BufferSize.Set(int64(b.length()))
+}
+
+// Reject returns the batch, acquired from Batch(), to the buffer and marks it
+// as unsent.
+func (b *DiskBuffer) Reject(_ []telegraf.Metric) {
+	b.Lock()
+	defer b.Unlock()
+
+	// The metrics are still on disk, so we only need to forget the batch
+	b.resetBatch()
+	b.BufferSize.Set(int64(b.length()))
+}
+
+// Stats returns the statistics of the buffer.
+func (b *DiskBuffer) Stats() BufferStats {
+	return b.BufferStats
+}
+
+// Close closes the underlying log keeping all unsent metrics on disk.
+func (b *DiskBuffer) Close() error {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.file.Close()
+}
+
+// Remove closes the underlying log and deletes it including all unsent
+// metrics.
+func (b *DiskBuffer) Remove() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if err := b.file.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(b.path)
+}
+
+func (b *DiskBuffer) resetBatch() {
+	b.batchEnd = 0
+	b.batchSize = 0
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newTestDiskBuffer(t testing.TB, path string, capacity int) *DiskBuffer {
+	b, err := NewDiskBuffer("test", "123", "", path, capacity, NewBufferStats("test", "", capacity))
+	require.NoError(t, err)
+	b.MetricsAdded.Set(0)
+	b.MetricsWritten.Set(0)
+	b.MetricsDropped.Set(0)
+	return b
+}
+
+func TestDiskBuffer_MissingSettings(t *testing.T) {
+	_, err := NewDiskBuffer("test", "", "", t.TempDir(), 5, NewBufferStats("test", "", 5))
+	require.ErrorContains(t, err, "no plugin id")
+
+	_, err = NewDiskBuffer("test", "123", "", "", 5, NewBufferStats("test", "", 5))
+	require.ErrorContains(t, err, "no buffer directory")
+}
+
+func TestDiskBuffer_BatchAccept(t *testing.T) {
+	b := newTestDiskBuffer(t, t.TempDir(), 5)
+	defer b.Close()
+
+	b.Add(MetricTime(1), MetricTime(2), MetricTime(3))
+	require.Equal(t, 3, b.Len())
+	require.Equal(t, int64(3), b.MetricsAdded.Get())
+
+	batch := b.Batch(2)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{MetricTime(1), MetricTime(2)}, batch)
+	require.Equal(t, 3, b.Len())
+
+	b.Accept(batch)
+	require.Equal(t, 1, b.Len())
+	require.Equal(t, int64(2), b.MetricsWritten.Get())
+
+	batch = b.Batch(2)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{MetricTime(3)}, batch)
+	b.Accept(batch)
+	require.Equal(t, 0, b.Len())
+
+	// Make sure the buffer is still usable after being emptied
+	b.Add(MetricTime(4))
+	batch = b.Batch(2)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{MetricTime(4)}, batch)
+}
+
+func TestDiskBuffer_BatchReject(t *testing.T) {
+	b := newTestDiskBuffer(t, t.TempDir(), 5)
+	defer b.Close()
+
+	b.Add(MetricTime(1), MetricTime(2), MetricTime(3))
+	batch := b.Batch(2)
+	b.Reject(batch)
+	require.Equal(t, 3, b.Len())
+
+	batch = b.Batch(5)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{MetricTime(1), MetricTime(2), MetricTime(3)}, batch)
+}
+
+func TestDiskBuffer_Replay(t *testing.T) {
+	path := t.TempDir()
+
+	b := newTestDiskBuffer(t, path, 5)
+	b.Add(MetricTime(1), MetricTime(2), MetricTime(3))
+	b.Accept(b.Batch(1))
+	require.NoError(t, b.Close())
+
+	b = newTestDiskBuffer(t, path, 5)
+	defer b.Close()
+	require.Equal(t, 2, b.Len())
+	require.Equal(t, int64(2), b.BufferSize.Get())
+
+	batch := b.Batch(5)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{MetricTime(2), MetricTime(3)}, batch)
+}
+
+func TestDiskBuffer_Distributions(t *testing.T) {
+	path := t.TempDir()
+
+	histogram := metric.New(
+		"latency",
+		map[string]string{"host": "a"},
+		map[string]interface{}{
+			"value": &telegraf.HistogramValue{
+				Count:   3,
+				Sum:     4.5,
+				Buckets: []telegraf.Bucket{{UpperBound: 1, Count: 1}, {UpperBound: math.Inf(1), Count: 3}},
+				Exponential: &telegraf.ExponentialBuckets{
+					Schema:    3,
+					ZeroCount: 1,
+					Positive:  telegraf.BucketRange{Offset: -2, Counts: []uint64{1, 0, 1}},
+				},
+			},
+		},
+		time.Unix(1, 0),
+		telegraf.Histogram,
+	)
+	summary := metric.New(
+		"latency",
+		map[string]string{"host": "a"},
+		map[string]interface{}{
+			"value": &telegraf.SummaryValue{
+				Count:     3,
+				Sum:       4.5,
+				Quantiles: []telegraf.Quantile{{Quantile: 0.5, Value: 1}, {Quantile: 0.99, Value: 3}},
+			},
+		},
+		time.Unix(2, 0),
+		telegraf.Summary,
+	)
+
+	b := newTestDiskBuffer(t, path, 5)
+	b.Add(histogram, summary)
+	require.Equal(t, 2, b.Len())
+	require.Zero(t, b.MetricsDropped.Get())
+	require.NoError(t, b.Close())
+
+	// The values are restored after replaying the buffer
+	b = newTestDiskBuffer(t, path, 5)
+	defer b.Close()
+	batch := b.Batch(5)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{histogram, summary}, batch)
+	require.Equal(t, telegraf.Histogram, batch[0].Type())
+	require.Equal(t, telegraf.Summary, batch[1].Type())
+}
+
+func TestDiskBuffer_DropOldest(t *testing.T) {
+	b := newTestDiskBuffer(t, t.TempDir(), 3)
+	defer b.Close()
+
+	dropped := b.Add(MetricTime(1), MetricTime(2), MetricTime(3), MetricTime(4))
+	require.Equal(t, 1, dropped)
+	require.Equal(t, 3, b.Len())
+	require.Equal(t, int64(1), b.MetricsDropped.Get())
+
+	batch := b.Batch(5)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{MetricTime(2), MetricTime(3), MetricTime(4)}, batch)
+}
+
+func TestDiskBuffer_DropOldestNotInBatch(t *testing.T) {
+	b := newTestDiskBuffer(t, t.TempDir(), 4)
+	defer b.Close()
+
+	b.Add(MetricTime(1), MetricTime(2), MetricTime(3), MetricTime(4))
+	batch := b.Batch(2)
+
+	dropped := b.Add(MetricTime(5))
+	require.Equal(t, 1, dropped)
+	require.Equal(t, 4, b.Len())
+
+	b.Accept(batch)
+	require.Equal(t, 2, b.Len())
+
+	batch = b.Batch(5)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{MetricTime(4), MetricTime(5)}, batch)
+}
+
+func TestDiskBuffer_AcceptsTrackingMetricOnAdd(t *testing.T) {
+	b := newTestDiskBuffer(t, t.TempDir(), 5)
+	defer b.Close()
+
+	var delivered bool
+	m, _ := metric.WithTracking(MetricTime(1), func(di telegraf.DeliveryInfo) {
+		delivered = di.Delivered()
+	})
+	b.Add(m)
+	require.True(t, delivered)
+
+	batch := b.Batch(1)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{MetricTime(1)}, batch)
+}
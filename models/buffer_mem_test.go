@@ -46,14 +46,21 @@ func MetricTime(sec int64) telegraf.Metric {
 }
 
 func BenchmarkAddMetrics(b *testing.B) {
-	buf := NewBuffer("test", "", 10000)
+	buf, err := NewMemoryBuffer(10000, NewBufferStats("test", "", 10000))
+	require.NoError(b, err)
 	m := Metric()
 	for n := 0; n < b.N; n++ {
 		buf.Add(m)
 	}
 }
 
-func setup(b *Buffer) *Buffer {
+func newTestMemoryBuffer(t testing.TB, capacity int) *MemoryBuffer {
+	b, err := NewMemoryBuffer(capacity, NewBufferStats("test", "", capacity))
+	require.NoError(t, err)
+	return setup(b)
+}
+
+func setup(b *MemoryBuffer) *MemoryBuffer {
 	b.MetricsAdded.Set(0)
 	b.MetricsWritten.Set(0)
 	b.MetricsDropped.Set(0)
@@ -61,14 +68,14 @@ func setup(b *Buffer) *Buffer {
 }
 
 func TestBuffer_LenEmpty(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 
 	require.Equal(t, 0, b.Len())
 }
 
 func TestBuffer_LenOne(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m)
 
 	require.Equal(t, 1, b.Len())
@@ -76,7 +83,7 @@ func TestBuffer_LenOne(t *testing.T) {
 
 func TestBuffer_LenFull(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m, m, m, m)
 
 	require.Equal(t, 5, b.Len())
@@ -84,7 +91,7 @@ func TestBuffer_LenFull(t *testing.T) {
 
 func TestBuffer_LenOverfill(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	setup(b)
 	b.Add(m, m, m, m, m, m)
 
@@ -92,14 +99,14 @@ func TestBuffer_LenOverfill(t *testing.T) {
 }
 
 func TestBuffer_BatchLenZero(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	batch := b.Batch(0)
 
 	require.Len(t, batch, 0)
 }
 
 func TestBuffer_BatchLenBufferEmpty(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	batch := b.Batch(2)
 
 	require.Len(t, batch, 0)
@@ -107,7 +114,7 @@ func TestBuffer_BatchLenBufferEmpty(t *testing.T) {
 
 func TestBuffer_BatchLenUnderfill(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m)
 	batch := b.Batch(2)
 
@@ -116,7 +123,7 @@ func TestBuffer_BatchLenUnderfill(t *testing.T) {
 
 func TestBuffer_BatchLenFill(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m, m)
 	batch := b.Batch(2)
 	require.Len(t, batch, 2)
@@ -124,7 +131,7 @@ func TestBuffer_BatchLenFill(t *testing.T) {
 
 func TestBuffer_BatchLenExact(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m)
 	batch := b.Batch(2)
 	require.Len(t, batch, 2)
@@ -132,7 +139,7 @@ func TestBuffer_BatchLenExact(t *testing.T) {
 
 func TestBuffer_BatchLenLargerThanBuffer(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m, m, m, m)
 	batch := b.Batch(6)
 	require.Len(t, batch, 5)
@@ -140,7 +147,7 @@ func TestBuffer_BatchLenLargerThanBuffer(t *testing.T) {
 
 func TestBuffer_BatchWrap(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m, m, m, m)
 	batch := b.Batch(2)
 	b.Accept(batch)
@@ -150,7 +157,7 @@ func TestBuffer_BatchWrap(t *testing.T) {
 }
 
 func TestBuffer_BatchLatest(t *testing.T) {
-	b := setup(NewBuffer("test", "", 4))
+	b := newTestMemoryBuffer(t, 4)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -164,7 +171,7 @@ func TestBuffer_BatchLatest(t *testing.T) {
 }
 
 func TestBuffer_BatchLatestWrap(t *testing.T) {
-	b := setup(NewBuffer("test", "", 4))
+	b := newTestMemoryBuffer(t, 4)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -180,7 +187,7 @@ func TestBuffer_BatchLatestWrap(t *testing.T) {
 }
 
 func TestBuffer_MultipleBatch(t *testing.T) {
-	b := setup(NewBuffer("test", "", 10))
+	b := newTestMemoryBuffer(t, 10)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -206,7 +213,7 @@ func TestBuffer_MultipleBatch(t *testing.T) {
 }
 
 func TestBuffer_RejectWithRoom(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -229,7 +236,7 @@ func TestBuffer_RejectWithRoom(t *testing.T) {
 }
 
 func TestBuffer_RejectNothingNewFull(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -252,7 +259,7 @@ func TestBuffer_RejectNothingNewFull(t *testing.T) {
 }
 
 func TestBuffer_RejectNoRoom(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(MetricTime(1))
 
 	b.Add(MetricTime(2))
@@ -281,7 +288,7 @@ func TestBuffer_RejectNoRoom(t *testing.T) {
 }
 
 func TestBuffer_RejectRoomExact(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	batch := b.Batch(2)
@@ -305,7 +312,7 @@ func TestBuffer_RejectRoomExact(t *testing.T) {
 }
 
 func TestBuffer_RejectRoomOverwriteOld(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -330,7 +337,7 @@ func TestBuffer_RejectRoomOverwriteOld(t *testing.T) {
 }
 
 func TestBuffer_RejectPartialRoom(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(MetricTime(1))
 
 	b.Add(MetricTime(2))
@@ -357,7 +364,7 @@ func TestBuffer_RejectPartialRoom(t *testing.T) {
 }
 
 func TestBuffer_RejectNewMetricsWrapped(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -400,7 +407,7 @@ func TestBuffer_RejectNewMetricsWrapped(t *testing.T) {
 }
 
 func TestBuffer_RejectWrapped(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -431,7 +438,7 @@ func TestBuffer_RejectWrapped(t *testing.T) {
 }
 
 func TestBuffer_RejectAdjustFirst(t *testing.T) {
-	b := setup(NewBuffer("test", "", 10))
+	b := newTestMemoryBuffer(t, 10)
 	b.Add(MetricTime(1))
 	b.Add(MetricTime(2))
 	b.Add(MetricTime(3))
@@ -479,7 +486,7 @@ func TestBuffer_RejectAdjustFirst(t *testing.T) {
 
 func TestBuffer_AddDropsOverwrittenMetrics(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 
 	b.Add(m, m, m, m, m)
 	b.Add(m, m, m, m, m)
@@ -490,7 +497,7 @@ func TestBuffer_AddDropsOverwrittenMetrics(t *testing.T) {
 
 func TestBuffer_AcceptRemovesBatch(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m, m)
 	batch := b.Batch(2)
 	b.Accept(batch)
@@ -499,7 +506,7 @@ func TestBuffer_AcceptRemovesBatch(t *testing.T) {
 
 func TestBuffer_RejectLeavesBatch(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m, m)
 	batch := b.Batch(2)
 	b.Reject(batch)
@@ -508,7 +515,7 @@ func TestBuffer_RejectLeavesBatch(t *testing.T) {
 
 func TestBuffer_AcceptWritesOverwrittenBatch(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 
 	b.Add(m, m, m, m, m)
 	batch := b.Batch(5)
@@ -521,7 +528,7 @@ func TestBuffer_AcceptWritesOverwrittenBatch(t *testing.T) {
 
 func TestBuffer_BatchRejectDropsOverwrittenBatch(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 
 	b.Add(m, m, m, m, m)
 	batch := b.Batch(5)
@@ -534,7 +541,7 @@ func TestBuffer_BatchRejectDropsOverwrittenBatch(t *testing.T) {
 
 func TestBuffer_MetricsOverwriteBatchAccept(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 
 	b.Add(m, m, m, m, m)
 	batch := b.Batch(3)
@@ -546,7 +553,7 @@ func TestBuffer_MetricsOverwriteBatchAccept(t *testing.T) {
 
 func TestBuffer_MetricsOverwriteBatchReject(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 
 	b.Add(m, m, m, m, m)
 	batch := b.Batch(3)
@@ -558,7 +565,7 @@ func TestBuffer_MetricsOverwriteBatchReject(t *testing.T) {
 
 func TestBuffer_MetricsBatchAcceptRemoved(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 
 	b.Add(m, m, m, m, m)
 	batch := b.Batch(3)
@@ -570,7 +577,7 @@ func TestBuffer_MetricsBatchAcceptRemoved(t *testing.T) {
 
 func TestBuffer_WrapWithBatch(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 
 	b.Add(m, m, m)
 	b.Batch(3)
@@ -581,7 +588,7 @@ func TestBuffer_WrapWithBatch(t *testing.T) {
 
 func TestBuffer_BatchNotRemoved(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m, m, m, m)
 	b.Batch(2)
 	require.Equal(t, 5, b.Len())
@@ -589,7 +596,7 @@ func TestBuffer_BatchNotRemoved(t *testing.T) {
 
 func TestBuffer_BatchRejectAcceptNoop(t *testing.T) {
 	m := Metric()
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(m, m, m, m, m)
 	batch := b.Batch(2)
 	b.Reject(batch)
@@ -605,7 +612,7 @@ func TestBuffer_AcceptCallsMetricAccept(t *testing.T) {
 			accept++
 		},
 	}
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(mm, mm, mm)
 	batch := b.Batch(2)
 	b.Accept(batch)
@@ -620,7 +627,7 @@ func TestBuffer_AddCallsMetricRejectWhenNoBatch(t *testing.T) {
 			reject++
 		},
 	}
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	setup(b)
 	b.Add(mm, mm, mm, mm, mm)
 	b.Add(mm, mm)
@@ -635,7 +642,7 @@ func TestBuffer_AddCallsMetricRejectWhenNotInBatch(t *testing.T) {
 			reject++
 		},
 	}
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	setup(b)
 	b.Add(mm, mm, mm, mm, mm)
 	batch := b.Batch(2)
@@ -653,7 +660,7 @@ func TestBuffer_RejectCallsMetricRejectWithOverwritten(t *testing.T) {
 			reject++
 		},
 	}
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(mm, mm, mm, mm, mm)
 	batch := b.Batch(5)
 	b.Add(mm, mm)
@@ -670,7 +677,7 @@ func TestBuffer_AddOverwriteAndReject(t *testing.T) {
 			reject++
 		},
 	}
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(mm, mm, mm, mm, mm)
 	batch := b.Batch(5)
 	b.Add(mm, mm, mm, mm, mm)
@@ -694,7 +701,7 @@ func TestBuffer_AddOverwriteAndRejectOffset(t *testing.T) {
 			accept++
 		},
 	}
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	b.Add(mm, mm, mm)
 	b.Add(mm, mm, mm, mm)
 	require.Equal(t, 2, reject)
@@ -713,7 +720,7 @@ func TestBuffer_AddOverwriteAndRejectOffset(t *testing.T) {
 }
 
 func TestBuffer_RejectEmptyBatch(t *testing.T) {
-	b := setup(NewBuffer("test", "", 5))
+	b := newTestMemoryBuffer(t, 5)
 	batch := b.Batch(2)
 	b.Add(MetricTime(1))
 	b.Reject(batch)
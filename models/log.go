@@ -1,23 +1,80 @@
 package models
 
 import (
+	"fmt"
 	"log"
 	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/influxdata/wlog"
+
 	"github.com/influxdata/telegraf"
 )
 
+// LogEvent is a message logged by a plugin
+type LogEvent struct {
+	Time     time.Time
+	Level    string // one of "debug", "info", "warn" or "error"
+	Category string
+	Plugin   string
+	Alias    string
+	Message  string
+}
+
+// UnfilteredWriter is implemented by log writers filtering messages according
+// to the global log level. Messages written using WriteUnfiltered were already
+// filtered by a logger with its own log level and must be written as is.
+type UnfilteredWriter interface {
+	WriteUnfiltered(b []byte) (int, error)
+}
+
+// Listeners receiving all messages logged by plugins
+var (
+	logListeners     = make(map[int]func(LogEvent))
+	logListenersNext int
+	logListenersMu   sync.RWMutex
+)
+
+// AddLogListener registers a function receiving every message logged by a
+// plugin independent of the log level. The function is called synchronously
+// by the logging plugin, so it must not block and must not log itself. The
+// returned function removes the listener again.
+func AddLogListener(f func(LogEvent)) func() {
+	logListenersMu.Lock()
+	defer logListenersMu.Unlock()
+
+	id := logListenersNext
+	logListenersNext++
+	logListeners[id] = f
+
+	return func() {
+		logListenersMu.Lock()
+		defer logListenersMu.Unlock()
+		delete(logListeners, id)
+	}
+}
+
 // Logger defines a logging structure for plugins.
 type Logger struct {
 	OnErrs []func()
 	Name   string // Name is the plugin name, will be printed in the `[]`.
+
+	category string
+	plugin   string
+	alias    string
+	level    wlog.Level
 }
 
 // NewLogger creates a new logger instance
 func NewLogger(pluginType, name, alias string) *Logger {
 	return &Logger{
-		Name: logName(pluginType, name, alias),
+		Name:     logName(pluginType, name, alias),
+		category: pluginType,
+		plugin:   name,
+		alias:    alias,
 	}
 }
 
@@ -26,12 +83,28 @@ func (l *Logger) OnErr(f func()) {
 	l.OnErrs = append(l.OnErrs, f)
 }
 
+// SetLogLevel overrides the global log level for the messages of this logger.
+// An empty level removes the override. The level must be one of "debug",
+// "info", "warn", "error" or "off". For a nil logger the level is only
+// validated.
+func (l *Logger) SetLogLevel(level string) error {
+	lvl, err := ParseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	if l != nil {
+		l.level = lvl
+	}
+	return nil
+}
+
 // Errorf logs an error message, patterned after log.Printf.
 func (l *Logger) Errorf(format string, args ...interface{}) {
 	for _, f := range l.OnErrs {
 		f()
 	}
-	log.Printf("E! ["+l.Name+"] "+format, args...)
+	l.print(wlog.ERROR, "E! ["+l.Name+"] "+fmt.Sprintf(format, args...))
+	l.publishf("error", format, args...)
 }
 
 // Error logs an error message, patterned after log.Print.
@@ -39,37 +112,115 @@ func (l *Logger) Error(args ...interface{}) {
 	for _, f := range l.OnErrs {
 		f()
 	}
-	log.Print(append([]interface{}{"E! [" + l.Name + "] "}, args...)...)
+	l.print(wlog.ERROR, fmt.Sprint(append([]interface{}{"E! [" + l.Name + "] "}, args...)...))
+	l.publish("error", args...)
 }
 
 // Debugf logs a debug message, patterned after log.Printf.
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	log.Printf("D! ["+l.Name+"] "+format, args...)
+	l.print(wlog.DEBUG, "D! ["+l.Name+"] "+fmt.Sprintf(format, args...))
+	l.publishf("debug", format, args...)
 }
 
 // Debug logs a debug message, patterned after log.Print.
 func (l *Logger) Debug(args ...interface{}) {
-	log.Print(append([]interface{}{"D! [" + l.Name + "] "}, args...)...)
+	l.print(wlog.DEBUG, fmt.Sprint(append([]interface{}{"D! [" + l.Name + "] "}, args...)...))
+	l.publish("debug", args...)
 }
 
 // Warnf logs a warning message, patterned after log.Printf.
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	log.Printf("W! ["+l.Name+"] "+format, args...)
+	l.print(wlog.WARN, "W! ["+l.Name+"] "+fmt.Sprintf(format, args...))
+	l.publishf("warn", format, args...)
 }
 
 // Warn logs a warning message, patterned after log.Print.
 func (l *Logger) Warn(args ...interface{}) {
-	log.Print(append([]interface{}{"W! [" + l.Name + "] "}, args...)...)
+	l.print(wlog.WARN, fmt.Sprint(append([]interface{}{"W! [" + l.Name + "] "}, args...)...))
+	l.publish("warn", args...)
 }
 
 // Infof logs an information message, patterned after log.Printf.
 func (l *Logger) Infof(format string, args ...interface{}) {
-	log.Printf("I! ["+l.Name+"] "+format, args...)
+	l.print(wlog.INFO, "I! ["+l.Name+"] "+fmt.Sprintf(format, args...))
+	l.publishf("info", format, args...)
 }
 
 // Info logs an information message, patterned after log.Print.
 func (l *Logger) Info(args ...interface{}) {
-	log.Print(append([]interface{}{"I! [" + l.Name + "] "}, args...)...)
+	l.print(wlog.INFO, fmt.Sprint(append([]interface{}{"I! [" + l.Name + "] "}, args...)...))
+	l.publish("info", args...)
+}
+
+// print writes the message to the log. Without a log level override, the
+// message is filtered by the log writer according to the global log level.
+// Otherwise, the message is filtered according to the logger's level and
+// passed to the log writer as is.
+func (l *Logger) print(level wlog.Level, msg string) {
+	if l.level == 0 {
+		log.Print(msg)
+		return
+	}
+	if level < l.level {
+		return
+	}
+
+	w, ok := log.Writer().(UnfilteredWriter)
+	if !ok {
+		log.Print(msg)
+		return
+	}
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	_, _ = w.WriteUnfiltered([]byte(msg))
+}
+
+// publishf sends the formatted message to all log listeners.
+func (l *Logger) publishf(level, format string, args ...interface{}) {
+	logListenersMu.RLock()
+	defer logListenersMu.RUnlock()
+	if len(logListeners) > 0 {
+		l.notify(level, fmt.Sprintf(format, args...))
+	}
+}
+
+// publish sends the message to all log listeners.
+func (l *Logger) publish(level string, args ...interface{}) {
+	logListenersMu.RLock()
+	defer logListenersMu.RUnlock()
+	if len(logListeners) > 0 {
+		l.notify(level, fmt.Sprint(args...))
+	}
+}
+
+// notify passes the message to the listeners and must be called with the
+// listeners lock held.
+func (l *Logger) notify(level, msg string) {
+	event := LogEvent{
+		Time:     time.Now(),
+		Level:    level,
+		Category: l.category,
+		Plugin:   l.plugin,
+		Alias:    l.alias,
+		Message:  msg,
+	}
+	for _, f := range logListeners {
+		f(event)
+	}
+}
+
+// ParseLogLevel converts the given log level name. An empty name results in
+// a zero level meaning "use the global level".
+func ParseLogLevel(level string) (wlog.Level, error) {
+	if level == "" {
+		return 0, nil
+	}
+	lvl, found := wlog.StringToLevel[strings.ToUpper(level)]
+	if !found {
+		return 0, fmt.Errorf("invalid log level %q", level)
+	}
+	return lvl, nil
 }
 
 // logName returns the log-friendly name/type.
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/influxdata/telegraf"
+)
+
+// OutputGroupConfig describes a group of outputs sharing the metrics instead
+// of each output receiving all metrics.
+type OutputGroupConfig struct {
+	Name string `toml:"-"`
+
+	// Mode of distributing the metrics, one of "failover", "round_robin" or
+	// "hash"
+	Mode string `toml:"mode"`
+
+	// Aliases of the member outputs, for the "failover" mode in the order of
+	// preference
+	Outputs []string `toml:"outputs"`
+}
+
+// Check validates the group configuration.
+func (c *OutputGroupConfig) Check() error {
+	switch c.Mode {
+	case "failover", "round_robin", "hash":
+	default:
+		return fmt.Errorf("invalid mode %q for output group %q", c.Mode, c.Name)
+	}
+	if len(c.Outputs) == 0 {
+		return fmt.Errorf("no outputs in output group %q", c.Name)
+	}
+	seen := make(map[string]bool, len(c.Outputs))
+	for _, alias := range c.Outputs {
+		if seen[alias] {
+			return fmt.Errorf("duplicate output %q in output group %q", alias, c.Name)
+		}
+		seen[alias] = true
+	}
+	return nil
+}
+
+// OutputGroup routes each metric to a single member output. Unhealthy
+// members are skipped as long as there is a healthy one.
+type OutputGroup struct {
+	Config  *OutputGroupConfig
+	Outputs []*RunningOutput
+
+	next atomic.Uint64
+}
+
+// NewOutputGroup creates a group of the given outputs. The outputs must be
+// in the order of the aliases in the configuration.
+func NewOutputGroup(config *OutputGroupConfig, outputs []*RunningOutput) *OutputGroup {
+	return &OutputGroup{
+		Config:  config,
+		Outputs: outputs,
+	}
+}
+
+// Select returns the member output the given metric should be written to.
+func (g *OutputGroup) Select(metric telegraf.Metric) *RunningOutput {
+	var start int
+	switch g.Config.Mode {
+	case "round_robin":
+		start = int((g.next.Add(1) - 1) % uint64(len(g.Outputs)))
+	case "hash":
+		start = int(metric.HashID() % uint64(len(g.Outputs)))
+	}
+
+	for i := range g.Outputs {
+		output := g.Outputs[(start+i)%len(g.Outputs)]
+		if output.Healthy() {
+			return output
+		}
+	}
+
+	// Keep the metric in the preferred output if no output is healthy
+	return g.Outputs[start]
+}
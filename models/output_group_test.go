@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newTestOutputGroup(t *testing.T, mode string, n int) *OutputGroup {
+	config := &OutputGroupConfig{Name: "group_" + mode, Mode: mode}
+	outputs := make([]*RunningOutput, 0, n)
+	for i := 0; i < n; i++ {
+		output, err := NewRunningOutput(&mockOutput{}, &OutputConfig{Name: "group_" + mode}, 10, 100)
+		require.NoError(t, err)
+		outputs = append(outputs, output)
+	}
+	return NewOutputGroup(config, outputs)
+}
+
+func TestOutputGroupFailover(t *testing.T) {
+	group := newTestOutputGroup(t, "failover", 3)
+	metric := testutil.TestMetric(1)
+
+	require.Same(t, group.Outputs[0], group.Select(metric))
+
+	// Unhealthy outputs are skipped in order
+	group.Outputs[0].writeRetry.failed(time.Now())
+	require.Same(t, group.Outputs[1], group.Select(metric))
+	group.Outputs[1].Pause()
+	require.Same(t, group.Outputs[2], group.Select(metric))
+
+	// Without any healthy output, the preferred one is used
+	group.Outputs[2].writeRetry.failed(time.Now())
+	require.Same(t, group.Outputs[0], group.Select(metric))
+
+	group.Outputs[0].writeRetry.succeeded()
+	require.Same(t, group.Outputs[0], group.Select(metric))
+}
+
+func TestOutputGroupRoundRobin(t *testing.T) {
+	group := newTestOutputGroup(t, "round_robin", 3)
+	metric := testutil.TestMetric(1)
+
+	require.Same(t, group.Outputs[0], group.Select(metric))
+	require.Same(t, group.Outputs[1], group.Select(metric))
+	require.Same(t, group.Outputs[2], group.Select(metric))
+	require.Same(t, group.Outputs[0], group.Select(metric))
+
+	// Unhealthy outputs are skipped
+	group.Outputs[1].writeRetry.failed(time.Now())
+	require.Same(t, group.Outputs[2], group.Select(metric))
+	require.Same(t, group.Outputs[2], group.Select(metric))
+	require.Same(t, group.Outputs[0], group.Select(metric))
+}
+
+func TestOutputGroupHash(t *testing.T) {
+	group := newTestOutputGroup(t, "hash", 3)
+
+	// Metrics of the same series always end up in the same output
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("metric%d", i)
+		metric := testutil.TestMetric(1, name)
+		expected := group.Outputs[metric.HashID()%3]
+		require.Same(t, expected, group.Select(metric))
+		require.Same(t, expected, group.Select(testutil.TestMetric(2, name)))
+	}
+
+	// Series of an unhealthy output are moved to the next one
+	metric := testutil.TestMetric(1, "metric")
+	idx := int(metric.HashID() % 3)
+	group.Outputs[idx].writeRetry.failed(time.Now())
+	require.Same(t, group.Outputs[(idx+1)%3], group.Select(metric))
+}
+
+func TestOutputGroupConfigCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *OutputGroupConfig
+		expected string
+	}{
+		{
+			name:   "valid",
+			config: &OutputGroupConfig{Name: "valid", Mode: "failover", Outputs: []string{"a", "b"}},
+		},
+		{
+			name:     "invalid mode",
+			config:   &OutputGroupConfig{Name: "invalid", Mode: "random", Outputs: []string{"a"}},
+			expected: `invalid mode "random" for output group "invalid"`,
+		},
+		{
+			name:     "empty",
+			config:   &OutputGroupConfig{Name: "empty", Mode: "hash"},
+			expected: `no outputs in output group "empty"`,
+		},
+		{
+			name:     "duplicate",
+			config:   &OutputGroupConfig{Name: "duplicate", Mode: "round_robin", Outputs: []string{"a", "a"}},
+			expected: `duplicate output "a" in output group "duplicate"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Check()
+			if tt.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/telegraf/internal"
+)
+
+const (
+	// Default upper limit of the delay between two write attempts
+	defaultRetryMaxBackoff = 5 * time.Minute
+
+	// Default time an open circuit waits before probing the output again
+	defaultCircuitResetTimeout = time.Minute
+)
+
+// Circuit breaker states of an output as reported in the circuit_state
+// field of the internal_write metric
+const (
+	circuitClosed int32 = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// writeRetry implements the retry policy of an output. After a failed write,
+// further attempts are delayed with an exponentially increasing backoff. Once
+// the configured number of consecutive failures is reached, the circuit opens
+// and writing is suspended for the reset timeout. Afterwards, the circuit is
+// half-open and a single batch is written to probe the output. Only the state,
+// the failing flag and the delay may be accessed concurrently, all other fields
+// are used by the flushing goroutine only.
+type writeRetry struct {
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         time.Duration
+	threshold      int
+	resetTimeout   time.Duration
+
+	state    atomic.Int32
+	failing  atomic.Bool
+	until    atomic.Int64
+	failures int
+	next     time.Time
+}
+
+func newWriteRetry(config *OutputConfig) *writeRetry {
+	w := &writeRetry{
+		initialBackoff: config.RetryInitialBackoff,
+		maxBackoff:     config.RetryMaxBackoff,
+		jitter:         config.RetryJitter,
+		threshold:      config.CircuitBreakerThreshold,
+		resetTimeout:   config.CircuitBreakerResetTimeout,
+	}
+	if w.maxBackoff == 0 {
+		w.maxBackoff = defaultRetryMaxBackoff
+	}
+	if w.resetTimeout == 0 {
+		w.resetTimeout = defaultCircuitResetTimeout
+	}
+	return w
+}
+
+// checkRetryPolicy validates the retry settings of the given output config.
+func checkRetryPolicy(config *OutputConfig) error {
+	if config.RetryInitialBackoff < 0 || config.RetryMaxBackoff < 0 || config.RetryJitter < 0 {
+		return errors.New("retry settings must not be negative")
+	}
+	if config.RetryMaxBackoff > 0 && config.RetryMaxBackoff < config.RetryInitialBackoff {
+		return errors.New("'retry_max_backoff' must not be smaller than 'retry_initial_backoff'")
+	}
+	if config.CircuitBreakerThreshold < 0 || config.CircuitBreakerResetTimeout < 0 {
+		return errors.New("circuit breaker settings must not be negative")
+	}
+	return nil
+}
+
+// allowed returns true if a write should be attempted at the given time. An
+// open circuit becomes half-open once the reset timeout elapsed.
+func (w *writeRetry) allowed(now time.Time) bool {
+	switch w.state.Load() {
+	case circuitOpen:
+		if now.Before(w.next) {
+			return false
+		}
+		w.state.Store(circuitHalfOpen)
+		return true
+	case circuitHalfOpen:
+		return true
+	}
+	return w.failures == 0 || !now.Before(w.next)
+}
+
+// retrying returns true if the previous write failed.
+func (w *writeRetry) retrying() bool {
+	return w.failing.Load()
+}
+
+// failed records a failed write at the given time and returns the delay until
+// the next attempt.
+func (w *writeRetry) failed(now time.Time) time.Duration {
+	w.failures++
+	w.failing.Store(true)
+
+	// A failed probe or too many failures open the circuit
+	if w.state.Load() == circuitHalfOpen || (w.threshold > 0 && w.failures >= w.threshold) {
+		w.state.Store(circuitOpen)
+		w.next = now.Add(w.resetTimeout)
+		w.until.Store(w.next.UnixNano())
+		return w.resetTimeout
+	}
+
+	delay := w.backoff()
+	w.next = now.Add(delay)
+	w.until.Store(w.next.UnixNano())
+	return delay
+}
+
+// delay returns the time until the next write attempt is allowed or zero if
+// writing is allowed at the given time.
+func (w *writeRetry) delay(now time.Time) time.Duration {
+	if !w.failing.Load() {
+		return 0
+	}
+	if delay := time.Unix(0, w.until.Load()).Sub(now); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// succeeded records a successful write and closes the circuit.
+func (w *writeRetry) succeeded() {
+	w.failures = 0
+	w.failing.Store(false)
+	w.state.Store(circuitClosed)
+}
+
+// backoff returns the delay for the current number of consecutive failures.
+// Without an initial backoff, writes are retried on the next flush.
+func (w *writeRetry) backoff() time.Duration {
+	if w.initialBackoff == 0 {
+		return 0
+	}
+
+	delay := w.initialBackoff
+	for i := 1; i < w.failures && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.maxBackoff {
+		delay = w.maxBackoff
+	}
+	return delay + internal.RandomDuration(w.jitter)
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -16,11 +19,18 @@ type RunningInput struct {
 	Input  telegraf.Input
 	Config *InputConfig
 
-	log         telegraf.Logger
+	log         *Logger
 	defaultTags map[string]string
 
 	MetricsGathered selfstat.Stat
 	GatherTime      selfstat.Stat
+	StartupErrors   selfstat.Stat
+
+	// Accumulator and backoff for (re)starting a service input
+	startAcc telegraf.Accumulator
+	retry    startupRetry
+
+	paused atomic.Bool
 }
 
 func NewRunningInput(input telegraf.Input, config *InputConfig) *RunningInput {
@@ -50,6 +60,11 @@ func NewRunningInput(input telegraf.Input, config *InputConfig) *RunningInput {
 			"gather_time_ns",
 			tags,
 		),
+		StartupErrors: selfstat.Register(
+			"gather",
+			"startup_errors",
+			tags,
+		),
 		log: logger,
 	}
 }
@@ -64,6 +79,9 @@ type InputConfig struct {
 	CollectionOffset time.Duration
 	Precision        time.Duration
 
+	StartupErrorBehavior string
+	LogLevel             string
+
 	NameOverride      string
 	MeasurementPrefix string
 	MeasurementSuffix string
@@ -80,6 +98,13 @@ func (r *RunningInput) LogName() string {
 }
 
 func (r *RunningInput) Init() error {
+	if err := checkStartupErrorBehavior(r.Config.StartupErrorBehavior); err != nil {
+		return err
+	}
+	if err := r.log.SetLogLevel(r.Config.LogLevel); err != nil {
+		return err
+	}
+
 	if p, ok := r.Input.(telegraf.Initializer); ok {
 		err := p.Init()
 		if err != nil {
@@ -97,6 +122,12 @@ func (r *RunningInput) ID() string {
 }
 
 func (r *RunningInput) MakeMetric(metric telegraf.Metric) telegraf.Metric {
+	// Drop the metrics of paused service inputs
+	if r.paused.Load() {
+		metric.Drop()
+		return nil
+	}
+
 	ok, err := r.Config.Filter.Select(metric)
 	if err != nil {
 		r.log.Errorf("filtering failed: %v", err)
@@ -124,14 +155,106 @@ func (r *RunningInput) MakeMetric(metric telegraf.Metric) telegraf.Metric {
 	return m
 }
 
+// Start starts the input if it is a service input. Errors are handled according
+// to the configured startup error behavior. For the "retry" behavior, starting
+// is retried with increasing delay on subsequent calls to Gather, for the
+// "ignore" behavior an error wrapping ErrPluginSkipped is returned.
+func (r *RunningInput) Start(acc telegraf.Accumulator) error {
+	si, ok := r.Input.(telegraf.ServiceInput)
+	if !ok {
+		return nil
+	}
+
+	// Keep the accumulator for restarting the input
+	r.startAcc = acc
+	err := si.Start(acc)
+	if err == nil {
+		return nil
+	}
+	r.StartupErrors.Incr(1)
+
+	switch r.Config.StartupErrorBehavior {
+	case "retry":
+		r.retry.failed()
+		r.log.Warnf("Starting failed: %v; retrying in %s", err, r.retry.backoff)
+		return nil
+	case "ignore":
+		return fmt.Errorf("%w: %w", ErrPluginSkipped, err)
+	}
+	return err
+}
+
+// Stop stops the input if it is a successfully started service input.
+func (r *RunningInput) Stop() {
+	if si, ok := r.Input.(telegraf.ServiceInput); ok && !r.retry.retrying() {
+		si.Stop()
+	}
+}
+
+func (r *RunningInput) retryStart() error {
+	if err := r.Input.(telegraf.ServiceInput).Start(r.startAcc); err != nil {
+		r.StartupErrors.Incr(1)
+		r.retry.failed()
+		return fmt.Errorf("starting failed, retrying in %s: %w", r.retry.backoff, err)
+	}
+	r.retry.succeeded()
+	r.log.Info("Started successfully")
+	return nil
+}
+
 func (r *RunningInput) Gather(acc telegraf.Accumulator) error {
+	if r.paused.Load() {
+		return nil
+	}
+
+	// Do not gather from service inputs not started yet
+	if r.retry.retrying() {
+		if !r.retry.due() {
+			return nil
+		}
+		if err := r.retryStart(); err != nil {
+			return err
+		}
+	}
+
 	start := time.Now()
 	err := r.Input.Gather(acc)
 	elapsed := time.Since(start)
 	r.GatherTime.Incr(elapsed.Nanoseconds())
+
+	var authErr *telegraf.AuthenticationError
+	if errors.As(err, &authErr) {
+		r.restart()
+	}
 	return err
 }
 
+// restart refreshes the rotatable secrets of the input after an
+// authentication failure. Service inputs are restarted to use the current
+// credentials, a failing start is retried according to the startup retry
+// policy.
+func (r *RunningInput) restart() {
+	n, err := rotateSecrets(r.Input)
+	if err != nil {
+		r.log.Errorf("Refreshing secrets failed: %v", err)
+	}
+	if n == 0 {
+		return
+	}
+	r.log.Infof("Authentication failed; using %d refreshed secret(s)", n)
+
+	si, ok := r.Input.(telegraf.ServiceInput)
+	if !ok || r.startAcc == nil {
+		return
+	}
+	si.Stop()
+	if err := si.Start(r.startAcc); err != nil {
+		r.StartupErrors.Incr(1)
+		r.retry.failed()
+		r.log.Warnf("Restarting failed: %v; retrying in %s", err, r.retry.backoff)
+	}
+}
+
 func (r *RunningInput) SetDefaultTags(tags map[string]string) {
 	r.defaultTags = tags
 }
@@ -139,3 +262,31 @@ func (r *RunningInput) SetDefaultTags(tags map[string]string) {
 func (r *RunningInput) Log() telegraf.Logger {
 	return r.log
 }
+
+// Pause stops gathering metrics from the input until Resume is called. Metrics
+// produced by service inputs in the meantime are dropped.
+func (r *RunningInput) Pause() {
+	r.paused.Store(true)
+}
+
+// Resume continues gathering metrics from a paused input.
+func (r *RunningInput) Resume() {
+	r.paused.Store(false)
+}
+
+// Paused returns true if the input is paused.
+func (r *RunningInput) Paused() bool {
+	return r.paused.Load()
+}
+
+// State returns the state of the input, either "running", "paused" or
+// "starting" if the input is waiting to retry a failed start.
+func (r *RunningInput) State() string {
+	switch {
+	case r.paused.Load():
+		return "paused"
+	case r.retry.retrying():
+		return "starting"
+	}
+	return "running"
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -278,3 +279,92 @@ type testInput struct{}
 func (t *testInput) Description() string                 { return "" }
 func (t *testInput) SampleConfig() string                { return "" }
 func (t *testInput) Gather(_ telegraf.Accumulator) error { return nil }
+
+type testServiceInput struct {
+	testInput
+	failStart bool
+	started   bool
+}
+
+func (t *testServiceInput) Start(_ telegraf.Accumulator) error {
+	if t.failStart {
+		return errors.New("failed start")
+	}
+	t.started = true
+	return nil
+}
+
+func (t *testServiceInput) Stop() {
+	t.started = false
+}
+
+func TestRunningInputStartupErrorBehavior(t *testing.T) {
+	tests := []struct {
+		behavior string
+		skipped  bool
+		failed   bool
+	}{
+		{behavior: "", failed: true},
+		{behavior: "error", failed: true},
+		{behavior: "ignore", skipped: true},
+		{behavior: "retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior, func(t *testing.T) {
+			ri := NewRunningInput(&testServiceInput{failStart: true}, &InputConfig{
+				Name:                 "TestRunningInputStartupErrorBehavior_" + tt.behavior,
+				StartupErrorBehavior: tt.behavior,
+			})
+			require.NoError(t, ri.Init())
+
+			err := ri.Start(&testutil.Accumulator{})
+			require.Equal(t, tt.failed || tt.skipped, err != nil)
+			require.Equal(t, tt.skipped, errors.Is(err, ErrPluginSkipped))
+			require.Equal(t, int64(1), ri.StartupErrors.Get())
+		})
+	}
+}
+
+func TestRunningInputStartupErrorRetry(t *testing.T) {
+	input := &testServiceInput{failStart: true}
+	ri := NewRunningInput(input, &InputConfig{
+		Name:                 "TestRunningInputStartupErrorRetry",
+		StartupErrorBehavior: "retry",
+	})
+	require.NoError(t, ri.Init())
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, ri.Start(acc))
+
+	// Gathering is skipped until the next attempt is due
+	require.NoError(t, ri.Gather(acc))
+	require.False(t, input.started)
+
+	ri.retry.next = time.Now()
+	require.ErrorContains(t, ri.Gather(acc), "failed start")
+	require.Equal(t, int64(2), ri.StartupErrors.Get())
+
+	input.failStart = false
+	ri.retry.next = time.Now()
+	require.NoError(t, ri.Gather(acc))
+	require.True(t, input.started)
+
+	ri.Stop()
+	require.False(t, input.started)
+}
+
+func TestRunningInputPause(t *testing.T) {
+	ri := NewRunningInput(&testInput{}, &InputConfig{Name: "TestRunningInputPause"})
+	require.Equal(t, "running", ri.State())
+
+	ri.Pause()
+	require.True(t, ri.Paused())
+	require.Equal(t, "paused", ri.State())
+	m := testutil.MustMetric("test", map[string]string{}, map[string]interface{}{"value": 1}, time.Unix(0, 0))
+	require.Nil(t, ri.MakeMetric(m))
+
+	ri.Resume()
+	require.False(t, ri.Paused())
+	require.NotNil(t, ri.MakeMetric(m))
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,6 +30,17 @@ type OutputConfig struct {
 	FlushJitter       time.Duration
 	MetricBufferLimit int
 	MetricBatchSize   int
+	BufferStrategy    string
+	BufferDirectory   string
+
+	StartupErrorBehavior string
+	LogLevel             string
+
+	RetryInitialBackoff        time.Duration
+	RetryMaxBackoff            time.Duration
+	RetryJitter                time.Duration
+	CircuitBreakerThreshold    int
+	CircuitBreakerResetTimeout time.Duration
 
 	NameOverride string
 	NamePrefix   string
@@ -47,20 +60,50 @@ type RunningOutput struct {
 
 	MetricsFiltered selfstat.Stat
 	WriteTime       selfstat.Stat
+	StartupErrors   selfstat.Stat
+	WriteRetries    selfstat.Stat
+	CircuitState    selfstat.Stat
 
 	BatchReady chan time.Time
 
-	buffer *Buffer
-	log    telegraf.Logger
+	buffer Buffer
+	log    *Logger
+	retry  startupRetry
+	paused atomic.Bool
+
+	writeRetry *writeRetry
+
+	lastErr     error
+	lastErrLock sync.Mutex
 
 	aggMutex sync.Mutex
 }
 
+// NewRunningOutput creates a running output including its metric buffer.
 func NewRunningOutput(
 	output telegraf.Output,
 	config *OutputConfig,
 	batchSize int,
 	bufferLimit int,
+) (*RunningOutput, error) {
+	ro := NewDeferredRunningOutput(output, config, batchSize, bufferLimit)
+	if err := ro.OpenBuffer(); err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+// NewDeferredRunningOutput creates a running output without its metric
+// buffer. Creating the buffer has side effects like creating the buffer
+// directory, locking the write-ahead log and resetting the buffer statistics
+// shared with running instances of the same output. Therefore, the buffer is
+// only opened using OpenBuffer once the output is actually used, allowing to
+// check configurations or to compare them to the running outputs.
+func NewDeferredRunningOutput(
+	output telegraf.Output,
+	config *OutputConfig,
+	batchSize int,
+	bufferLimit int,
 ) *RunningOutput {
 	tags := map[string]string{"output": config.Name}
 	if config.Alias != "" {
@@ -88,7 +131,6 @@ func NewRunningOutput(
 	}
 
 	ro := &RunningOutput{
-		buffer:            NewBuffer(config.Name, config.Alias, bufferLimit),
 		BatchReady:        make(chan time.Time, 1),
 		Output:            output,
 		Config:            config,
@@ -104,12 +146,44 @@ func NewRunningOutput(
 			"write_time_ns",
 			tags,
 		),
-		log: logger,
+		StartupErrors: selfstat.Register(
+			"write",
+			"startup_errors",
+			tags,
+		),
+		WriteRetries: selfstat.Register(
+			"write",
+			"retries",
+			tags,
+		),
+		CircuitState: selfstat.Register(
+			"write",
+			"circuit_state",
+			tags,
+		),
+		writeRetry: newWriteRetry(config),
+		log:        logger,
 	}
 
 	return ro
 }
 
+// OpenBuffer creates the metric buffer of an output created without it. It
+// does nothing if the buffer already exists.
+func (r *RunningOutput) OpenBuffer() error {
+	if r.buffer != nil {
+		return nil
+	}
+
+	c := r.Config
+	b, err := NewBuffer(c.Name, c.ID, c.Alias, r.MetricBufferLimit, c.BufferStrategy, c.BufferDirectory)
+	if err != nil {
+		return fmt.Errorf("creating buffer failed: %w", err)
+	}
+	r.buffer = b
+	return nil
+}
+
 func (r *RunningOutput) LogName() string {
 	return logName("outputs", r.Config.Name, r.Config.Alias)
 }
@@ -120,6 +194,16 @@ func (r *RunningOutput) metricFiltered(metric telegraf.Metric) {
 }
 
 func (r *RunningOutput) Init() error {
+	if err := checkStartupErrorBehavior(r.Config.StartupErrorBehavior); err != nil {
+		return err
+	}
+	if err := checkRetryPolicy(r.Config); err != nil {
+		return err
+	}
+	if err := r.log.SetLogLevel(r.Config.LogLevel); err != nil {
+		return err
+	}
+
 	if p, ok := r.Output.(telegraf.Initializer); ok {
 		err := p.Init()
 		if err != nil {
@@ -186,8 +270,24 @@ func (r *RunningOutput) AddMetric(metric telegraf.Metric) {
 }
 
 // Write writes all metrics to the output, stopping when all have been sent on
-// or error.
+// or error. Nothing is written if the output is paused or waiting to retry a
+// failed write or connection.
 func (r *RunningOutput) Write() error {
+	return r.write(false)
+}
+
+// WriteFinal writes all metrics to the output when stopping the agent. Unlike
+// Write, it ignores the pause state and the retry policy of the output to not
+// lose the buffered metrics.
+func (r *RunningOutput) WriteFinal() error {
+	return r.write(true)
+}
+
+func (r *RunningOutput) write(force bool) error {
+	if r.paused.Load() && !force {
+		return nil
+	}
+
 	if output, ok := r.Output.(telegraf.AggregatingOutput); ok {
 		r.aggMutex.Lock()
 		metrics := output.Push()
@@ -198,10 +298,21 @@ func (r *RunningOutput) Write() error {
 
 	atomic.StoreInt64(&r.newMetricsCount, 0)
 
+	if connected, err := r.retryConnect(force); !connected {
+		return err
+	}
+	if !force && !r.writeAllowed() {
+		return nil
+	}
+
 	// Only process the metrics in the buffer now.  Metrics added while we are
-	// writing will be sent on the next call.
+	// writing will be sent on the next call. A half-open circuit is probed
+	// with a single batch.
 	nBuffer := r.buffer.Len()
 	nBatches := nBuffer/r.MetricBatchSize + 1
+	if !force && r.writeRetry.state.Load() == circuitHalfOpen {
+		nBatches = 1
+	}
 	for i := 0; i < nBatches; i++ {
 		batch := r.buffer.Batch(r.MetricBatchSize)
 		if len(batch) == 0 {
@@ -220,6 +331,17 @@ func (r *RunningOutput) Write() error {
 
 // WriteBatch writes a single batch of metrics to the output.
 func (r *RunningOutput) WriteBatch() error {
+	if r.paused.Load() {
+		return nil
+	}
+
+	if connected, err := r.retryConnect(false); !connected {
+		return err
+	}
+	if !r.writeAllowed() {
+		return nil
+	}
+
 	batch := r.buffer.Batch(r.MetricBatchSize)
 	if len(batch) == 0 {
 		return nil
@@ -235,14 +357,100 @@ func (r *RunningOutput) WriteBatch() error {
 	return nil
 }
 
+// Connect connects the output. Errors are handled according to the configured
+// startup error behavior. For the "retry" behavior, connecting is retried with
+// increasing delay on subsequent writes while metrics are buffered, for the
+// "ignore" behavior an error wrapping ErrPluginSkipped is returned.
+func (r *RunningOutput) Connect() error {
+	err := r.Output.Connect()
+	if err == nil {
+		return nil
+	}
+	r.StartupErrors.Incr(1)
+
+	switch r.Config.StartupErrorBehavior {
+	case "retry":
+		r.retry.failed()
+		r.log.Warnf("Connecting failed: %v; retrying in %s", err, r.retry.backoff)
+		return nil
+	case "ignore":
+		return fmt.Errorf("%w: %w", ErrPluginSkipped, err)
+	}
+	return err
+}
+
+// retryConnect retries to connect the output if a previous attempt failed and
+// returns true if the output is connected. Forced attempts ignore the backoff.
+func (r *RunningOutput) retryConnect(force bool) (bool, error) {
+	if !r.retry.retrying() {
+		return true, nil
+	}
+	if !force && !r.retry.due() {
+		return false, nil
+	}
+
+	if err := r.Output.Connect(); err != nil {
+		r.StartupErrors.Incr(1)
+		r.retry.failed()
+		err = fmt.Errorf("connecting failed, retrying in %s: %w", r.retry.backoff, err)
+		r.setLastError(err)
+		return false, err
+	}
+	r.retry.succeeded()
+	r.log.Info("Successfully connected")
+	return true, nil
+}
+
 // Close closes the output
 func (r *RunningOutput) Close() {
-	err := r.Output.Close()
-	if err != nil {
+	r.closeOutput()
+	r.closeBuffer()
+}
+
+// Remove closes the output like Close but deletes a disk buffer instead of
+// keeping it for the next start, e.g. when the output is removed from the
+// configuration on reload. Metrics left in the buffer are dropped.
+func (r *RunningOutput) Remove() {
+	b, ok := r.buffer.(*DiskBuffer)
+	if !ok {
+		r.Close()
+		return
+	}
+
+	r.closeOutput()
+	if n := b.Len(); n > 0 {
+		r.log.Warnf("Dropping %d unwritten metrics of removed output", n)
+	}
+	if err := b.Remove(); err != nil {
+		r.log.Errorf("Error removing output buffer: %v", err)
+	}
+}
+
+func (r *RunningOutput) closeOutput() {
+	// Outputs still waiting for a connection were never connected
+	if r.retry.retrying() {
+		return
+	}
+	if err := r.Output.Close(); err != nil {
 		r.log.Errorf("Error closing output: %v", err)
 	}
 }
 
+// Discard releases the resources of an output that was never connected, e.g.
+// if it is superseded by an already running instance when reloading.
+func (r *RunningOutput) Discard() {
+	r.closeBuffer()
+}
+
+func (r *RunningOutput) closeBuffer() {
+	if r.buffer == nil {
+		return
+	}
+	if err := r.buffer.Close(); err != nil {
+		r.log.Errorf("Error closing output buffer: %v", err)
+	}
+}
+
 func (r *RunningOutput) writeMetrics(metrics []telegraf.Metric) error {
 	dropped := atomic.LoadInt64(&r.droppedMetrics)
 	if dropped > 0 {
@@ -255,10 +463,140 @@ func (r *RunningOutput) writeMetrics(metrics []telegraf.Metric) error {
 	elapsed := time.Since(start)
 	r.WriteTime.Incr(elapsed.Nanoseconds())
 
-	if err == nil {
-		r.log.Debugf("Wrote batch of %d metrics in %s", len(metrics), elapsed)
+	if err != nil {
+		r.setLastError(err)
+		r.writeFailed()
+		var authErr *telegraf.AuthenticationError
+		if errors.As(err, &authErr) {
+			r.reconnect()
+		}
+		return err
 	}
-	return err
+	r.log.Debugf("Wrote batch of %d metrics in %s", len(metrics), elapsed)
+	r.writeSucceeded()
+	return nil
+}
+
+// reconnect refreshes the rotatable secrets of the output after an
+// authentication failure and reconnects the output to use the current
+// credentials. A failing connection is retried according to the startup
+// retry policy.
+func (r *RunningOutput) reconnect() {
+	n, err := rotateSecrets(r.Output)
+	if err != nil {
+		r.log.Errorf("Refreshing secrets failed: %v", err)
+	}
+	if n == 0 {
+		return
+	}
+
+	r.log.Infof("Authentication failed; reconnecting with %d refreshed secret(s)", n)
+	if err := r.Output.Close(); err != nil {
+		r.log.Errorf("Error closing output: %v", err)
+	}
+	if err := r.Output.Connect(); err != nil {
+		r.StartupErrors.Incr(1)
+		r.retry.failed()
+		r.log.Warnf("Reconnecting failed: %v; retrying in %s", err, r.retry.backoff)
+	}
+}
+
+// writeAllowed returns true if writing should be attempted according to the
+// retry policy of the output.
+func (r *RunningOutput) writeAllowed() bool {
+	allowed := r.writeRetry.allowed(time.Now())
+	r.CircuitState.Set(int64(r.writeRetry.state.Load()))
+	if allowed && r.writeRetry.retrying() {
+		r.WriteRetries.Incr(1)
+	}
+	return allowed
+}
+
+func (r *RunningOutput) writeFailed() {
+	halfOpen := r.writeRetry.state.Load() == circuitHalfOpen
+	delay := r.writeRetry.failed(time.Now())
+	r.CircuitState.Set(int64(r.writeRetry.state.Load()))
+
+	switch {
+	case r.writeRetry.state.Load() != circuitOpen:
+		if delay > 0 {
+			r.log.Debugf("Retrying write in %s", delay)
+		}
+	case halfOpen:
+		r.log.Warnf("Probing output failed; retrying in %s", delay)
+	default:
+		r.log.Warnf("Suspending writes after %d consecutive failures; retrying in %s", r.writeRetry.failures, delay)
+	}
+}
+
+func (r *RunningOutput) writeSucceeded() {
+	if r.writeRetry.state.Load() != circuitClosed {
+		r.log.Info("Output recovered; resuming writes")
+	}
+	r.writeRetry.succeeded()
+	r.CircuitState.Set(int64(circuitClosed))
+}
+
+func (r *RunningOutput) setLastError(err error) {
+	r.lastErrLock.Lock()
+	r.lastErr = err
+	r.lastErrLock.Unlock()
+}
+
+// LastError returns the most recent error of writing to the output or nil if
+// no write failed so far.
+func (r *RunningOutput) LastError() error {
+	r.lastErrLock.Lock()
+	defer r.lastErrLock.Unlock()
+	return r.lastErr
+}
+
+// Pause stops writing metrics to the output until Resume is called. Metrics
+// are buffered in the meantime and the oldest ones are dropped once the buffer
+// is full. The buffered metrics are still written when stopping the output.
+func (r *RunningOutput) Pause() {
+	r.paused.Store(true)
+}
+
+// Resume continues writing metrics to a paused output.
+func (r *RunningOutput) Resume() {
+	r.paused.Store(false)
+}
+
+// Paused returns true if the output is paused.
+func (r *RunningOutput) Paused() bool {
+	return r.paused.Load()
+}
+
+// State returns the state of the output, either "running", "paused",
+// "connecting" if the output is waiting to retry a failed connection or
+// "suspended" if writes are suspended by the circuit breaker.
+func (r *RunningOutput) State() string {
+	switch {
+	case r.paused.Load():
+		return "paused"
+	case r.retry.retrying():
+		return "connecting"
+	case r.writeRetry.state.Load() != circuitClosed:
+		return "suspended"
+	}
+	return "running"
+}
+
+// RetryDelay returns the time until the output attempts to write again after a
+// failed write or connection. Writes before are skipped. The delay is zero if
+// writing is not delayed.
+func (r *RunningOutput) RetryDelay() time.Duration {
+	if delay := r.retry.delay(); delay > 0 {
+		return delay
+	}
+	return r.writeRetry.delay(time.Now())
+}
+
+// Healthy returns true if the output is running and the last write attempt
+// succeeded.
+func (r *RunningOutput) Healthy() bool {
+	return !r.paused.Load() && !r.retry.retrying() && !r.writeRetry.retrying()
 }
 
 func (r *RunningOutput) LogBufferStatus() {
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -36,7 +39,8 @@ func BenchmarkRunningOutputAddWrite(b *testing.B) {
 	}
 
 	m := &perfOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(b, err)
 
 	for n := 0; n < b.N; n++ {
 		ro.AddMetric(testutil.TestMetric(101, "metric1"))
@@ -51,7 +55,8 @@ func BenchmarkRunningOutputAddWriteEvery100(b *testing.B) {
 	}
 
 	m := &perfOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(b, err)
 
 	for n := 0; n < b.N; n++ {
 		ro.AddMetric(testutil.TestMetric(101, "metric1"))
@@ -69,7 +74,8 @@ func BenchmarkRunningOutputAddFailWrites(b *testing.B) {
 
 	m := &perfOutput{}
 	m.failWrite = true
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(b, err)
 
 	for n := 0; n < b.N; n++ {
 		ro.AddMetric(testutil.TestMetric(101, "metric1"))
@@ -86,7 +92,8 @@ func TestRunningOutput_DropFilter(t *testing.T) {
 	require.NoError(t, conf.Filter.Compile())
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	for _, metric := range first5 {
 		ro.AddMetric(metric)
@@ -96,7 +103,7 @@ func TestRunningOutput_DropFilter(t *testing.T) {
 	}
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 8)
 }
@@ -111,7 +118,8 @@ func TestRunningOutput_PassFilter(t *testing.T) {
 	require.NoError(t, conf.Filter.Compile())
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	for _, metric := range first5 {
 		ro.AddMetric(metric)
@@ -121,7 +129,7 @@ func TestRunningOutput_PassFilter(t *testing.T) {
 	}
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 10)
 }
@@ -136,12 +144,13 @@ func TestRunningOutput_TagIncludeNoMatch(t *testing.T) {
 	require.NoError(t, conf.Filter.Compile())
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	ro.AddMetric(testutil.TestMetric(101, "metric1"))
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 1)
 	require.Empty(t, m.Metrics()[0].Tags())
@@ -157,12 +166,13 @@ func TestRunningOutput_TagExcludeMatch(t *testing.T) {
 	require.NoError(t, conf.Filter.Compile())
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	ro.AddMetric(testutil.TestMetric(101, "metric1"))
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 1)
 	require.Len(t, m.Metrics()[0].Tags(), 0)
@@ -178,12 +188,13 @@ func TestRunningOutput_TagExcludeNoMatch(t *testing.T) {
 	require.NoError(t, conf.Filter.Compile())
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	ro.AddMetric(testutil.TestMetric(101, "metric1"))
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 1)
 	require.Len(t, m.Metrics()[0].Tags(), 1)
@@ -199,12 +210,13 @@ func TestRunningOutput_TagIncludeMatch(t *testing.T) {
 	require.NoError(t, conf.Filter.Compile())
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	ro.AddMetric(testutil.TestMetric(101, "metric1"))
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 1)
 	require.Len(t, m.Metrics()[0].Tags(), 1)
@@ -217,12 +229,13 @@ func TestRunningOutput_NameOverride(t *testing.T) {
 	}
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	ro.AddMetric(testutil.TestMetric(101, "metric1"))
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 1)
 	require.Equal(t, "new_metric_name", m.Metrics()[0].Name())
@@ -235,12 +248,13 @@ func TestRunningOutput_NamePrefix(t *testing.T) {
 	}
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	ro.AddMetric(testutil.TestMetric(101, "metric1"))
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 1)
 	require.Equal(t, "prefix_metric1", m.Metrics()[0].Name())
@@ -253,12 +267,13 @@ func TestRunningOutput_NameSuffix(t *testing.T) {
 	}
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	ro.AddMetric(testutil.TestMetric(101, "metric1"))
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 1)
 	require.Equal(t, "metric1_suffix", m.Metrics()[0].Name())
@@ -271,7 +286,8 @@ func TestRunningOutputDefault(t *testing.T) {
 	}
 
 	m := &mockOutput{}
-	ro := NewRunningOutput(m, conf, 1000, 10000)
+	ro, err := NewRunningOutput(m, conf, 1000, 10000)
+	require.NoError(t, err)
 
 	for _, metric := range first5 {
 		ro.AddMetric(metric)
@@ -281,7 +297,7 @@ func TestRunningOutputDefault(t *testing.T) {
 	}
 	require.Len(t, m.Metrics(), 0)
 
-	err := ro.Write()
+	err = ro.Write()
 	require.NoError(t, err)
 	require.Len(t, m.Metrics(), 10)
 }
@@ -293,7 +309,8 @@ func TestRunningOutputWriteFail(t *testing.T) {
 
 	m := &mockOutput{}
 	m.failWrite = true
-	ro := NewRunningOutput(m, conf, 4, 12)
+	ro, err := NewRunningOutput(m, conf, 4, 12)
+	require.NoError(t, err)
 
 	// Fill buffer to limit twice
 	for _, metric := range first5 {
@@ -306,7 +323,7 @@ func TestRunningOutputWriteFail(t *testing.T) {
 	require.Len(t, m.Metrics(), 0)
 
 	// manual write fails
-	err := ro.Write()
+	err = ro.Write()
 	require.Error(t, err)
 	// no successful flush yet
 	require.Len(t, m.Metrics(), 0)
@@ -318,6 +335,35 @@ func TestRunningOutputWriteFail(t *testing.T) {
 	require.Len(t, m.Metrics(), 10)
 }
 
+func TestRunningOutputDiskBufferReplay(t *testing.T) {
+	conf := &OutputConfig{
+		Filter:          Filter{},
+		ID:              "disk_test",
+		BufferStrategy:  "disk",
+		BufferDirectory: t.TempDir(),
+	}
+
+	m := &mockOutput{}
+	m.failWrite = true
+	ro, err := NewRunningOutput(m, conf, 4, 12)
+	require.NoError(t, err)
+	for _, metric := range first5 {
+		ro.AddMetric(metric)
+	}
+	require.Error(t, ro.Write())
+	ro.Close()
+
+	// Metrics must be written after restarting the output
+	m.failWrite = false
+	ro, err = NewRunningOutput(m, conf, 4, 12)
+	require.NoError(t, err)
+	defer ro.Close()
+	require.Equal(t, 5, ro.BufferLength())
+	require.NoError(t, ro.Write())
+	require.Equal(t, 0, ro.BufferLength())
+	testutil.RequireMetricsEqual(t, first5, m.Metrics())
+}
+
 // Verify that the order of points is preserved during write failure.
 func TestRunningOutputWriteFailOrder(t *testing.T) {
 	conf := &OutputConfig{
@@ -326,7 +372,8 @@ func TestRunningOutputWriteFailOrder(t *testing.T) {
 
 	m := &mockOutput{}
 	m.failWrite = true
-	ro := NewRunningOutput(m, conf, 100, 1000)
+	ro, err := NewRunningOutput(m, conf, 100, 1000)
+	require.NoError(t, err)
 
 	// add 5 metrics
 	for _, metric := range first5 {
@@ -336,7 +383,7 @@ func TestRunningOutputWriteFailOrder(t *testing.T) {
 	require.Len(t, m.Metrics(), 0)
 
 	// Write fails
-	err := ro.Write()
+	err = ro.Write()
 	require.Error(t, err)
 	// no successful flush yet
 	require.Len(t, m.Metrics(), 0)
@@ -364,14 +411,15 @@ func TestRunningOutputWriteFailOrder2(t *testing.T) {
 
 	m := &mockOutput{}
 	m.failWrite = true
-	ro := NewRunningOutput(m, conf, 5, 100)
+	ro, err := NewRunningOutput(m, conf, 5, 100)
+	require.NoError(t, err)
 
 	// add 5 metrics
 	for _, metric := range first5 {
 		ro.AddMetric(metric)
 	}
 	// Write fails
-	err := ro.Write()
+	err = ro.Write()
 	require.Error(t, err)
 	// no successful flush yet
 	require.Len(t, m.Metrics(), 0)
@@ -428,7 +476,8 @@ func TestRunningOutputWriteFailOrder3(t *testing.T) {
 
 	m := &mockOutput{}
 	m.failWrite = true
-	ro := NewRunningOutput(m, conf, 5, 1000)
+	ro, err := NewRunningOutput(m, conf, 5, 1000)
+	require.NoError(t, err)
 
 	// add 5 metrics
 	for _, metric := range first5 {
@@ -438,7 +487,7 @@ func TestRunningOutputWriteFailOrder3(t *testing.T) {
 	require.Len(t, m.Metrics(), 0)
 
 	// Write fails
-	err := ro.Write()
+	err = ro.Write()
 	require.Error(t, err)
 	// no successful flush yet
 	require.Len(t, m.Metrics(), 0)
@@ -461,7 +510,7 @@ func TestRunningOutputWriteFailOrder3(t *testing.T) {
 }
 
 func TestInternalMetrics(t *testing.T) {
-	_ = NewRunningOutput(
+	_, err := NewRunningOutput(
 		&mockOutput{},
 		&OutputConfig{
 			Filter: Filter{},
@@ -470,6 +519,7 @@ func TestInternalMetrics(t *testing.T) {
 		},
 		5,
 		10)
+	require.NoError(t, err)
 
 	expected := []telegraf.Metric{
 		testutil.MustMetric(
@@ -486,6 +536,9 @@ func TestInternalMetrics(t *testing.T) {
 				"metrics_dropped":  0,
 				"metrics_filtered": 0,
 				"metrics_written":  0,
+				"startup_errors":   0,
+				"retries":          0,
+				"circuit_state":    0,
 				"write_time_ns":    0,
 			},
 			time.Unix(0, 0),
@@ -503,6 +556,344 @@ func TestInternalMetrics(t *testing.T) {
 	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
 }
 
+func TestRunningOutputStartupErrorBehavior(t *testing.T) {
+	tests := []struct {
+		behavior string
+		skipped  bool
+		failed   bool
+	}{
+		{behavior: "", failed: true},
+		{behavior: "error", failed: true},
+		{behavior: "ignore", skipped: true},
+		{behavior: "retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior, func(t *testing.T) {
+			m := &mockOutput{failConnect: true}
+			conf := &OutputConfig{
+				Name:                 "startup_error_" + tt.behavior,
+				StartupErrorBehavior: tt.behavior,
+			}
+			ro, err := NewRunningOutput(m, conf, 10, 100)
+			require.NoError(t, err)
+			require.NoError(t, ro.Init())
+
+			err = ro.Connect()
+			require.Equal(t, tt.failed || tt.skipped, err != nil)
+			require.Equal(t, tt.skipped, errors.Is(err, ErrPluginSkipped))
+			require.Equal(t, int64(1), ro.StartupErrors.Get())
+		})
+	}
+}
+
+func TestRunningOutputStartupErrorRetry(t *testing.T) {
+	m := &mockOutput{failConnect: true}
+	conf := &OutputConfig{
+		Name:                 "startup_error_retry_write",
+		StartupErrorBehavior: "retry",
+	}
+	ro, err := NewRunningOutput(m, conf, 10, 100)
+	require.NoError(t, err)
+	require.NoError(t, ro.Init())
+	require.NoError(t, ro.Connect())
+
+	// Metrics are buffered until the output is connected
+	for _, metric := range first5 {
+		ro.AddMetric(metric)
+	}
+	require.NoError(t, ro.Write())
+	require.Empty(t, m.Metrics())
+	require.Equal(t, 5, ro.BufferLength())
+
+	// Failing retries are reported
+	ro.retry.next = time.Now()
+	require.ErrorContains(t, ro.Write(), "failed connect")
+	require.Equal(t, int64(2), ro.StartupErrors.Get())
+	require.Equal(t, 2*startupRetryMinBackoff, ro.retry.backoff)
+
+	m.failConnect = false
+	ro.retry.next = time.Now()
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 5)
+	require.False(t, ro.retry.retrying())
+}
+
+func TestRunningOutputInvalidBuffer(t *testing.T) {
+	// A file blocking the buffer directory must not crash the agent
+	fn := filepath.Join(t.TempDir(), "blocked")
+	require.NoError(t, os.WriteFile(fn, nil, 0600))
+
+	conf := &OutputConfig{
+		Filter:          Filter{},
+		ID:              "disk_test",
+		BufferStrategy:  "disk",
+		BufferDirectory: fn,
+	}
+	_, err := NewRunningOutput(&mockOutput{}, conf, 10, 100)
+	require.ErrorContains(t, err, "creating buffer failed")
+
+	_, err = NewRunningOutput(&mockOutput{}, &OutputConfig{BufferStrategy: "foo"}, 10, 100)
+	require.ErrorContains(t, err, `invalid buffer strategy "foo"`)
+}
+
+func TestRunningOutputInvalidStartupErrorBehavior(t *testing.T) {
+	ro, err := NewRunningOutput(&mockOutput{}, &OutputConfig{StartupErrorBehavior: "foo"}, 10, 100)
+	require.NoError(t, err)
+	require.ErrorContains(t, ro.Init(), "invalid 'startup_error_behavior' setting")
+}
+
+func TestRunningOutputPause(t *testing.T) {
+	m := &mockOutput{}
+	ro, err := NewRunningOutput(m, &OutputConfig{Name: "pause"}, 10, 100)
+	require.NoError(t, err)
+	for _, metric := range first5 {
+		ro.AddMetric(metric)
+	}
+
+	// Metrics are kept in the buffer while paused
+	ro.Pause()
+	require.Equal(t, "paused", ro.State())
+	require.NoError(t, ro.Write())
+	require.Empty(t, m.Metrics())
+	require.Equal(t, 5, ro.BufferLength())
+
+	ro.Resume()
+	require.Equal(t, "running", ro.State())
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 5)
+
+	// The final write ignores the pause state
+	ro.Pause()
+	ro.AddMetric(testutil.TestMetric(101, "metric1"))
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 5)
+	require.NoError(t, ro.WriteFinal())
+	require.Len(t, m.Metrics(), 6)
+}
+
+func TestRunningOutputLastError(t *testing.T) {
+	m := &mockOutput{failWrite: true}
+	ro, err := NewRunningOutput(m, &OutputConfig{Name: "last_error"}, 10, 100)
+	require.NoError(t, err)
+	require.NoError(t, ro.LastError())
+
+	ro.AddMetric(testutil.TestMetric(101, "metric1"))
+	require.Error(t, ro.Write())
+	require.ErrorContains(t, ro.LastError(), "failed write")
+}
+
+func TestRunningOutputRetryBackoff(t *testing.T) {
+	m := &mockOutput{failWrite: true}
+	conf := &OutputConfig{
+		Name:                "retry_backoff",
+		RetryInitialBackoff: time.Second,
+		RetryMaxBackoff:     3 * time.Second,
+	}
+	ro, err := NewRunningOutput(m, conf, 10, 100)
+	require.NoError(t, err)
+	require.NoError(t, ro.Init())
+	for _, metric := range first5 {
+		ro.AddMetric(metric)
+	}
+
+	require.Error(t, ro.Write())
+	require.WithinDuration(t, time.Now().Add(time.Second), ro.writeRetry.next, 100*time.Millisecond)
+
+	// Writes are skipped during the backoff
+	require.NoError(t, ro.Write())
+	require.Zero(t, ro.WriteRetries.Get())
+	require.Equal(t, 5, ro.BufferLength())
+
+	// The delay doubles with every failure up to the maximum backoff
+	for _, expected := range []time.Duration{2 * time.Second, 3 * time.Second} {
+		ro.writeRetry.next = time.Now()
+		require.Error(t, ro.Write())
+		require.WithinDuration(t, time.Now().Add(expected), ro.writeRetry.next, 100*time.Millisecond)
+	}
+	require.Equal(t, int64(2), ro.WriteRetries.Get())
+
+	m.failWrite = false
+	ro.writeRetry.next = time.Now()
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 5)
+	require.Equal(t, int64(3), ro.WriteRetries.Get())
+	require.False(t, ro.writeRetry.retrying())
+}
+
+func TestRunningOutputWriteFinal(t *testing.T) {
+	m := &mockOutput{failWrite: true}
+	conf := &OutputConfig{
+		Name:                       "write_final",
+		CircuitBreakerThreshold:    1,
+		CircuitBreakerResetTimeout: time.Minute,
+	}
+	ro, err := NewRunningOutput(m, conf, 2, 100)
+	require.NoError(t, err)
+	require.NoError(t, ro.Init())
+	for _, metric := range first5 {
+		ro.AddMetric(metric)
+	}
+
+	require.Error(t, ro.Write())
+	require.Equal(t, "suspended", ro.State())
+	require.InDelta(t, time.Minute.Seconds(), ro.RetryDelay().Seconds(), 1)
+
+	// The final write ignores the open circuit and writes all metrics
+	m.failWrite = false
+	require.NoError(t, ro.Write())
+	require.Empty(t, m.Metrics())
+	require.NoError(t, ro.WriteFinal())
+	require.Len(t, m.Metrics(), 5)
+	require.Zero(t, ro.RetryDelay())
+}
+
+func TestRunningOutputCircuitBreaker(t *testing.T) {
+	m := &mockOutput{failWrite: true}
+	conf := &OutputConfig{
+		Name:                       "circuit_breaker",
+		CircuitBreakerThreshold:    2,
+		CircuitBreakerResetTimeout: time.Minute,
+	}
+	ro, err := NewRunningOutput(m, conf, 2, 100)
+	require.NoError(t, err)
+	require.NoError(t, ro.Init())
+	for _, metric := range first5 {
+		ro.AddMetric(metric)
+	}
+
+	// The circuit opens after the given number of consecutive failures
+	require.Error(t, ro.Write())
+	require.Equal(t, "running", ro.State())
+	require.Error(t, ro.Write())
+	require.Equal(t, "suspended", ro.State())
+	require.Equal(t, int64(circuitOpen), ro.CircuitState.Get())
+	require.WithinDuration(t, time.Now().Add(time.Minute), ro.writeRetry.next, 100*time.Millisecond)
+
+	// Writes are suspended while the circuit is open
+	m.failWrite = false
+	require.NoError(t, ro.Write())
+	require.Empty(t, m.Metrics())
+
+	// A failed probe opens the circuit again
+	m.failWrite = true
+	ro.writeRetry.next = time.Now()
+	require.Error(t, ro.Write())
+	require.Equal(t, int64(circuitOpen), ro.CircuitState.Get())
+	require.WithinDuration(t, time.Now().Add(time.Minute), ro.writeRetry.next, 100*time.Millisecond)
+
+	// A successful probe writes a single batch and closes the circuit
+	m.failWrite = false
+	ro.writeRetry.next = time.Now()
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 2)
+	require.Equal(t, "running", ro.State())
+	require.Equal(t, int64(circuitClosed), ro.CircuitState.Get())
+
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 5)
+}
+
+func TestRunningOutputInvalidRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *OutputConfig
+		expected string
+	}{
+		{
+			name:     "negative backoff",
+			config:   &OutputConfig{RetryInitialBackoff: -time.Second},
+			expected: "retry settings must not be negative",
+		},
+		{
+			name:     "max smaller than initial",
+			config:   &OutputConfig{RetryInitialBackoff: time.Minute, RetryMaxBackoff: time.Second},
+			expected: "'retry_max_backoff' must not be smaller than 'retry_initial_backoff'",
+		},
+		{
+			name:     "negative threshold",
+			config:   &OutputConfig{CircuitBreakerThreshold: -1},
+			expected: "circuit breaker settings must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ro, err := NewRunningOutput(&mockOutput{}, tt.config, 10, 100)
+			require.NoError(t, err)
+			require.ErrorContains(t, ro.Init(), tt.expected)
+		})
+	}
+}
+
+func TestRunningOutputReconnectOnAuthError(t *testing.T) {
+	plugin := &mockAuthOutput{}
+	ro, err := NewRunningOutput(plugin, &OutputConfig{Filter: Filter{}}, 10, 100)
+	require.NoError(t, err)
+	require.NoError(t, ro.Init())
+	require.NoError(t, ro.Connect())
+	require.Equal(t, 1, plugin.connected)
+
+	// Outputs are reconnected after refreshing the secrets
+	ro.AddMetric(testutil.TestMetric(101, "metric1"))
+	var authErr *telegraf.AuthenticationError
+	require.ErrorAs(t, ro.Write(), &authErr)
+	require.Equal(t, 1, plugin.Token.rotated)
+	require.Equal(t, 1, plugin.Other.rotated)
+	require.Equal(t, 1, plugin.closed)
+	require.Equal(t, 2, plugin.connected)
+	require.Equal(t, 1, ro.BufferLength())
+
+	// Plugins without rotatable secrets are not reconnected
+	plugin.Token.static = true
+	plugin.Other.static = true
+	require.ErrorAs(t, ro.Write(), &authErr)
+	require.Equal(t, 1, plugin.closed)
+	require.Equal(t, 2, plugin.connected)
+}
+
+type mockSecret struct {
+	static  bool
+	rotated int
+}
+
+func (s *mockSecret) Rotate() (bool, error) {
+	if s.static {
+		return false, nil
+	}
+	s.rotated++
+	return true, nil
+}
+
+type mockAuthOutput struct {
+	Token mockSecret  `toml:"token"`
+	Other *mockSecret `toml:"other"`
+
+	connected int
+	closed    int
+}
+
+func (*mockAuthOutput) SampleConfig() string {
+	return ""
+}
+
+func (m *mockAuthOutput) Connect() error {
+	if m.Other == nil {
+		m.Other = &mockSecret{}
+	}
+	m.connected++
+	return nil
+}
+
+func (m *mockAuthOutput) Close() error {
+	m.closed++
+	return nil
+}
+
+func (*mockAuthOutput) Write([]telegraf.Metric) error {
+	return &telegraf.AuthenticationError{Err: errors.New("invalid token")}
+}
+
 type mockOutput struct {
 	sync.Mutex
 
@@ -510,9 +901,15 @@ type mockOutput struct {
 
 	// if true, mock write failure
 	failWrite bool
+
+	// if true, mock connect failure
+	failConnect bool
 }
 
 func (m *mockOutput) Connect() error {
+	if m.failConnect {
+		return errors.New("failed connect")
+	}
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"io"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -95,3 +96,24 @@ func (r *RunningParser) SetDefaultTags(tags map[string]string) {
 func (r *RunningParser) Log() telegraf.Logger {
 	return r.log
 }
+
+// RunningStreamParser is a running parser for parsers implementing the
+// telegraf.StreamParser interface.
+type RunningStreamParser struct {
+	*RunningParser
+}
+
+func (r *RunningStreamParser) ParseStream(reader io.Reader, fn func([]telegraf.Metric) error) error {
+	// Only account the time spent parsing, not processing the metrics
+	var processing time.Duration
+	start := time.Now()
+	err := r.Parser.(telegraf.StreamParser).ParseStream(reader, func(metrics []telegraf.Metric) error {
+		r.MetricsParsed.Incr(int64(len(metrics)))
+		processingStart := time.Now()
+		defer func() { processing += time.Since(processingStart) }()
+		return fn(metrics)
+	})
+	r.ParseTime.Incr((time.Since(start) - processing).Nanoseconds())
+
+	return err
+}
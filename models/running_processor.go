@@ -2,16 +2,35 @@ package models
 
 import (
 	"sync"
+	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/selfstat"
 )
 
+const (
+	// Default number of metrics passed to batch processors at once, batching
+	// is disabled by default.
+	DefaultProcessorBatchSize = 1
+
+	// Default time metrics are kept before passing them to batch processors.
+	DefaultProcessorBatchTimeout = 100 * time.Millisecond
+)
+
 type RunningProcessor struct {
 	sync.Mutex
-	log       telegraf.Logger
+	log       *Logger
 	Processor telegraf.StreamingProcessor
 	Config    *ProcessorConfig
+
+	// Batching of metrics for processors implementing telegraf.BatchProcessor
+	batchSize    int
+	batchTimeout time.Duration
+	batch        []telegraf.Metric
+	passthrough  []telegraf.Metric
+	acc          telegraf.Accumulator
+	done         chan struct{}
+	wg           sync.WaitGroup
 }
 
 type RunningProcessors []*RunningProcessor
@@ -22,11 +41,15 @@ func (rp RunningProcessors) Less(i, j int) bool { return rp[i].Config.Order < rp
 
 // ProcessorConfig containing a name and filter
 type ProcessorConfig struct {
-	Name   string
-	Alias  string
-	ID     string
-	Order  int64
-	Filter Filter
+	Name     string
+	Alias    string
+	ID       string
+	Order    int64
+	Filter   Filter
+	LogLevel string
+
+	BatchSize    int
+	BatchTimeout time.Duration
 }
 
 func NewRunningProcessor(processor telegraf.StreamingProcessor, config *ProcessorConfig) *RunningProcessor {
@@ -42,10 +65,21 @@ func NewRunningProcessor(processor telegraf.StreamingProcessor, config *Processo
 	})
 	SetLoggerOnPlugin(processor, logger)
 
+	batchSize := config.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultProcessorBatchSize
+	}
+	batchTimeout := config.BatchTimeout
+	if batchTimeout <= 0 {
+		batchTimeout = DefaultProcessorBatchTimeout
+	}
+
 	return &RunningProcessor{
-		Processor: processor,
-		Config:    config,
-		log:       logger,
+		Processor:    processor,
+		Config:       config,
+		log:          logger,
+		batchSize:    batchSize,
+		batchTimeout: batchTimeout,
 	}
 }
 
@@ -54,6 +88,9 @@ func (rp *RunningProcessor) metricFiltered(metric telegraf.Metric) {
 }
 
 func (rp *RunningProcessor) Init() error {
+	if err := rp.log.SetLogLevel(rp.Config.LogLevel); err != nil {
+		return err
+	}
 	if p, ok := rp.Processor.(telegraf.Initializer); ok {
 		err := p.Init()
 		if err != nil {
@@ -83,7 +120,87 @@ func (rp *RunningProcessor) MakeMetric(metric telegraf.Metric) telegraf.Metric {
 }
 
 func (rp *RunningProcessor) Start(acc telegraf.Accumulator) error {
-	return rp.Processor.Start(acc)
+	if err := rp.Processor.Start(acc); err != nil {
+		return err
+	}
+
+	if !rp.batching() {
+		return nil
+	}
+	rp.batch = make([]telegraf.Metric, 0, rp.batchSize)
+	rp.acc = acc
+	rp.done = make(chan struct{})
+
+	// Pass on incomplete batches after the timeout
+	rp.wg.Add(1)
+	go func() {
+		defer rp.wg.Done()
+
+		ticker := time.NewTicker(rp.batchTimeout)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-rp.done:
+				return
+			case <-ticker.C:
+				rp.Lock()
+				rp.flush(acc)
+				rp.Unlock()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// batching returns true if metrics should be passed to the processor in
+// batches.
+func (rp *RunningProcessor) batching() bool {
+	_, ok := rp.Processor.(telegraf.BatchProcessor)
+	return ok && rp.batchSize > 1
+}
+
+// flush passes the pending metrics to the batch processor followed by the
+// metrics not selected by the filter in the meantime. The lock must be held by
+// the caller.
+func (rp *RunningProcessor) flush(acc telegraf.Accumulator) {
+	if len(rp.batch) == 0 {
+		return
+	}
+
+	// The processor is responsible for all metrics of the batch even in case
+	// of errors, so errors are only reported.
+	if err := rp.Processor.(telegraf.BatchProcessor).AddBatch(rp.batch, acc); err != nil {
+		acc.AddError(err)
+	}
+	for i := range rp.batch {
+		rp.batch[i] = nil
+	}
+	rp.batch = rp.batch[:0]
+
+	for i, m := range rp.passthrough {
+		acc.AddMetric(m)
+		rp.passthrough[i] = nil
+	}
+	rp.passthrough = rp.passthrough[:0]
+}
+
+// pass sends a metric not selected by the filter downstream. While a batch is
+// pending, the metric is held back until the batch is processed so it does not
+// overtake metrics received earlier.
+func (rp *RunningProcessor) pass(m telegraf.Metric, acc telegraf.Accumulator) {
+	if rp.done == nil {
+		acc.AddMetric(m)
+		return
+	}
+
+	rp.Lock()
+	defer rp.Unlock()
+	if len(rp.batch) == 0 {
+		acc.AddMetric(m)
+		return
+	}
+	rp.passthrough = append(rp.passthrough, m)
 }
 
 func (rp *RunningProcessor) Add(m telegraf.Metric, acc telegraf.Accumulator) error {
@@ -92,7 +209,7 @@ func (rp *RunningProcessor) Add(m telegraf.Metric, acc telegraf.Accumulator) err
 		rp.log.Errorf("filtering failed: %v", err)
 	} else if !ok {
 		// pass downstream
-		acc.AddMetric(m)
+		rp.pass(m, acc)
 		return nil
 	}
 
@@ -103,9 +220,29 @@ func (rp *RunningProcessor) Add(m telegraf.Metric, acc telegraf.Accumulator) err
 		return nil
 	}
 
-	return rp.Processor.Add(m, acc)
+	if rp.done == nil {
+		return rp.Processor.Add(m, acc)
+	}
+
+	rp.Lock()
+	defer rp.Unlock()
+	rp.batch = append(rp.batch, m)
+	if len(rp.batch) >= rp.batchSize {
+		rp.flush(acc)
+	}
+	return nil
 }
 
 func (rp *RunningProcessor) Stop() {
+	if rp.done != nil {
+		close(rp.done)
+		rp.wg.Wait()
+		rp.done = nil
+
+		// Pass on the pending metrics before stopping the processor
+		rp.Lock()
+		rp.flush(rp.acc)
+		rp.Unlock()
+	}
 	rp.Processor.Stop()
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/models"
 	"github.com/influxdata/telegraf/plugins/processors"
 	"github.com/influxdata/telegraf/testutil"
@@ -56,6 +57,7 @@ func TestRunningProcessor_Init(t *testing.T) {
 	mock := MockProcessorToInit{}
 	rp := &models.RunningProcessor{
 		Processor: processors.NewStreamingProcessorFromProcessor(&mock),
+		Config:    &models.ProcessorConfig{Name: "init"},
 	}
 	err := rp.Init()
 	require.NoError(t, err)
@@ -231,3 +233,102 @@ func TestRunningProcessor_Order(t *testing.T) {
 		models.RunningProcessors{rp1, rp2, rp3},
 		procs)
 }
+
+// MockBatchProcessor is a Processor also processing batches of metrics.
+type MockBatchProcessor struct {
+	MockProcessor
+	Batches [][]string
+}
+
+func (p *MockBatchProcessor) AddBatch(metrics []telegraf.Metric, acc telegraf.Accumulator) error {
+	names := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		names = append(names, m.Name())
+		acc.AddMetric(m)
+	}
+	p.Batches = append(p.Batches, names)
+	return nil
+}
+
+func TestRunningProcessor_Batch(t *testing.T) {
+	mock := &MockBatchProcessor{}
+	rp := models.NewRunningProcessor(
+		processors.NewStreamingProcessorFromProcessor(mock),
+		&models.ProcessorConfig{
+			Name:         "batch",
+			BatchSize:    2,
+			BatchTimeout: time.Hour,
+			Filter:       models.Filter{NameDrop: []string{"skip"}},
+		},
+	)
+	require.NoError(t, rp.Config.Filter.Compile())
+
+	var acc testutil.Accumulator
+	require.NoError(t, rp.Start(&acc))
+	for _, name := range []string{"skip", "a", "skip", "b", "c", "skip"} {
+		m := metric.New(name, map[string]string{}, map[string]interface{}{"value": 1}, time.Unix(0, 0))
+		require.NoError(t, rp.Add(m, &acc))
+	}
+
+	// Full batches are processed immediately, filtered metrics are passed on
+	// without overtaking pending metrics
+	require.Equal(t, [][]string{{"a", "b"}}, mock.Batches)
+	require.Equal(t, []string{"skip", "a", "b", "skip"}, metricNames(acc.GetTelegrafMetrics()))
+
+	// Pending metrics are processed on stop
+	rp.Stop()
+	require.Equal(t, [][]string{{"a", "b"}, {"c"}}, mock.Batches)
+	require.Equal(t, []string{"skip", "a", "b", "skip", "c", "skip"}, metricNames(acc.GetTelegrafMetrics()))
+}
+
+func metricNames(metrics []telegraf.Metric) []string {
+	names := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		names = append(names, m.Name())
+	}
+	return names
+}
+
+func TestRunningProcessor_BatchTimeout(t *testing.T) {
+	mock := &MockBatchProcessor{}
+	rp := models.NewRunningProcessor(
+		processors.NewStreamingProcessorFromProcessor(mock),
+		&models.ProcessorConfig{
+			Name:         "batch",
+			BatchSize:    10,
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	)
+
+	var acc testutil.Accumulator
+	require.NoError(t, rp.Start(&acc))
+	defer rp.Stop()
+
+	m := metric.New("a", map[string]string{}, map[string]interface{}{"value": 1}, time.Unix(0, 0))
+	require.NoError(t, rp.Add(m, &acc))
+
+	// Incomplete batches are processed after the timeout
+	acc.Wait(1)
+	rp.Lock()
+	defer rp.Unlock()
+	require.Equal(t, [][]string{{"a"}}, mock.Batches)
+}
+
+func TestRunningProcessor_BatchDisabled(t *testing.T) {
+	mock := &MockBatchProcessor{MockProcessor: *TagProcessor("apply", "true")}
+	rp := models.NewRunningProcessor(
+		processors.NewStreamingProcessorFromProcessor(mock),
+		&models.ProcessorConfig{Name: "batch"},
+	)
+
+	// Batching is disabled by default
+	var acc testutil.Accumulator
+	require.NoError(t, rp.Start(&acc))
+	m := metric.New("a", map[string]string{}, map[string]interface{}{"value": 1}, time.Unix(0, 0))
+	require.NoError(t, rp.Add(m, &acc))
+	rp.Stop()
+
+	require.Empty(t, mock.Batches)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+	require.True(t, acc.HasTag("a", "apply"))
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+)
+
+// secretRotator is implemented by secrets able to refresh references to
+// rotatable secrets of secret-stores, i.e. config.Secret. The interface avoids
+// a dependency on the config package.
+type secretRotator interface {
+	Rotate() (bool, error)
+}
+
+// rotateSecrets rotates all secrets in the exported fields of the given plugin
+// and returns the number of secrets with rotatable references.
+func rotateSecrets(plugin interface{}) (int, error) {
+	w := &secretWalker{visited: make(map[uintptr]bool)}
+	w.walk(reflect.ValueOf(plugin))
+	return w.rotated, errors.Join(w.errs...)
+}
+
+type secretWalker struct {
+	visited map[uintptr]bool
+	rotated int
+	errs    []error
+}
+
+func (w *secretWalker) walk(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || w.visited[v.Pointer()] {
+			return
+		}
+		w.visited[v.Pointer()] = true
+		w.walk(v.Elem())
+	case reflect.Interface:
+		if !v.IsNil() {
+			w.walk(v.Elem())
+		}
+	case reflect.Struct:
+		if v.CanAddr() && v.Addr().CanInterface() {
+			if s, ok := v.Addr().Interface().(secretRotator); ok {
+				rotated, err := s.Rotate()
+				if rotated {
+					w.rotated++
+				}
+				if err != nil {
+					w.errs = append(w.errs, err)
+				}
+				return
+			}
+		}
+		// Only exported fields can contain settings
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				w.walk(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			w.walk(v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			w.walk(iter.Value())
+		}
+	}
+}
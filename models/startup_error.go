@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrPluginSkipped is returned when a plugin failed to start and should be
+// removed from the agent according to its startup error behavior.
+var ErrPluginSkipped = errors.New("plugin skipped")
+
+const (
+	// Initial and maximum delay between two attempts to start a plugin
+	startupRetryMinBackoff = 5 * time.Second
+	startupRetryMaxBackoff = 5 * time.Minute
+)
+
+// checkStartupErrorBehavior validates the given startup error behavior.
+func checkStartupErrorBehavior(behavior string) error {
+	switch behavior {
+	case "", "error", "retry", "ignore":
+		return nil
+	}
+	return fmt.Errorf("invalid 'startup_error_behavior' setting %q", behavior)
+}
+
+// startupRetry tracks the backoff for retrying to start a plugin. Only the
+// pending flag and the delay may be accessed concurrently.
+type startupRetry struct {
+	pending atomic.Bool
+	until   atomic.Int64
+	next    time.Time
+	backoff time.Duration
+}
+
+// failed schedules the next attempt with an exponentially increasing delay.
+func (r *startupRetry) failed() {
+	r.backoff *= 2
+	if r.backoff < startupRetryMinBackoff {
+		r.backoff = startupRetryMinBackoff
+	}
+	if r.backoff > startupRetryMaxBackoff {
+		r.backoff = startupRetryMaxBackoff
+	}
+	r.pending.Store(true)
+	r.next = time.Now().Add(r.backoff)
+	r.until.Store(r.next.UnixNano())
+}
+
+// succeeded marks the plugin as started.
+func (r *startupRetry) succeeded() {
+	r.pending.Store(false)
+	r.backoff = 0
+}
+
+// retrying returns true if the plugin failed to start and is waiting for the
+// next attempt.
+func (r *startupRetry) retrying() bool {
+	return r.pending.Load()
+}
+
+// delay returns the time until the next attempt to start the plugin or zero
+// if no attempt is pending or it is due.
+func (r *startupRetry) delay() time.Duration {
+	if !r.pending.Load() {
+		return 0
+	}
+	if delay := time.Until(time.Unix(0, r.until.Load())); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// due returns true if the next attempt to start the plugin should be made.
+func (r *startupRetry) due() bool {
+	return r.pending.Load() && !time.Now().Before(r.next)
+}
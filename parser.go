@@ -1,5 +1,7 @@
 package telegraf
 
+import "io"
+
 // Parser is an interface defining functions that a parser plugin must satisfy.
 type Parser interface {
 	// Parse takes a byte buffer separated by newlines
@@ -22,6 +24,16 @@ type Parser interface {
 	SetDefaultTags(tags map[string]string)
 }
 
+// StreamParser is an optional interface for parsers able to parse data in
+// chunks, e.g. the record batches of columnar formats, so large files don't
+// need to fit in memory.
+type StreamParser interface {
+	// ParseStream parses the data read from the given reader and calls the
+	// given function with the metrics of each chunk. Parsing stops at the
+	// first error returned by the function.
+	ParseStream(r io.Reader, fn func([]Metric) error) error
+}
+
 type ParserFunc func() (Parser, error)
 
 // ParserPlugin is an interface for plugins that are able to parse
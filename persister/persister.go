@@ -1,27 +1,68 @@
 package persister
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/selfstat"
 )
 
+// stateFileVersion is the version of the state-file schema written by Store.
+// Version zero denotes the legacy format without any versioning information.
+const stateFileVersion = 1
+
+// stateFile is the on-disk representation of all plugin states.
+type stateFile struct {
+	Version int                    `json:"version"`
+	States  map[string]pluginState `json:"states"`
+}
+
+// pluginState is the on-disk representation of a single plugin state.
+type pluginState struct {
+	Version int             `json:"version"`
+	State   json.RawMessage `json:"state"`
+}
+
 type Persister struct {
 	Filename string
+	Log      telegraf.Logger
 
 	register map[string]telegraf.StatefulPlugin
+	detached map[string]pluginState
+	mu       sync.Mutex
+
+	lastCheckpoint   selfstat.Stat
+	checkpointErrors selfstat.Stat
 }
 
 func (p *Persister) Init() error {
 	p.register = make(map[string]telegraf.StatefulPlugin)
+	p.detached = make(map[string]pluginState)
+
+	if p.Log == nil {
+		p.Log = models.NewLogger("agent", "persister", "")
+	}
+
+	tags := map[string]string{"statefile": p.Filename}
+	p.lastCheckpoint = selfstat.Register("persister", "last_checkpoint", tags)
+	p.checkpointErrors = selfstat.Register("persister", "checkpoint_errors", tags)
 
 	return nil
 }
 
 func (p *Persister) Register(id string, plugin telegraf.StatefulPlugin) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if _, found := p.register[id]; found {
 		return fmt.Errorf("plugin with ID %q already registered", id)
 	}
@@ -30,6 +71,28 @@ func (p *Persister) Register(id string, plugin telegraf.StatefulPlugin) error {
 	return nil
 }
 
+// Unregister removes the plugin with the given ID, e.g. if the plugin was
+// removed when reloading the configuration. The plugin's state is not
+// persisted anymore afterwards but kept in memory to be restored if the
+// plugin is added again.
+func (p *Persister) Unregister(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	plugin, found := p.register[id]
+	if !found {
+		return
+	}
+	delete(p.register, id)
+
+	state, err := json.Marshal(plugin.GetState())
+	if err != nil {
+		p.Log.Warnf("Discarding state of %q: %v", id, err)
+		return
+	}
+	p.detached[id] = pluginState{Version: stateVersion(plugin), State: state}
+}
+
 func (p *Persister) Load() error {
 	// Read the states from disk
 	in, err := os.ReadFile(p.Filename)
@@ -37,40 +100,98 @@ func (p *Persister) Load() error {
 		return fmt.Errorf("reading states file failed: %w", err)
 	}
 
-	// Unmarshal the id to serialized states map
-	var states map[string][]byte
-	if err := json.Unmarshal(in, &states); err != nil {
-		return fmt.Errorf("unmarshalling states failed: %w", err)
+	states, err := unmarshalStates(in)
+	if err != nil {
+		return err
 	}
 
-	// Get the initialized state as blueprint for unmarshalling
-	for id, serialized := range states {
+	for id, entry := range states {
 		// Check if we have a plugin with that ID
 		plugin, found := p.register[id]
 		if !found {
 			continue
 		}
+		if err := p.setState(id, plugin, entry); err != nil {
+			return err
+		}
+	}
 
-		// Create a new empty state of the "state"-type. As we need a pointer
-		// of the state, we cannot dereference it here due to the unknown
-		// nature of the state-type.
-		nstate := reflect.New(reflect.TypeOf(plugin.GetState())).Interface()
-		if err := json.Unmarshal(serialized, &nstate); err != nil {
-			return fmt.Errorf("unmarshalling state for %q failed: %w", id, err)
+	return nil
+}
+
+// Restore sets the stored state of a plugin started after loading the
+// states, e.g. when reloading the configuration. The state kept when
+// unregistering the plugin takes precedence over the one in the states file.
+// A missing states file or state is not an error.
+func (p *Persister) Restore(id string, plugin telegraf.StatefulPlugin) error {
+	p.mu.Lock()
+	entry, found := p.detached[id]
+	delete(p.detached, id)
+	p.mu.Unlock()
+
+	if !found {
+		in, err := os.ReadFile(p.Filename)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading states file failed: %w", err)
 		}
-		state := reflect.ValueOf(nstate).Elem().Interface()
 
-		// Set the state in the plugin
-		if err := plugin.SetState(state); err != nil {
-			return fmt.Errorf("setting state of %q failed: %w", id, err)
+		states, err := unmarshalStates(in)
+		if err != nil {
+			return err
+		}
+		if entry, found = states[id]; !found {
+			return nil
 		}
 	}
 
+	return p.setState(id, plugin, entry)
+}
+
+func (p *Persister) setState(id string, plugin telegraf.StatefulPlugin, entry pluginState) error {
+	// Make sure the stored state is compatible with the plugin
+	serialized, err := migrateState(plugin, entry)
+	if err != nil {
+		p.Log.Warnf("Discarding state of %q: %v", id, err)
+		return nil
+	}
+
+	// Create a new empty state of the "state"-type using the initialized
+	// state as blueprint. As we need a pointer of the state, we cannot
+	// dereference it here due to the unknown nature of the state-type.
+	nstate := reflect.New(reflect.TypeOf(plugin.GetState())).Interface()
+	if err := json.Unmarshal(serialized, &nstate); err != nil {
+		return fmt.Errorf("unmarshalling state for %q failed: %w", id, err)
+	}
+	state := reflect.ValueOf(nstate).Elem().Interface()
+
+	// Set the state in the plugin
+	if err := plugin.SetState(state); err != nil {
+		return fmt.Errorf("setting state of %q failed: %w", id, err)
+	}
 	return nil
 }
 
 func (p *Persister) Store() error {
-	states := make(map[string][]byte)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.store(); err != nil {
+		p.checkpointErrors.Incr(1)
+		return err
+	}
+	p.lastCheckpoint.Set(time.Now().Unix())
+
+	return nil
+}
+
+func (p *Persister) store() error {
+	states := stateFile{
+		Version: stateFileVersion,
+		States:  make(map[string]pluginState, len(p.register)),
+	}
 
 	// Collect the states and serialize the individual data chunks
 	// to later serialize all items in the id / serialized-states map
@@ -79,7 +200,10 @@ func (p *Persister) Store() error {
 		if err != nil {
 			return fmt.Errorf("marshalling state for id %q failed: %w", id, err)
 		}
-		states[id] = state
+		states.States[id] = pluginState{
+			Version: stateVersion(plugin),
+			State:   state,
+		}
 	}
 
 	// Serialize the states
@@ -89,15 +213,131 @@ func (p *Persister) Store() error {
 	}
 
 	// Write the states to disk
-	f, err := os.Create(p.Filename)
+	return writeFileAtomic(p.Filename, serialized)
+}
+
+// Checkpoint periodically stores the states with the given interval until
+// the context is cancelled.
+func (p *Persister) Checkpoint(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Store(); err != nil {
+				p.Log.Errorf("Checkpointing states failed: %v", err)
+			}
+		}
+	}
+}
+
+// unmarshalStates decodes the states file content supporting the versioned
+// schema as well as the legacy id to serialized-state map.
+func unmarshalStates(in []byte) (map[string]pluginState, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(in, &header); err != nil {
+		return nil, fmt.Errorf("unmarshalling states failed: %w", err)
+	}
+
+	switch header.Version {
+	case 0:
+		// Legacy format without version information
+		var legacy map[string][]byte
+		if err := json.Unmarshal(in, &legacy); err != nil {
+			return nil, fmt.Errorf("unmarshalling states failed: %w", err)
+		}
+		states := make(map[string]pluginState, len(legacy))
+		for id, serialized := range legacy {
+			states[id] = pluginState{State: serialized}
+		}
+		return states, nil
+	case stateFileVersion:
+		var states stateFile
+		if err := json.Unmarshal(in, &states); err != nil {
+			return nil, fmt.Errorf("unmarshalling states failed: %w", err)
+		}
+		return states.States, nil
+	}
+
+	return nil, fmt.Errorf("unsupported states file version %d", header.Version)
+}
+
+// stateVersion returns the version of the state of the given plugin.
+func stateVersion(plugin telegraf.StatefulPlugin) int {
+	if p, ok := plugin.(telegraf.StatefulPluginWithVersion); ok {
+		return p.StateVersion()
+	}
+	return 0
+}
+
+// migrateState returns the serialized state in the current version of the
+// plugin's state, migrating older states if the plugin supports it.
+func migrateState(plugin telegraf.StatefulPlugin, entry pluginState) ([]byte, error) {
+	current := stateVersion(plugin)
+	if entry.Version == current {
+		return entry.State, nil
+	}
+
+	migrator, ok := plugin.(telegraf.StateMigrator)
+	if !ok || entry.Version > current {
+		return nil, fmt.Errorf("incompatible state version %d, expected %d", entry.Version, current)
+	}
+
+	state, err := migrator.MigrateState(entry.Version, entry.State)
+	if err != nil {
+		return nil, fmt.Errorf("migrating state from version %d failed: %w", entry.Version, err)
+	}
+	return state, nil
+}
+
+// writeFileAtomic writes the data to a temporary file in the directory of the
+// given file and renames it to the final name afterwards. This way the file is
+// either completely written or not modified at all.
+func writeFileAtomic(filename string, data []byte) error {
+	dir := filepath.Dir(filename)
+
+	f, err := os.CreateTemp(dir, filepath.Base(filename)+".tmp-*")
 	if err != nil {
-		return fmt.Errorf("creating states file %q failed: %w", p.Filename, err)
+		return fmt.Errorf("creating temporary states file failed: %w", err)
 	}
-	defer f.Close()
+	tmpfile := f.Name()
+	defer os.Remove(tmpfile)
 
-	if _, err := f.Write(serialized); err != nil {
+	// Keep the permissions of an existing file
+	if info, err := os.Stat(filename); err == nil {
+		if err := f.Chmod(info.Mode().Perm()); err != nil {
+			f.Close()
+			return fmt.Errorf("setting permissions of states file failed: %w", err)
+		}
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
 		return fmt.Errorf("writing states failed: %w", err)
 	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("syncing states failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing states file failed: %w", err)
+	}
+
+	if err := os.Rename(tmpfile, filename); err != nil {
+		return fmt.Errorf("replacing states file %q failed: %w", filename, err)
+	}
+
+	// Persist the rename operation, this is not supported on all platforms
+	// so ignore any error.
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
 
 	return nil
 }
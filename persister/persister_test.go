@@ -0,0 +1,216 @@
+package persister
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/testutil"
+)
+
+type mockState struct {
+	Offset uint64 `json:"offset"`
+}
+
+type mockPlugin struct {
+	state mockState
+}
+
+func (m *mockPlugin) GetState() interface{} {
+	return m.state
+}
+
+func (m *mockPlugin) SetState(state interface{}) error {
+	s, ok := state.(mockState)
+	if !ok {
+		return errors.New("invalid state type")
+	}
+	m.state = s
+	return nil
+}
+
+type mockVersionedPlugin struct {
+	mockPlugin
+	version int
+}
+
+func (m *mockVersionedPlugin) StateVersion() int {
+	return m.version
+}
+
+type mockMigratingPlugin struct {
+	mockVersionedPlugin
+}
+
+func (m *mockMigratingPlugin) MigrateState(version int, state []byte) ([]byte, error) {
+	if version != 0 {
+		return nil, errors.New("unknown version")
+	}
+
+	// Version 0 stored the offset as a plain number
+	var offset uint64
+	if err := json.Unmarshal(state, &offset); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mockState{Offset: offset})
+}
+
+func newPersister(t *testing.T, filename string) *Persister {
+	p := &Persister{
+		Filename: filename,
+		Log:      testutil.Logger{},
+	}
+	require.NoError(t, p.Init())
+	return p
+}
+
+func TestStoreLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "states.json")
+
+	store := newPersister(t, filename)
+	require.NoError(t, store.Register("a", &mockPlugin{state: mockState{Offset: 42}}))
+	require.NoError(t, store.Register("b", &mockVersionedPlugin{mockPlugin{mockState{Offset: 23}}, 2}))
+	require.NoError(t, store.Store())
+	require.NotZero(t, store.lastCheckpoint.Get())
+
+	// Make sure no temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	pa := &mockPlugin{}
+	pb := &mockVersionedPlugin{version: 2}
+	load := newPersister(t, filename)
+	require.NoError(t, load.Register("a", pa))
+	require.NoError(t, load.Register("b", pb))
+	require.NoError(t, load.Load())
+	require.Equal(t, uint64(42), pa.state.Offset)
+	require.Equal(t, uint64(23), pb.state.Offset)
+}
+
+func TestStoreReplacesExisting(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "states.json")
+	require.NoError(t, os.WriteFile(filename, []byte("garbage that is much longer than the new states"), 0600))
+
+	store := newPersister(t, filename)
+	require.NoError(t, store.Register("a", &mockPlugin{state: mockState{Offset: 42}}))
+	require.NoError(t, store.Store())
+
+	buf, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version":1,"states":{"a":{"version":0,"state":{"offset":42}}}}`, string(buf))
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestRestore(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "states.json")
+
+	store := newPersister(t, filename)
+	require.NoError(t, store.Register("a", &mockPlugin{state: mockState{Offset: 42}}))
+	require.NoError(t, store.Register("b", &mockPlugin{state: mockState{Offset: 23}}))
+	require.NoError(t, store.Store())
+
+	// Plugins not known when loading are restored from the states file
+	p := newPersister(t, filename)
+	require.NoError(t, p.Load())
+	pa := &mockPlugin{}
+	require.NoError(t, p.Restore("a", pa))
+	require.Equal(t, uint64(42), pa.state.Offset)
+	require.NoError(t, p.Register("a", pa))
+
+	// Removed plugins keep their current state when added again
+	pa.state.Offset = 43
+	p.Unregister("a")
+	readded := &mockPlugin{}
+	require.NoError(t, p.Restore("a", readded))
+	require.Equal(t, uint64(43), readded.state.Offset)
+
+	// Unknown plugins and missing states files are no error
+	unknown := &mockPlugin{}
+	require.NoError(t, p.Restore("c", unknown))
+	require.Zero(t, unknown.state.Offset)
+	missing := newPersister(t, filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, missing.Restore("a", unknown))
+	require.Zero(t, unknown.state.Offset)
+}
+
+func TestLoadLegacyFormat(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "states.json")
+
+	state, err := json.Marshal(mockState{Offset: 42})
+	require.NoError(t, err)
+	legacy, err := json.Marshal(map[string][]byte{"a": state})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, legacy, 0600))
+
+	plugin := &mockPlugin{}
+	load := newPersister(t, filename)
+	require.NoError(t, load.Register("a", plugin))
+	require.NoError(t, load.Load())
+	require.Equal(t, uint64(42), plugin.state.Offset)
+}
+
+func TestLoadUnsupportedFileVersion(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "states.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"version":99,"states":{}}`), 0600))
+
+	load := newPersister(t, filename)
+	require.ErrorContains(t, load.Load(), "unsupported states file version 99")
+}
+
+func TestLoadIncompatibleStateVersion(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "states.json")
+	content := `{"version":1,"states":{"a":{"version":1,"state":{"offset":42}}}}`
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+
+	plugin := &mockVersionedPlugin{mockPlugin{mockState{Offset: 1}}, 2}
+	load := newPersister(t, filename)
+	require.NoError(t, load.Register("a", plugin))
+	require.NoError(t, load.Load())
+
+	// The state must be discarded
+	require.Equal(t, uint64(1), plugin.state.Offset)
+}
+
+func TestLoadMigrateState(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "states.json")
+	content := `{"version":1,"states":{"a":{"version":0,"state":42}}}`
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+
+	plugin := &mockMigratingPlugin{mockVersionedPlugin{version: 1}}
+	load := newPersister(t, filename)
+	require.NoError(t, load.Register("a", plugin))
+	require.NoError(t, load.Load())
+	require.Equal(t, uint64(42), plugin.state.Offset)
+}
+
+func TestCheckpoint(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "states.json")
+
+	plugin := &mockPlugin{state: mockState{Offset: 42}}
+	p := newPersister(t, filename)
+	require.NoError(t, p.Register("a", plugin))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Checkpoint(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(filename)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
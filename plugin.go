@@ -73,7 +73,9 @@ type StatefulPlugin interface {
 	// serialized to JSON. The best choice is a structure defined in
 	// your plugin.
 	// Note: This function has to be callable directly after the
-	// plugin's Init() function if there is any!
+	// plugin's Init() function if there is any! If periodic checkpointing
+	// is enabled, the function is also called while the plugin is running
+	// so it must be safe for concurrent use.
 	GetState() interface{}
 
 	// SetState is called by the Persister once after loading and
@@ -81,6 +83,42 @@ type StatefulPlugin interface {
 	SetState(state interface{}) error
 }
 
+// StatefulPluginWithVersion can be implemented by stateful plugins to version
+// the format of their state. The version is stored alongside the state and
+// states of a different version are discarded on load unless the plugin
+// implements the StateMigrator interface. Plugins not implementing this
+// interface have a state version of zero.
+type StatefulPluginWithVersion interface {
+	StatefulPlugin
+
+	// StateVersion returns the version of the state returned by GetState.
+	StateVersion() int
+}
+
+// StateMigrator contains the functions that stateful plugins must implement
+// to convert states stored by older versions of the plugin.
+type StateMigrator interface {
+	// MigrateState converts the JSON-serialized state of the given, older
+	// version into the JSON-serialized state of the current version.
+	MigrateState(version int, state []byte) ([]byte, error)
+}
+
+// AuthenticationError can be returned by inputs and outputs to signal that
+// authenticating against a service failed, e.g. with HTTP status 401 or 403.
+// Telegraf then resolves the plugin's secrets again to pick up rotated
+// credentials and reconnects outputs or restarts service inputs.
+type AuthenticationError struct {
+	Err error
+}
+
+func (e *AuthenticationError) Error() string {
+	return "authentication failed: " + e.Err.Error()
+}
+
+func (e *AuthenticationError) Unwrap() error {
+	return e.Err
+}
+
 // Logger defines an plugin-related interface for logging.
 type Logger interface {
 	// Errorf logs an error message, patterned after log.Printf.
@@ -3,10 +3,13 @@ package derivative
 
 import (
 	_ "embed"
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/aggregators"
 )
 
@@ -19,6 +22,7 @@ type Derivative struct {
 	MaxRollOver uint            `toml:"max_roll_over"`
 	Log         telegraf.Logger `toml:"-"`
 	cache       map[uint64]*aggregate
+	sync.Mutex
 }
 
 type aggregate struct {
@@ -34,6 +38,21 @@ type event struct {
 	time   time.Time
 }
 
+// aggregateState is the serializable form of an aggregate. The first event is
+// omitted if it is the same as the last one.
+type aggregateState struct {
+	Name     string            `json:"name"`
+	Tags     map[string]string `json:"tags"`
+	First    *eventState       `json:"first,omitempty"`
+	Last     eventState        `json:"last"`
+	RollOver uint              `json:"roll_over"`
+}
+
+type eventState struct {
+	Fields map[string]float64 `json:"fields"`
+	Time   time.Time          `json:"time"`
+}
+
 const defaultSuffix = "_rate"
 
 func NewDerivative() *Derivative {
@@ -48,6 +67,9 @@ func (*Derivative) SampleConfig() string {
 }
 
 func (d *Derivative) Add(in telegraf.Metric) {
+	d.Lock()
+	defer d.Unlock()
+
 	id := in.HashID()
 	current, ok := d.cache[id]
 	if !ok {
@@ -116,6 +138,9 @@ func convert(in interface{}) (float64, bool) {
 }
 
 func (d *Derivative) Push(acc telegraf.Accumulator) {
+	d.Lock()
+	defer d.Unlock()
+
 	for _, aggregate := range d.cache {
 		if aggregate.first == aggregate.last {
 			d.Log.Debugf("Same first and last event for %q, skipping.", aggregate.name)
@@ -157,6 +182,9 @@ func (d *Derivative) Push(acc telegraf.Accumulator) {
 }
 
 func (d *Derivative) Reset() {
+	d.Lock()
+	defer d.Unlock()
+
 	for id, aggregate := range d.cache {
 		if aggregate.rollOver < d.MaxRollOver {
 			aggregate.first = aggregate.last
@@ -170,6 +198,56 @@ func (d *Derivative) Reset() {
 	}
 }
 
+// GetState returns the cached first and last events of all series
+func (d *Derivative) GetState() interface{} {
+	d.Lock()
+	defer d.Unlock()
+
+	state := make([]aggregateState, 0, len(d.cache))
+	for _, aggregate := range d.cache {
+		entry := aggregateState{
+			Name:     aggregate.name,
+			Tags:     aggregate.tags,
+			Last:     eventState{Fields: aggregate.last.fields, Time: aggregate.last.time},
+			RollOver: aggregate.rollOver,
+		}
+		if aggregate.first != aggregate.last {
+			entry.First = &eventState{Fields: aggregate.first.fields, Time: aggregate.first.time}
+		}
+		state = append(state, entry)
+	}
+	return state
+}
+
+// SetState restores the cached first and last events of all series
+func (d *Derivative) SetState(state interface{}) error {
+	entries, ok := state.([]aggregateState)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", state)
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	for _, entry := range entries {
+		last := &event{fields: entry.Last.Fields, time: entry.Last.Time}
+		first := last
+		if entry.First != nil {
+			first = &event{fields: entry.First.Fields, time: entry.First.Time}
+		}
+
+		id := metric.New(entry.Name, entry.Tags, nil, time.Time{}).HashID()
+		d.cache[id] = &aggregate{
+			name:     entry.Name,
+			tags:     entry.Tags,
+			first:    first,
+			last:     last,
+			rollOver: entry.RollOver,
+		}
+	}
+	return nil
+}
+
 func (d *Derivative) Init() error {
 	d.Suffix = strings.TrimSpace(d.Suffix)
 	d.Variable = strings.TrimSpace(d.Variable)
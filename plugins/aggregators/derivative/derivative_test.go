@@ -1,6 +1,7 @@
 package derivative
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -414,3 +415,49 @@ func TestCalculatesCorrectDerivativeOnTwoConsecutivePeriods(t *testing.T) {
 		"value_rate": 2.0,
 	})
 }
+
+func TestState(t *testing.T) {
+	acc := testutil.Accumulator{}
+	derivative := &Derivative{
+		Variable:    "parameter",
+		Suffix:      "_wrt_parameter",
+		MaxRollOver: 10,
+		cache:       make(map[uint64]*aggregate),
+	}
+	derivative.Log = testutil.Logger{}
+	require.NoError(t, derivative.Init())
+
+	derivative.Add(start)
+	derivative.Push(&acc)
+	derivative.Reset()
+
+	// Restore the state in a new instance after a round-trip through JSON
+	serialized, err := json.Marshal(derivative.GetState())
+	require.NoError(t, err)
+	var state []aggregateState
+	require.NoError(t, json.Unmarshal(serialized, &state))
+
+	restored := &Derivative{
+		Variable:    "parameter",
+		Suffix:      "_wrt_parameter",
+		MaxRollOver: 10,
+		cache:       make(map[uint64]*aggregate),
+	}
+	restored.Log = testutil.Logger{}
+	require.NoError(t, restored.Init())
+	require.NoError(t, restored.SetState(state))
+	require.Error(t, restored.SetState("garbage"))
+
+	restored.Add(finish)
+	restored.Push(&acc)
+
+	expectedFields := map[string]interface{}{
+		"increasing_wrt_parameter": 100.0,
+		"decreasing_wrt_parameter": -10.0,
+		"unchanged_wrt_parameter":  0.0,
+	}
+	expectedTags := map[string]string{
+		"state": "full",
+	}
+	acc.AssertContainsTaggedFields(t, "TestMetric", expectedFields, expectedTags)
+}
@@ -3,10 +3,13 @@ package final
 
 import (
 	_ "embed"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/aggregators"
 )
 
@@ -15,9 +18,11 @@ var sampleConfig string
 
 type Final struct {
 	SeriesTimeout config.Duration `toml:"series_timeout"`
+	Log           telegraf.Logger `toml:"-"`
 
 	// The last metric for all series which are active
 	metricCache map[uint64]telegraf.Metric
+	sync.Mutex
 }
 
 func NewFinal() *Final {
@@ -32,11 +37,17 @@ func (*Final) SampleConfig() string {
 }
 
 func (m *Final) Add(in telegraf.Metric) {
+	m.Lock()
+	defer m.Unlock()
+
 	id := in.HashID()
 	m.metricCache[id] = in
 }
 
 func (m *Final) Push(acc telegraf.Accumulator) {
+	m.Lock()
+	defer m.Unlock()
+
 	// Preserve timestamp of original metric
 	acc.SetPrecision(time.Nanosecond)
 
@@ -55,6 +66,43 @@ func (m *Final) Push(acc telegraf.Accumulator) {
 func (m *Final) Reset() {
 }
 
+// GetState returns the last metric of all active series in serialized form
+func (m *Final) GetState() interface{} {
+	m.Lock()
+	defer m.Unlock()
+
+	state := make([][]byte, 0, len(m.metricCache))
+	for _, series := range m.metricCache {
+		buf, err := metric.ToBytes(series)
+		if err != nil {
+			m.Log.Errorf("Serializing series %q failed: %v", series.Name(), err)
+			continue
+		}
+		state = append(state, buf)
+	}
+	return state
+}
+
+// SetState restores the last metric of all active series
+func (m *Final) SetState(state interface{}) error {
+	serialized, ok := state.([][]byte)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", state)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	for _, buf := range serialized {
+		in, err := metric.FromBytes(buf)
+		if err != nil {
+			return fmt.Errorf("restoring series failed: %w", err)
+		}
+		m.metricCache[in.HashID()] = in
+	}
+	return nil
+}
+
 func init() {
 	aggregators.Add("final", func() telegraf.Aggregator {
 		return NewFinal()
@@ -1,9 +1,12 @@
 package final
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/metric"
@@ -142,3 +145,38 @@ func TestLongDifference(t *testing.T) {
 	}
 	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.SortMetrics())
 }
+
+func TestState(t *testing.T) {
+	acc := testutil.Accumulator{}
+	final := NewFinal()
+
+	tags := map[string]string{"foo": "bar"}
+	final.Add(metric.New("m1",
+		tags,
+		map[string]interface{}{"a": int64(1), "b": "text"},
+		time.Unix(1530939936, 0)))
+
+	// Restore the state in a new instance after a round-trip through JSON
+	serialized, err := json.Marshal(final.GetState())
+	require.NoError(t, err)
+	var state [][]byte
+	require.NoError(t, json.Unmarshal(serialized, &state))
+
+	restored := NewFinal()
+	require.NoError(t, restored.SetState(state))
+	require.Error(t, restored.SetState("garbage"))
+	restored.Push(&acc)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"m1",
+			tags,
+			map[string]interface{}{
+				"a_final": int64(1),
+				"b_final": "text",
+			},
+			time.Unix(1530939936, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
@@ -3,6 +3,7 @@ package histogram
 
 import (
 	_ "embed"
+	"math"
 	"sort"
 	"strconv"
 	"time"
@@ -34,6 +35,7 @@ type HistogramAggregator struct {
 	Cumulative         bool                    `toml:"cumulative"`
 	ExpirationInterval telegrafConfig.Duration `toml:"expiration_interval"`
 	PushOnlyOnUpdate   bool                    `toml:"push_only_on_update"`
+	NativeValues       bool                    `toml:"native_values"`
 
 	buckets bucketsByMetrics
 	cache   map[uint64]metricHistogramCollection
@@ -58,6 +60,7 @@ type buckets []float64
 // metricHistogramCollection aggregates the histogram data
 type metricHistogramCollection struct {
 	histogramCollection map[string]counts
+	sums                map[string]float64
 	name                string
 	tags                map[string]string
 	expireTime          time.Time
@@ -114,6 +117,7 @@ func (h *HistogramAggregator) Add(in telegraf.Metric) {
 			name:                in.Name(),
 			tags:                in.Tags(),
 			histogramCollection: make(map[string]counts),
+			sums:                make(map[string]float64),
 		}
 	}
 
@@ -126,6 +130,7 @@ func (h *HistogramAggregator) Add(in telegraf.Metric) {
 			if value, ok := convert(value); ok {
 				index := sort.SearchFloat64s(buckets, value)
 				agr.histogramCollection[field][index]++
+				agr.sums[field] += value
 			}
 			if h.ExpirationInterval != 0 {
 				agr.expireTime = addTime.Add(time.Duration(h.ExpirationInterval))
@@ -152,6 +157,10 @@ func (h *HistogramAggregator) Push(acc telegraf.Accumulator) {
 		}
 		aggregate.updated = false
 		h.cache[id] = aggregate
+		if h.NativeValues {
+			h.pushNative(acc, aggregate)
+			continue
+		}
 		for field, counts := range aggregate.histogramCollection {
 			h.groupFieldsByBuckets(&metricsWithGroupedFields, aggregate.name, field, copyTags(aggregate.tags), counts)
 		}
@@ -162,6 +171,29 @@ func (h *HistogramAggregator) Push(acc telegraf.Accumulator) {
 	}
 }
 
+// pushNative adds a single histogram field for each aggregated field holding
+// the count, sum and cumulative counts of all buckets
+func (h *HistogramAggregator) pushNative(acc telegraf.Accumulator, aggregate metricHistogramCollection) {
+	fields := make(map[string]interface{}, len(aggregate.histogramCollection))
+	for field, counts := range aggregate.histogramCollection {
+		buckets := h.getBuckets(aggregate.name, field)
+		v := &telegraf.HistogramValue{
+			Sum:     aggregate.sums[field],
+			Buckets: make([]telegraf.Bucket, 0, len(counts)),
+		}
+		for index, count := range counts {
+			bound := math.Inf(1)
+			if index < len(buckets) {
+				bound = buckets[index]
+			}
+			v.Count += uint64(count)
+			v.Buckets = append(v.Buckets, telegraf.Bucket{UpperBound: bound, Count: v.Count})
+		}
+		fields[field] = v
+	}
+	acc.AddHistogram(aggregate.name, fields, copyTags(aggregate.tags))
+}
+
 // groupFieldsByBuckets groups fields by metric buckets which are represented as tags
 func (h *HistogramAggregator) groupFieldsByBuckets(
 	metricsWithGroupedFields *[]groupedByCountFields,
@@ -2,6 +2,7 @@ package histogram
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -105,6 +106,46 @@ func TestHistogram(t *testing.T) {
 	assertContainsTaggedField(t, acc, "first_metric_name", fields{"a_bucket": int64(2)}, tags{bucketRightTag: bucketPosInf})
 }
 
+// TestHistogramNativeValues tests emitting a single histogram field
+func TestHistogramNativeValues(t *testing.T) {
+	var cfg []config
+	cfg = append(cfg, config{Metric: "first_metric_name", Fields: []string{"a"}, Buckets: []float64{0.0, 10.0, 20.0}})
+	histogram := NewHistogramAggregator()
+	histogram.Configs = cfg
+	histogram.NativeValues = true
+
+	acc := &testutil.Accumulator{}
+
+	histogram.Add(firstMetric1)
+	histogram.Add(firstMetric2)
+	histogram.Push(acc)
+
+	// Sum up the values the same way to get the same rounding
+	sum := firstMetric1.Fields()["a"].(float64)
+	sum += firstMetric2.Fields()["a"].(float64)
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"first_metric_name",
+			map[string]string{},
+			map[string]interface{}{
+				"a": &telegraf.HistogramValue{
+					Count: 2,
+					Sum:   sum,
+					Buckets: []telegraf.Bucket{
+						{UpperBound: 0, Count: 0},
+						{UpperBound: 10, Count: 0},
+						{UpperBound: 20, Count: 2},
+						{UpperBound: math.Inf(1), Count: 2},
+					},
+				},
+			},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
 // TestHistogram tests metrics for one period, for one field and push only on histogram update
 func TestHistogramPushOnUpdate(t *testing.T) {
 	var cfg []config
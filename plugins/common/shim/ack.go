@@ -0,0 +1,89 @@
+package shim
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/process"
+	"github.com/influxdata/telegraf/plugins/serializers/influx"
+)
+
+var errAckStreaming = errors.New("acknowledgements are not supported for asynchronous processors")
+
+// ackReader strips the ID lines of the acknowledgement protocol from the
+// input stream and queues the IDs in the order of the metrics they belong to.
+type ackReader struct {
+	r   *bufio.Reader
+	buf []byte
+	ids []uint64
+}
+
+func newAckReader(r io.Reader) *ackReader {
+	return &ackReader{r: bufio.NewReader(r)}
+}
+
+func (a *ackReader) Read(p []byte) (int, error) {
+	for len(a.buf) == 0 {
+		line, err := a.r.ReadBytes('\n')
+		if msg, ok := process.ParseAck(line); ok && msg.Type == process.AckID {
+			a.ids = append(a.ids, msg.ID)
+			line = nil
+		}
+		a.buf = line
+		if err != nil {
+			if len(a.buf) > 0 {
+				break
+			}
+			return 0, err
+		}
+	}
+
+	n := copy(p, a.buf)
+	a.buf = a.buf[n:]
+	return n, nil
+}
+
+// next returns the ID of the next metric if any.
+func (a *ackReader) next() (uint64, bool) {
+	if len(a.ids) == 0 {
+		return 0, false
+	}
+	id := a.ids[0]
+	a.ids = a.ids[1:]
+	return id, true
+}
+
+// ackCollector collects the metrics a processor emits while processing a
+// metric, so they can be written along with the acknowledgement.
+type ackCollector struct {
+	telegraf.Accumulator
+	metrics []telegraf.Metric
+}
+
+func (c *ackCollector) AddMetric(m telegraf.Metric) {
+	c.metrics = append(c.metrics, m)
+}
+
+// writeAck writes the metrics derived from the metric with the given ID
+// followed by the acknowledgement or, in case of an error, the rejection.
+func (s *Shim) writeAck(serializer *influx.Serializer, id uint64, metrics []telegraf.Metric, ackErr error) error {
+	buf := process.FormatAckID(id)
+	for _, m := range metrics {
+		b, err := serializer.Serialize(m)
+		if err != nil {
+			return fmt.Errorf("failed to serialize metric: %w", err)
+		}
+		buf = append(buf, b...)
+	}
+	buf = append(buf, process.FormatAck(id, ackErr)...)
+
+	s.stdoutMu.Lock()
+	defer s.stdoutMu.Unlock()
+	if _, err := s.stdout.Write(buf); err != nil {
+		return fmt.Errorf("failed to write acknowledgement: %w", err)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -44,9 +45,15 @@ type Shim struct {
 	stdout io.Writer
 	stderr io.Writer
 
+	// serializes writes to stdout
+	stdoutMu sync.Mutex
+
 	// outgoing metric channel
 	metricCh chan telegraf.Metric
 
+	// processor only, true if the processor emits all metrics within Add()
+	processorSync bool
+
 	// input only
 	gatherPromptCh chan empty
 }
@@ -116,7 +123,9 @@ func (s *Shim) writeProcessedMetrics() error {
 				return fmt.Errorf("failed to serialize metric: %w", err)
 			}
 			// Write this to stdout
+			s.stdoutMu.Lock()
 			_, err = fmt.Fprint(s.stdout, string(b))
+			s.stdoutMu.Unlock()
 			if err != nil {
 				return fmt.Errorf("failed to write metric: %w", err)
 			}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal/process"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
 )
 
@@ -37,16 +38,30 @@ func (s *Shim) RunOutput() error {
 
 	var m telegraf.Metric
 
+	// Metrics preceded by an ID are acknowledged after writing
+	var id uint64
+	var hasID bool
+
 	scanner := bufio.NewScanner(s.stdin)
 	for scanner.Scan() {
+		if msg, ok := process.ParseAck(scanner.Bytes()); ok && msg.Type == process.AckID {
+			id, hasID = msg.ID, true
+			continue
+		}
+
 		m, err = parser.ParseLine(scanner.Text())
 		if err != nil {
 			fmt.Fprintf(s.stderr, "Failed to parse metric: %s\n", err)
-			continue
-		}
-		if err = s.Output.Write([]telegraf.Metric{m}); err != nil {
+		} else if err = s.Output.Write([]telegraf.Metric{m}); err != nil {
 			fmt.Fprintf(s.stderr, "Failed to write metric: %s\n", err)
 		}
+
+		if hasID {
+			if _, err := s.stdout.Write(process.FormatAck(id, err)); err != nil {
+				fmt.Fprintf(s.stderr, "Failed to write acknowledgement: %s\n", err)
+			}
+			hasID = false
+		}
 	}
 
 	return nil
@@ -1,7 +1,9 @@
 package shim
 
 import (
+	"bufio"
 	"io"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -82,3 +84,41 @@ func (o *testOutput) SampleConfig() string {
 func (o *testOutput) Description() string {
 	return ""
 }
+
+func TestOutputShimAck(t *testing.T) {
+	o := &testOutput{}
+
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+
+	s := New()
+	s.stdin = stdinReader
+	s.stdout = stdoutWriter
+	require.NoError(t, s.AddOutput(o))
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		err := s.RunOutput()
+		require.NoError(t, err)
+		wg.Done()
+	}()
+
+	// Metrics preceded by an ID are acknowledged, invalid ones rejected
+	go func() {
+		_, err := stdinWriter.Write([]byte("#telegraf:id=1\nthing v=1i 0\n#telegraf:id=2\nthing v=\n"))
+		require.NoError(t, err)
+		require.NoError(t, stdinWriter.Close())
+	}()
+
+	r := bufio.NewReader(stdoutReader)
+	out, err := r.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "#telegraf:ack=1\n", out)
+	out, err = r.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "#telegraf:nack=2 "))
+
+	wg.Wait()
+	require.Len(t, o.MetricsWritten, 1)
+}
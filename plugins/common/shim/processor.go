@@ -10,13 +10,26 @@ import (
 	"github.com/influxdata/telegraf/agent"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
 	"github.com/influxdata/telegraf/plugins/processors"
+	influxSerializer "github.com/influxdata/telegraf/plugins/serializers/influx"
 )
 
 // AddProcessor adds the processor to the shim. Later calls to Run() will run this.
 func (s *Shim) AddProcessor(processor telegraf.Processor) error {
 	setLoggerOnPlugin(processor, s.Log())
 	p := processors.NewStreamingProcessorFromProcessor(processor)
-	return s.AddStreamingProcessor(p)
+	if err := s.AddStreamingProcessor(p); err != nil {
+		return err
+	}
+	s.processorSync = true
+	return nil
+}
+
+// SynchronousProcessor is implemented by streaming processors emitting all
+// metrics derived from a metric within Add(). Only those processors support
+// acknowledgements.
+type SynchronousProcessor interface {
+	telegraf.StreamingProcessor
+	Synchronous() bool
 }
 
 // AddStreamingProcessor adds the processor to the shim. Later calls to Run() will run this.
@@ -30,6 +43,11 @@ func (s *Shim) AddStreamingProcessor(processor telegraf.StreamingProcessor) erro
 	}
 
 	s.Processor = processor
+	if p, ok := processor.(SynchronousProcessor); ok {
+		s.processorSync = p.Synchronous()
+	} else {
+		s.processorSync = false
+	}
 	return nil
 }
 
@@ -52,7 +70,15 @@ func (s *Shim) RunProcessor() error {
 		wg.Done()
 	}()
 
-	parser := influx.NewStreamParser(s.stdin)
+	// Metrics preceded by an ID are acknowledged after processing
+	serializer := &influxSerializer.Serializer{}
+	if err := serializer.Init(); err != nil {
+		return fmt.Errorf("creating serializer failed: %w", err)
+	}
+	reader := newAckReader(s.stdin)
+	var ackWarned bool
+
+	parser := influx.NewStreamParser(reader)
 	for {
 		m, err := parser.Next()
 		if err != nil {
@@ -62,15 +88,46 @@ func (s *Shim) RunProcessor() error {
 			var parseErr *influx.ParseError
 			if errors.As(err, &parseErr) {
 				fmt.Fprintf(s.stderr, "Failed to parse metric: %s\b", parseErr)
+				if id, ok := reader.next(); ok {
+					if err := s.writeAck(serializer, id, nil, parseErr); err != nil {
+						s.log.Warn(err.Error())
+					}
+				}
 				continue
 			}
 			fmt.Fprintf(s.stderr, "Failure during reading stdin: %s\b", err)
 			continue
 		}
 
-		if err = s.Processor.Add(m, acc); err != nil {
+		id, ok := reader.next()
+		if !ok {
+			if err = s.Processor.Add(m, acc); err != nil {
+				fmt.Fprintf(s.stderr, "Failure during processing metric by processor: %v\b", err)
+			}
+			continue
+		}
+
+		// Asynchronous processors might emit metrics after Add() returned, so
+		// it is unknown when the metric is handled completely.
+		if !s.processorSync {
+			if !ackWarned {
+				fmt.Fprintf(s.stderr, "Rejecting metrics: %v\b", errAckStreaming)
+				ackWarned = true
+			}
+			m.Drop()
+			if err := s.writeAck(serializer, id, nil, errAckStreaming); err != nil {
+				s.log.Warn(err.Error())
+			}
+			continue
+		}
+
+		collector := &ackCollector{Accumulator: acc}
+		if err = s.Processor.Add(m, collector); err != nil {
 			fmt.Fprintf(s.stderr, "Failure during processing metric by processor: %v\b", err)
 		}
+		if err := s.writeAck(serializer, id, collector.metrics, err); err != nil {
+			s.log.Warn(err.Error())
+		}
 	}
 
 	close(s.metricCh)
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"io"
 	"math/rand"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -112,3 +113,106 @@ func (p *testProcessor) SampleConfig() string {
 func (p *testProcessor) Description() string {
 	return ""
 }
+
+func TestProcessorShimAck(t *testing.T) {
+	p := &testProcessor{"hi", "mom"}
+
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+
+	s := New()
+	s.stdin = stdinReader
+	s.stdout = stdoutWriter
+	require.NoError(t, s.AddProcessor(p))
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		err := s.RunProcessor()
+		require.NoError(t, err)
+		wg.Done()
+	}()
+
+	// The processed metrics are written along with the acknowledgement,
+	// invalid metrics are rejected
+	go func() {
+		_, err := stdinWriter.Write([]byte("#telegraf:id=1\nthing v=1i 0\n#telegraf:id=2\nthing v=\n#telegraf:id=3\nthing v=2i 0\n"))
+		require.NoError(t, err)
+		require.NoError(t, stdinWriter.Close())
+	}()
+
+	expected := []string{
+		"#telegraf:id=1\n",
+		"thing,hi=mom v=1i 0\n",
+		"#telegraf:ack=1\n",
+		"#telegraf:id=2\n",
+		"#telegraf:nack=2 ",
+		"#telegraf:id=3\n",
+		"thing,hi=mom v=2i 0\n",
+		"#telegraf:ack=3\n",
+	}
+	r := bufio.NewReader(stdoutReader)
+	for _, line := range expected {
+		out, err := r.ReadString('\n')
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(out, line), "expected %q but got %q", line, out)
+	}
+
+	go func() {
+		_, _ = io.ReadAll(r)
+	}()
+	wg.Wait()
+}
+
+type testStreamingProcessor struct{}
+
+func (*testStreamingProcessor) SampleConfig() string             { return "" }
+func (*testStreamingProcessor) Start(telegraf.Accumulator) error { return nil }
+func (*testStreamingProcessor) Stop()                            {}
+func (*testStreamingProcessor) Add(m telegraf.Metric, acc telegraf.Accumulator) error {
+	acc.AddMetric(m)
+	return nil
+}
+
+func TestProcessorShimAckStreaming(t *testing.T) {
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+
+	s := New()
+	s.stdin = stdinReader
+	s.stdout = stdoutWriter
+	require.NoError(t, s.AddStreamingProcessor(&testStreamingProcessor{}))
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		err := s.RunProcessor()
+		require.NoError(t, err)
+		wg.Done()
+	}()
+
+	// Metrics with an ID are rejected as the processor might emit
+	// metrics asynchronously, metrics without an ID are processed
+	go func() {
+		_, err := stdinWriter.Write([]byte("#telegraf:id=1\nthing v=1i 0\nthing v=2i 0\n"))
+		require.NoError(t, err)
+		require.NoError(t, stdinWriter.Close())
+	}()
+
+	expected := []string{
+		"#telegraf:id=1\n",
+		"#telegraf:nack=1 ",
+		"thing v=2i 0\n",
+	}
+	r := bufio.NewReader(stdoutReader)
+	for _, line := range expected {
+		out, err := r.ReadString('\n')
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(out, line), "expected %q but got %q", line, out)
+	}
+
+	go func() {
+		_, _ = io.ReadAll(r)
+	}()
+	wg.Wait()
+}
@@ -82,6 +82,12 @@ func (s *Common) GetParameters(name string) (starlark.Tuple, bool) {
 	return parameters, found
 }
 
+// IsDefined returns true if the script defines a global of the given name.
+func (s *Common) IsDefined(name string) bool {
+	_, found := s.globals[name]
+	return found
+}
+
 func (s *Common) AddFunction(name string, params ...starlark.Value) error {
 	globalFn, found := s.globals[name]
 	if !found {
@@ -69,6 +69,18 @@ type DirectoryMonitor struct {
 	fileRegexesToMatch  []*regexp.Regexp
 	fileRegexesToIgnore []*regexp.Regexp
 	filesToProcess      chan string
+	fileStates          map[string]fileState
+	fileStatesMutex     sync.Mutex
+}
+
+// fileState is the processing progress of a file persisted across restarts
+type fileState struct {
+	// Number of lines already processed
+	Lines int64 `json:"lines"`
+	// Number of chunks already processed by stream parsers
+	Chunks int64 `json:"chunks,omitempty"`
+	// The file was processed completely but not moved yet
+	Finished bool `json:"finished"`
 }
 
 func (*DirectoryMonitor) SampleConfig() string {
@@ -203,26 +215,34 @@ func (monitor *DirectoryMonitor) processFile(path string) {
 }
 
 func (monitor *DirectoryMonitor) read(filePath string) {
-	// Open, read, and parse the contents of the file.
-	err := monitor.ingestFile(filePath)
-	var pathErr *os.PathError
-	if errors.As(err, &pathErr) {
-		return
-	}
+	// Files processed completely before, e.g. if moving them failed, are only
+	// moved to avoid duplicate metrics.
+	if !monitor.getFileState(filePath).Finished {
+		// Open, read, and parse the contents of the file.
+		err := monitor.ingestFile(filePath)
+		var pathErr *os.PathError
+		if errors.As(err, &pathErr) {
+			return
+		}
 
-	// Handle a file read error. We don't halt execution but do document, log, and move the problematic file.
-	if err != nil {
-		monitor.Log.Errorf("Error while reading file: '" + filePath + "'. " + err.Error())
-		monitor.filesDropped.Incr(1)
-		monitor.filesDroppedDir.Incr(1)
-		if monitor.ErrorDirectory != "" {
-			monitor.moveFile(filePath, monitor.ErrorDirectory)
+		// Handle a file read error. We don't halt execution but do document, log, and move the problematic file.
+		if err != nil {
+			monitor.Log.Errorf("Error while reading file: '" + filePath + "'. " + err.Error())
+			monitor.filesDropped.Incr(1)
+			monitor.filesDroppedDir.Incr(1)
+			if monitor.ErrorDirectory != "" {
+				monitor.moveFile(filePath, monitor.ErrorDirectory)
+				monitor.removeFileStateIfMoved(filePath)
+			}
+			return
 		}
-		return
+
+		monitor.updateFileState(filePath, func(state *fileState) { state.Finished = true })
 	}
 
 	// File is finished, move it to the 'finished' directory.
 	monitor.moveFile(filePath, monitor.FinishedDirectory)
+	monitor.removeFileStateIfMoved(filePath)
 	monitor.filesProcessed.Incr(1)
 	monitor.filesProcessedDir.Incr(1)
 }
@@ -254,6 +274,11 @@ func (monitor *DirectoryMonitor) ingestFile(filePath string) error {
 }
 
 func (monitor *DirectoryMonitor) parseFile(parser telegraf.Parser, reader io.Reader, fileName string) error {
+	// Parsers of binary formats read the file in chunks
+	if sp, ok := parser.(telegraf.StreamParser); ok {
+		return monitor.parseStream(sp, reader, fileName)
+	}
+
 	var splitter bufio.SplitFunc
 
 	// Decide on how to split the file
@@ -269,7 +294,19 @@ func (monitor *DirectoryMonitor) parseFile(parser telegraf.Parser, reader io.Rea
 	scanner := bufio.NewScanner(reader)
 	scanner.Split(splitter)
 
+	// Skip the lines processed before a restart
+	skip := monitor.getFileState(fileName).Lines
+	if skip > 0 {
+		monitor.Log.Debugf("Skipping %d already processed lines of %q", skip, fileName)
+	}
+
+	var lines int64
 	for scanner.Scan() {
+		lines++
+		if lines <= skip {
+			continue
+		}
+
 		metrics, err := monitor.parseMetrics(parser, scanner.Bytes(), fileName)
 		if err != nil {
 			return err
@@ -278,11 +315,35 @@ func (monitor *DirectoryMonitor) parseFile(parser telegraf.Parser, reader io.Rea
 		if err := monitor.sendMetrics(metrics); err != nil {
 			return err
 		}
+		monitor.updateFileState(fileName, func(state *fileState) { state.Lines = lines })
 	}
 
 	return scanner.Err()
 }
 
+func (monitor *DirectoryMonitor) parseStream(parser telegraf.StreamParser, reader io.Reader, fileName string) error {
+	// Skip the chunks processed before a restart
+	skip := monitor.getFileState(fileName).Chunks
+	if skip > 0 {
+		monitor.Log.Debugf("Skipping %d already processed chunks of %q", skip, fileName)
+	}
+
+	var chunks int64
+	return parser.ParseStream(reader, func(metrics []telegraf.Metric) error {
+		chunks++
+		if chunks <= skip {
+			return nil
+		}
+
+		monitor.addFileTag(metrics, fileName)
+		if err := monitor.sendMetrics(metrics); err != nil {
+			return err
+		}
+		monitor.updateFileState(fileName, func(state *fileState) { state.Chunks = chunks })
+		return nil
+	})
+}
+
 func (monitor *DirectoryMonitor) parseAtOnce(parser telegraf.Parser, reader io.Reader, fileName string) error {
 	bytes, err := io.ReadAll(reader)
 	if err != nil {
@@ -306,15 +367,20 @@ func (monitor *DirectoryMonitor) parseMetrics(parser telegraf.Parser, line []byt
 		return nil, err
 	}
 
-	if monitor.FileTag != "" {
-		for _, m := range metrics {
-			m.AddTag(monitor.FileTag, filepath.Base(fileName))
-		}
-	}
+	monitor.addFileTag(metrics, fileName)
 
 	return metrics, err
 }
 
+func (monitor *DirectoryMonitor) addFileTag(metrics []telegraf.Metric, fileName string) {
+	if monitor.FileTag == "" {
+		return
+	}
+	for _, m := range metrics {
+		m.AddTag(monitor.FileTag, filepath.Base(fileName))
+	}
+}
+
 func (monitor *DirectoryMonitor) sendMetrics(metrics []telegraf.Metric) error {
 	// Report the metrics for the file.
 	for _, m := range metrics {
@@ -365,6 +431,65 @@ func (monitor *DirectoryMonitor) moveFile(srcPath string, dstBaseDir string) {
 	}
 }
 
+func (monitor *DirectoryMonitor) getFileState(filePath string) fileState {
+	monitor.fileStatesMutex.Lock()
+	defer monitor.fileStatesMutex.Unlock()
+
+	return monitor.fileStates[filePath]
+}
+
+func (monitor *DirectoryMonitor) updateFileState(filePath string, update func(*fileState)) {
+	monitor.fileStatesMutex.Lock()
+	defer monitor.fileStatesMutex.Unlock()
+
+	state := monitor.fileStates[filePath]
+	update(&state)
+	monitor.fileStates[filePath] = state
+}
+
+func (monitor *DirectoryMonitor) removeFileStateIfMoved(filePath string) {
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		return
+	}
+
+	monitor.fileStatesMutex.Lock()
+	defer monitor.fileStatesMutex.Unlock()
+
+	delete(monitor.fileStates, filePath)
+}
+
+// GetState returns the processing progress of the files not yet moved
+func (monitor *DirectoryMonitor) GetState() interface{} {
+	monitor.fileStatesMutex.Lock()
+	defer monitor.fileStatesMutex.Unlock()
+
+	states := make(map[string]fileState, len(monitor.fileStates))
+	for k, v := range monitor.fileStates {
+		states[k] = v
+	}
+	return states
+}
+
+// SetState restores the processing progress of files
+func (monitor *DirectoryMonitor) SetState(state interface{}) error {
+	states, ok := state.(map[string]fileState)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", state)
+	}
+
+	monitor.fileStatesMutex.Lock()
+	defer monitor.fileStatesMutex.Unlock()
+
+	for k, v := range states {
+		// Forget about files that vanished in the meantime
+		if _, err := os.Stat(k); os.IsNotExist(err) {
+			continue
+		}
+		monitor.fileStates[k] = v
+	}
+	return nil
+}
+
 func (monitor *DirectoryMonitor) isMonitoredFile(fileName string) bool {
 	if len(monitor.fileRegexesToMatch) == 0 {
 		return true
@@ -435,6 +560,7 @@ func (monitor *DirectoryMonitor) Init() error {
 	monitor.sem = semaphore.NewWeighted(int64(monitor.MaxBufferedMetrics))
 	monitor.context, monitor.cancel = context.WithCancel(context.Background())
 	monitor.filesToProcess = make(chan string, monitor.FileQueueSize)
+	monitor.fileStates = make(map[string]fileState)
 
 	// Establish file matching / exclusion regexes.
 	for _, matcher := range monitor.FilesToMonitor {
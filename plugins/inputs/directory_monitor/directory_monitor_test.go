@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/parsers/csv"
 	"github.com/influxdata/telegraf/plugins/parsers/json"
+	"github.com/influxdata/telegraf/plugins/parsers/parquet"
+	parquetSerializer "github.com/influxdata/telegraf/plugins/serializers/parquet"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -533,6 +536,61 @@ func TestParseCompleteFile(t *testing.T) {
 	testutil.RequireMetricEqual(t, testutil.TestMetric(100.1), acc.GetTelegrafMetrics()[0], testutil.IgnoreTime())
 }
 
+func TestParseParquetFile(t *testing.T) {
+	acc := testutil.Accumulator{}
+
+	// Establish process directory and finished directory.
+	finishedDirectory := t.TempDir()
+	processDirectory := t.TempDir()
+
+	// Init plugin, binary formats are read in chunks regardless of the method.
+	r := DirectoryMonitor{
+		Directory:          processDirectory,
+		FinishedDirectory:  finishedDirectory,
+		MaxBufferedMetrics: defaultMaxBufferedMetrics,
+		FileQueueSize:      defaultFileQueueSize,
+		FileTag:            "filename",
+		ParseMethod:        "line-by-line",
+	}
+	require.NoError(t, r.Init())
+	r.Log = testutil.Logger{}
+
+	r.SetParserFunc(func() (telegraf.Parser, error) {
+		parser := &parquet.Parser{
+			MeasurementColumn: "measurement",
+			TimestampColumn:   "time",
+			BatchSize:         2,
+		}
+		err := parser.Init()
+		return parser, err
+	})
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("test", map[string]string{"filename": "test.parquet"}, map[string]interface{}{"value": 1.0}, time.Unix(1, 0)),
+		testutil.MustMetric("test", map[string]string{"filename": "test.parquet"}, map[string]interface{}{"value": 2.0}, time.Unix(2, 0)),
+		testutil.MustMetric("test", map[string]string{"filename": "test.parquet"}, map[string]interface{}{"value": 3.0}, time.Unix(3, 0)),
+	}
+	input := make([]telegraf.Metric, 0, len(expected))
+	for _, m := range expected {
+		m = m.Copy()
+		m.RemoveTag("filename")
+		input = append(input, m)
+	}
+	serializer := &parquetSerializer.Serializer{}
+	require.NoError(t, serializer.Init())
+	buf, err := serializer.SerializeBatch(input)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(processDirectory, "test.parquet"), buf, 0600))
+
+	require.NoError(t, r.Start(&acc))
+	require.NoError(t, r.Gather(&acc))
+	acc.Wait(len(expected))
+	r.Stop()
+
+	require.NoError(t, acc.FirstError())
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
 func TestParseSubdirectories(t *testing.T) {
 	acc := testutil.Accumulator{}
 
@@ -680,3 +738,60 @@ func TestParseSubdirectoriesFilesIgnore(t *testing.T) {
 	_, err = os.Stat(filepath.Join(finishedDirectory, testJSONFile))
 	require.NoError(t, err)
 }
+
+func TestState(t *testing.T) {
+	acc := testutil.Accumulator{}
+	testJSONFile := "test.json"
+
+	// Establish process directory and finished directory.
+	finishedDirectory := t.TempDir()
+	processDirectory := t.TempDir()
+	filePath := filepath.Join(processDirectory, testJSONFile)
+
+	// Init plugin.
+	r := DirectoryMonitor{
+		Directory:          processDirectory,
+		FinishedDirectory:  finishedDirectory,
+		MaxBufferedMetrics: defaultMaxBufferedMetrics,
+		FileQueueSize:      defaultFileQueueSize,
+		ParseMethod:        defaultParseMethod,
+		Log:                testutil.Logger{},
+	}
+	require.NoError(t, r.Init())
+
+	r.SetParserFunc(func() (telegraf.Parser, error) {
+		p := &json.Parser{NameKey: "Name"}
+		err := p.Init()
+		return p, err
+	})
+
+	// Write a 3-line LINE-DELIMITED json file of which the first two lines
+	// were processed before the restart.
+	require.NoError(t, os.WriteFile(filePath, []byte(
+		"{\"Name\": \"event1\",\"Speed\": 100.1}\n"+
+			"{\"Name\": \"event2\",\"Speed\": 500}\n"+
+			"{\"Name\": \"event3\",\"Speed\": 200}\n",
+	), 0640))
+
+	// State of vanished files must be ignored
+	state := map[string]fileState{
+		filePath: {Lines: 2},
+		filepath.Join(processDirectory, "vanished.json"): {Lines: 5},
+	}
+	require.NoError(t, r.SetState(state))
+	require.Equal(t, map[string]fileState{filePath: {Lines: 2}}, r.GetState())
+
+	require.NoError(t, r.Start(&acc))
+	require.NoError(t, r.Gather(&acc))
+	acc.Wait(1)
+	r.Stop()
+
+	// Only the unprocessed line must be read
+	require.Len(t, acc.Metrics, 1)
+	require.Equal(t, "event3", acc.Metrics[0].Measurement)
+
+	// The state of the moved file must be removed
+	require.Empty(t, r.GetState())
+	_, err := os.Stat(filepath.Join(finishedDirectory, testJSONFile))
+	require.NoError(t, err)
+}
@@ -45,16 +45,17 @@ func (f *File) Gather(acc telegraf.Accumulator) error {
 		return err
 	}
 	for _, k := range f.filenames {
-		metrics, err := f.readMetric(k)
-		if err != nil {
-			return err
-		}
-
-		for _, m := range metrics {
-			if f.FileTag != "" {
-				m.AddTag(f.FileTag, filepath.Base(k))
+		add := func(metrics []telegraf.Metric) error {
+			for _, m := range metrics {
+				if f.FileTag != "" {
+					m.AddTag(f.FileTag, filepath.Base(k))
+				}
+				acc.AddMetric(m)
 			}
-			acc.AddMetric(m)
+			return nil
+		}
+		if err := f.readMetrics(k, add); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -82,27 +83,36 @@ func (f *File) refreshFilePaths() error {
 	return nil
 }
 
-func (f *File) readMetric(filename string) ([]telegraf.Metric, error) {
+func (f *File) readMetrics(filename string, add func([]telegraf.Metric) error) error {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer file.Close()
 
-	r, _ := utfbom.Skip(f.decoder.Reader(file))
-	fileContents, err := io.ReadAll(r)
+	parser, err := f.parserFunc()
 	if err != nil {
-		return nil, fmt.Errorf("could not read %q: %w", filename, err)
+		return fmt.Errorf("could not instantiate parser: %w", err)
 	}
-	parser, err := f.parserFunc()
+
+	// Parsers of binary formats read the file in chunks
+	if sp, ok := parser.(telegraf.StreamParser); ok {
+		if err := sp.ParseStream(file, add); err != nil {
+			return fmt.Errorf("could not parse %q: %w", filename, err)
+		}
+		return nil
+	}
+
+	r, _ := utfbom.Skip(f.decoder.Reader(file))
+	fileContents, err := io.ReadAll(r)
 	if err != nil {
-		return nil, fmt.Errorf("could not instantiate parser: %w", err)
+		return fmt.Errorf("could not read %q: %w", filename, err)
 	}
 	metrics, err := parser.Parse(fileContents)
 	if err != nil {
-		return metrics, fmt.Errorf("could not parse %q: %w", filename, err)
+		return fmt.Errorf("could not parse %q: %w", filename, err)
 	}
-	return metrics, err
+	return add(metrics)
 }
 
 func init() {
@@ -19,6 +19,8 @@ import (
 	"github.com/influxdata/telegraf/plugins/parsers/csv"
 	"github.com/influxdata/telegraf/plugins/parsers/grok"
 	"github.com/influxdata/telegraf/plugins/parsers/json"
+	"github.com/influxdata/telegraf/plugins/parsers/parquet"
+	parquetSerializer "github.com/influxdata/telegraf/plugins/serializers/parquet"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -63,6 +65,40 @@ func TestFileTag(t *testing.T) {
 	}
 }
 
+func TestStreamParser(t *testing.T) {
+	expected := []telegraf.Metric{
+		testutil.MustMetric("test", map[string]string{}, map[string]interface{}{"value": 1.0}, time.Unix(1, 0)),
+		testutil.MustMetric("test", map[string]string{}, map[string]interface{}{"value": 2.0}, time.Unix(2, 0)),
+		testutil.MustMetric("test", map[string]string{}, map[string]interface{}{"value": 3.0}, time.Unix(3, 0)),
+	}
+	serializer := &parquetSerializer.Serializer{}
+	require.NoError(t, serializer.Init())
+	buf, err := serializer.SerializeBatch(expected)
+	require.NoError(t, err)
+
+	fn := filepath.Join(t.TempDir(), "test.parquet")
+	require.NoError(t, os.WriteFile(fn, buf, 0600))
+
+	r := File{
+		Files: []string{fn},
+	}
+	require.NoError(t, r.Init())
+
+	r.SetParserFunc(func() (telegraf.Parser, error) {
+		p := &parquet.Parser{
+			MeasurementColumn: "measurement",
+			TimestampColumn:   "time",
+			BatchSize:         2,
+		}
+		err := p.Init()
+		return p, err
+	})
+
+	var acc testutil.Accumulator
+	require.NoError(t, r.Gather(&acc))
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
 func TestJSONParserCompile(t *testing.T) {
 	var acc testutil.Accumulator
 	wd, _ := os.Getwd()
@@ -0,0 +1,150 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package internal_log
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type InternalLog struct {
+	Levels     []string        `toml:"levels"`
+	RateLimit  int             `toml:"rate_limit"`
+	BufferSize int             `toml:"buffer_size"`
+	Log        telegraf.Logger `toml:"-"`
+
+	levels  map[string]bool
+	events  chan models.LogEvent
+	remove  func()
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
+
+	// Rate limiting state, protected by the mutex as messages are received
+	// from all logging plugins concurrently
+	mu     sync.Mutex
+	window time.Time
+	count  int
+}
+
+func (*InternalLog) SampleConfig() string {
+	return sampleConfig
+}
+
+func (l *InternalLog) Init() error {
+	if l.RateLimit < 0 {
+		return fmt.Errorf("invalid rate limit %d", l.RateLimit)
+	}
+	if l.BufferSize <= 0 {
+		return fmt.Errorf("invalid buffer size %d", l.BufferSize)
+	}
+
+	l.levels = make(map[string]bool, len(l.Levels))
+	for _, level := range l.Levels {
+		switch level {
+		case "debug", "info", "warn", "error":
+			l.levels[level] = true
+		default:
+			return fmt.Errorf("invalid level %q", level)
+		}
+	}
+	return nil
+}
+
+func (l *InternalLog) Start(acc telegraf.Accumulator) error {
+	l.events = make(chan models.LogEvent, l.BufferSize)
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		for event := range l.events {
+			tags := map[string]string{
+				"level":    event.Level,
+				"category": event.Category,
+				"plugin":   event.Plugin,
+			}
+			if event.Alias != "" {
+				tags["alias"] = event.Alias
+			}
+			acc.AddFields("internal_log", map[string]interface{}{"message": event.Message}, tags, event.Time)
+		}
+	}()
+
+	l.remove = models.AddLogListener(l.receive)
+	return nil
+}
+
+func (l *InternalLog) Gather(_ telegraf.Accumulator) error {
+	if dropped := l.dropped.Swap(0); dropped > 0 {
+		l.Log.Warnf("Dropped %d log message(s) exceeding the rate limit or buffer size", dropped)
+	}
+	return nil
+}
+
+func (l *InternalLog) Stop() {
+	// Removing the listener waits for all running notifications so closing
+	// the channel afterwards is safe.
+	l.remove()
+	close(l.events)
+	l.wg.Wait()
+}
+
+// receive is called for every message logged by a plugin and must neither
+// block nor log.
+func (l *InternalLog) receive(event models.LogEvent) {
+	if !l.levels[event.Level] {
+		return
+	}
+
+	// Skip the messages of this plugin, e.g. the warnings about dropped
+	// messages, to not feed them back and keep the rate limit saturated
+	if event.Category == "inputs" && event.Plugin == "internal_log" {
+		return
+	}
+
+	if !l.allowed(event.Time) {
+		l.dropped.Add(1)
+		return
+	}
+
+	select {
+	case l.events <- event:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// allowed returns true if the rate limit is not exceeded for the current
+// one-second window.
+func (l *InternalLog) allowed(t time.Time) bool {
+	if l.RateLimit == 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t.Sub(l.window) >= time.Second {
+		l.window = t
+		l.count = 0
+	}
+	l.count++
+	return l.count <= l.RateLimit
+}
+
+func init() {
+	inputs.Add("internal_log", func() telegraf.Input {
+		return &InternalLog{
+			Levels:     []string{"warn", "error"},
+			RateLimit:  10,
+			BufferSize: 1000,
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package internal_log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestCollectMessages(t *testing.T) {
+	plugin := &InternalLog{
+		Levels:     []string{"warn", "error"},
+		BufferSize: 10,
+		Log:        testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+
+	logger := models.NewLogger("inputs", "test", "mytest")
+	logger.Debug("debug message")
+	logger.Infof("info %s", "message")
+	logger.Warnf("warn %s", "message")
+	models.NewLogger("outputs", "test", "").Error("error message")
+
+	plugin.Stop()
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"internal_log",
+			map[string]string{
+				"level":    "warn",
+				"category": "inputs",
+				"plugin":   "test",
+				"alias":    "mytest",
+			},
+			map[string]interface{}{"message": "warn message"},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"internal_log",
+			map[string]string{
+				"level":    "error",
+				"category": "outputs",
+				"plugin":   "test",
+			},
+			map[string]interface{}{"message": "error message"},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+
+	// Messages are not collected after stopping the plugin
+	logger.Error("late message")
+	require.Len(t, acc.GetTelegrafMetrics(), 2)
+}
+
+func TestRateLimit(t *testing.T) {
+	plugin := &InternalLog{
+		Levels:     []string{"error"},
+		RateLimit:  3,
+		BufferSize: 10,
+		Log:        testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.events = make(chan models.LogEvent, plugin.BufferSize)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		plugin.receive(models.LogEvent{Time: now, Level: "error"})
+	}
+	require.Len(t, plugin.events, 3)
+	require.Equal(t, uint64(2), plugin.dropped.Load())
+
+	// The limit applies per second
+	plugin.receive(models.LogEvent{Time: now.Add(time.Second), Level: "error"})
+	require.Len(t, plugin.events, 4)
+
+	// Dropped messages are reset on gather
+	require.NoError(t, plugin.Gather(&testutil.Accumulator{}))
+	require.Zero(t, plugin.dropped.Load())
+
+	// The warnings about dropped messages are not collected and do not
+	// count against the limit
+	plugin.receive(models.LogEvent{Time: now.Add(time.Second), Level: "error", Category: "inputs", Plugin: "internal_log"})
+	require.Len(t, plugin.events, 4)
+	require.Zero(t, plugin.dropped.Load())
+}
+
+func TestBufferOverflow(t *testing.T) {
+	plugin := &InternalLog{
+		Levels:     []string{"error"},
+		BufferSize: 2,
+		Log:        testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.events = make(chan models.LogEvent, plugin.BufferSize)
+
+	for i := 0; i < 5; i++ {
+		plugin.receive(models.LogEvent{Time: time.Now(), Level: "error"})
+	}
+	require.Len(t, plugin.events, 2)
+	require.Equal(t, uint64(3), plugin.dropped.Load())
+}
+
+func TestInvalidConfig(t *testing.T) {
+	plugin := &InternalLog{Levels: []string{"fatal"}, BufferSize: 10}
+	require.ErrorContains(t, plugin.Init(), `invalid level "fatal"`)
+
+	plugin = &InternalLog{Levels: []string{"error"}, RateLimit: -1, BufferSize: 10}
+	require.ErrorContains(t, plugin.Init(), "invalid rate limit")
+
+	plugin = &InternalLog{Levels: []string{"error"}}
+	require.ErrorContains(t, plugin.Init(), "invalid buffer size")
+}
@@ -67,6 +67,39 @@ type KafkaConsumer struct {
 	topicLock sync.Mutex
 	wg        sync.WaitGroup
 	cancel    context.CancelFunc
+
+	state *offsetStore
+}
+
+// offsetStore keeps the offset of the next message to consume per topic and
+// partition for all delivered messages.
+type offsetStore struct {
+	offsets map[string]map[int32]int64
+	sync.Mutex
+}
+
+func newOffsetStore() *offsetStore {
+	return &offsetStore{offsets: make(map[string]map[int32]int64)}
+}
+
+func (s *offsetStore) get(topic string, partition int32) (int64, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	offset, found := s.offsets[topic][partition]
+	return offset, found
+}
+
+func (s *offsetStore) set(topic string, partition int32, offset int64) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, found := s.offsets[topic]; !found {
+		s.offsets[topic] = make(map[int32]int64)
+	}
+	if offset > s.offsets[topic][partition] {
+		s.offsets[topic][partition] = offset
+	}
 }
 
 type ConsumerGroup interface {
@@ -102,6 +135,8 @@ func (k *KafkaConsumer) Init() error {
 	if time.Duration(k.MaxProcessingTime) == 0 {
 		k.MaxProcessingTime = defaultMaxProcessingTime
 	}
+	k.state = newOffsetStore()
+
 	if k.ConsumerGroup == "" {
 		k.ConsumerGroup = defaultConsumerGroup
 	}
@@ -311,6 +346,7 @@ func (k *KafkaConsumer) Start(acc telegraf.Accumulator) error {
 			handler := NewConsumerGroupHandler(acc, k.MaxUndeliveredMessages, k.parser, k.Log)
 			handler.MaxMessageLen = k.MaxMessageLen
 			handler.TopicTag = k.TopicTag
+			handler.state = k.state
 			// We need to copy allWantedTopics; the Consume() is
 			// long-running and we can easily deadlock if our
 			// topic-update-checker fires.
@@ -351,6 +387,38 @@ func (k *KafkaConsumer) Stop() {
 	k.wg.Wait()
 }
 
+// GetState returns the offsets of the next message to consume per topic and
+// partition.
+func (k *KafkaConsumer) GetState() interface{} {
+	k.state.Lock()
+	defer k.state.Unlock()
+
+	state := make(map[string]map[int32]int64, len(k.state.offsets))
+	for topic, partitions := range k.state.offsets {
+		state[topic] = make(map[int32]int64, len(partitions))
+		for partition, offset := range partitions {
+			state[topic][partition] = offset
+		}
+	}
+	return state
+}
+
+// SetState restores the offsets of the next message to consume per topic and
+// partition.
+func (k *KafkaConsumer) SetState(state interface{}) error {
+	offsets, ok := state.(map[string]map[int32]int64)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", state)
+	}
+
+	for topic, partitions := range offsets {
+		for partition, offset := range partitions {
+			k.state.set(topic, partition, offset)
+		}
+	}
+	return nil
+}
+
 // Message is an aggregate type binding the Kafka message and the session so
 // that offsets can be updated.
 type Message struct {
@@ -383,14 +451,29 @@ type ConsumerGroupHandler struct {
 	mu          sync.Mutex
 	undelivered map[telegraf.TrackingID]Message
 
+	state *offsetStore
+
 	log telegraf.Logger
 }
 
 // Setup is called once when a new session is opened.  It setups up the handler
 // and begins processing delivered messages.
-func (h *ConsumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+func (h *ConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
 	h.undelivered = make(map[telegraf.TrackingID]Message)
 
+	// Skip messages delivered before a restart but not yet committed to the
+	// broker. Marking an offset only advances the committed offset, so newer
+	// offsets committed by other members of the group take precedence.
+	if h.state != nil && session != nil {
+		for topic, partitions := range session.Claims() {
+			for _, partition := range partitions {
+				if offset, found := h.state.get(topic, partition); found {
+					session.MarkOffset(topic, partition, offset, "")
+				}
+			}
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	h.cancel = cancel
 
@@ -425,13 +508,22 @@ func (h *ConsumerGroupHandler) onDelivery(track telegraf.DeliveryInfo) {
 	}
 
 	if track.Delivered() {
-		msg.session.MarkMessage(msg.message, "")
+		h.markMessage(msg.session, msg.message)
 	}
 
 	delete(h.undelivered, track.ID())
 	<-h.sem
 }
 
+// markMessage marks the message as consumed and remembers its offset in the
+// state.
+func (h *ConsumerGroupHandler) markMessage(session sarama.ConsumerGroupSession, msg *sarama.ConsumerMessage) {
+	session.MarkMessage(msg, "")
+	if h.state != nil {
+		h.state.set(msg.Topic, msg.Partition, msg.Offset+1)
+	}
+}
+
 // Reserve blocks until there is an available slot for a new message.
 func (h *ConsumerGroupHandler) Reserve(ctx context.Context) error {
 	select {
@@ -450,7 +542,7 @@ func (h *ConsumerGroupHandler) release() {
 // after delivery.
 func (h *ConsumerGroupHandler) Handle(session sarama.ConsumerGroupSession, msg *sarama.ConsumerMessage) error {
 	if h.MaxMessageLen != 0 && len(msg.Value) > h.MaxMessageLen {
-		session.MarkMessage(msg, "")
+		h.markMessage(session, msg)
 		h.release()
 		return fmt.Errorf("message exceeds max_message_len (actual %d, max %d)",
 			len(msg.Value), h.MaxMessageLen)
@@ -470,6 +470,77 @@ func TestConsumerGroupHandler_Handle(t *testing.T) {
 	}
 }
 
+type offsetRecordingSession struct {
+	FakeConsumerGroupSession
+	claims map[string][]int32
+	marked map[string]map[int32]int64
+}
+
+func (s *offsetRecordingSession) Claims() map[string][]int32 {
+	return s.claims
+}
+
+func (s *offsetRecordingSession) MarkOffset(topic string, partition int32, offset int64, _ string) {
+	if _, found := s.marked[topic]; !found {
+		s.marked[topic] = make(map[int32]int64)
+	}
+	s.marked[topic][partition] = offset
+}
+
+func TestState(t *testing.T) {
+	plugin := &KafkaConsumer{
+		Brokers: []string{"localhost:9092"},
+		Topics:  []string{"telegraf"},
+		Log:     testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	state := map[string]map[int32]int64{
+		"telegraf": {0: 42, 1: 23},
+		"other":    {0: 5},
+	}
+	require.NoError(t, plugin.SetState(state))
+	require.Equal(t, state, plugin.GetState())
+	require.Error(t, plugin.SetState(map[string]int64{}))
+
+	// Only offsets of claimed partitions must be applied to the session
+	parser := value.Parser{
+		MetricName: "cpu",
+		DataType:   "int",
+	}
+	require.NoError(t, parser.Init())
+	cg := NewConsumerGroupHandler(&testutil.Accumulator{}, 1, &parser, testutil.Logger{})
+	cg.MaxMessageLen = 1
+	cg.state = plugin.state
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := &offsetRecordingSession{
+		FakeConsumerGroupSession: FakeConsumerGroupSession{ctx: ctx},
+		claims:                   map[string][]int32{"telegraf": {0, 2}},
+		marked:                   make(map[string]map[int32]int64),
+	}
+	require.NoError(t, cg.Setup(session))
+	require.Equal(t, map[string]map[int32]int64{"telegraf": {0: 42}}, session.marked)
+
+	// Consumed messages must advance the state
+	require.NoError(t, cg.Reserve(ctx))
+	msg := &sarama.ConsumerMessage{
+		Topic:     "telegraf",
+		Partition: 2,
+		Offset:    100,
+		Value:     []byte("too long"),
+	}
+	require.Error(t, cg.Handle(session, msg))
+	require.NoError(t, cg.Cleanup(session))
+
+	expected := map[string]map[int32]int64{
+		"telegraf": {0: 42, 1: 23, 2: 101},
+		"other":    {0: 5},
+	}
+	require.Equal(t, expected, plugin.GetState())
+}
+
 func TestKafkaRoundTripIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -49,6 +49,7 @@ type (
 		sem    chan struct{}
 
 		checkpoint    consumer.Store
+		state         *stateStore
 		checkpoints   map[string]checkpoint
 		records       map[telegraf.TrackingID]string
 		checkpointTex sync.Mutex
@@ -92,7 +93,7 @@ func (k *KinesisConsumer) connect(ac telegraf.Accumulator) error {
 	}
 	client := kinesis.NewFromConfig(cfg)
 
-	k.checkpoint = &noopStore{}
+	k.checkpoint = k.state
 	if k.DynamoDB != nil {
 		var err error
 		k.checkpoint, err = ddb.New(
@@ -308,13 +309,69 @@ func (k *KinesisConsumer) configureProcessContentEncodingFunc() error {
 }
 
 func (k *KinesisConsumer) Init() error {
+	k.state = &stateStore{sequenceNumbers: make(map[string]string)}
+
 	return k.configureProcessContentEncodingFunc()
 }
 
-type noopStore struct{}
+// GetState returns the last delivered sequence number of each shard if no
+// DynamoDB checkpoint is used
+func (k *KinesisConsumer) GetState() interface{} {
+	return k.state.get()
+}
+
+// SetState restores the sequence numbers to resume the shards from
+func (k *KinesisConsumer) SetState(state interface{}) error {
+	sequenceNumbers, ok := state.(map[string]string)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", state)
+	}
+	k.state.set(sequenceNumbers)
+
+	return nil
+}
+
+// stateStore keeps the checkpoints in memory if no DynamoDB checkpoint is
+// configured, so they can be persisted as state of the plugin.
+type stateStore struct {
+	sequenceNumbers map[string]string
+	sync.Mutex
+}
+
+func (s *stateStore) SetCheckpoint(_, shardID, sequenceNumber string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.sequenceNumbers[shardID] = sequenceNumber
+	return nil
+}
+
+func (s *stateStore) GetCheckpoint(_, shardID string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.sequenceNumbers[shardID], nil
+}
+
+func (s *stateStore) get() map[string]string {
+	s.Lock()
+	defer s.Unlock()
 
-func (n noopStore) SetCheckpoint(string, string, string) error   { return nil }
-func (n noopStore) GetCheckpoint(string, string) (string, error) { return "", nil }
+	sequenceNumbers := make(map[string]string, len(s.sequenceNumbers))
+	for k, v := range s.sequenceNumbers {
+		sequenceNumbers[k] = v
+	}
+	return sequenceNumbers
+}
+
+func (s *stateStore) set(sequenceNumbers map[string]string) {
+	s.Lock()
+	defer s.Unlock()
+
+	for k, v := range sequenceNumbers {
+		s.sequenceNumbers[k] = v
+	}
+}
 
 func init() {
 	negOne, _ = new(big.Int).SetString("-1", 10)
@@ -230,3 +230,24 @@ func TestKinesisConsumer_onMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestState(t *testing.T) {
+	k := &KinesisConsumer{ContentEncoding: "identity"}
+	require.NoError(t, k.Init())
+	require.Empty(t, k.GetState())
+
+	require.NoError(t, k.state.SetCheckpoint("stream", "shard-0", "42"))
+	require.Equal(t, map[string]string{"shard-0": "42"}, k.GetState())
+
+	// Restore the state in a new plugin instance
+	restored := &KinesisConsumer{ContentEncoding: "identity"}
+	require.NoError(t, restored.Init())
+	require.NoError(t, restored.SetState(k.GetState()))
+	restored.checkpoint = restored.state
+
+	seq, err := restored.GetCheckpoint("stream", "shard-0")
+	require.NoError(t, err)
+	require.Equal(t, "42", seq)
+
+	require.Error(t, restored.SetState("invalid"))
+}
@@ -37,6 +37,7 @@ func (s *traceService) Export(ctx context.Context, req ptraceotlp.ExportRequest)
 type metricsService struct {
 	pmetricotlp.UnimplementedGRPCServer
 	converter *otel2influx.OtelMetricsToLineProtocol
+	native    *nativeConverter
 }
 
 var _ pmetricotlp.GRPCServer = (*metricsService)(nil)
@@ -46,7 +47,7 @@ var metricsSchemata = map[string]common.MetricsSchema{
 	"prometheus-v2": common.MetricsSchemaTelegrafPrometheusV2,
 }
 
-func newMetricsService(logger common.Logger, writer *writeToAccumulator, schema string) (*metricsService, error) {
+func newMetricsService(logger common.Logger, writer *writeToAccumulator, schema string, native bool) (*metricsService, error) {
 	ms, found := metricsSchemata[schema]
 	if !found {
 		return nil, fmt.Errorf("schema %q not recognized", schema)
@@ -56,12 +57,23 @@ func newMetricsService(logger common.Logger, writer *writeToAccumulator, schema
 	if err != nil {
 		return nil, err
 	}
-	return &metricsService{
+	service := &metricsService{
 		converter: converter,
-	}, nil
+	}
+	if native {
+		service.native = &nativeConverter{
+			logger:       logger,
+			acc:          writer.accumulator,
+			prometheusV2: ms == common.MetricsSchemaTelegrafPrometheusV2,
+		}
+	}
+	return service, nil
 }
 
 func (s *metricsService) Export(ctx context.Context, req pmetricotlp.ExportRequest) (pmetricotlp.ExportResponse, error) {
+	if s.native != nil {
+		s.native.convert(req.Metrics())
+	}
 	err := s.converter.WriteMetrics(ctx, req.Metrics())
 	return pmetricotlp.NewExportResponse(), err
 }
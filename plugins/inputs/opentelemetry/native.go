@@ -0,0 +1,148 @@
+package opentelemetry
+
+import (
+	"math"
+
+	"github.com/influxdata/influxdb-observability/common"
+	"github.com/influxdata/influxdb-observability/otel2influx"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/influxdata/telegraf"
+)
+
+// nativeConverter converts histograms, exponential histograms and summaries
+// into single fields holding all buckets or quantiles. The metric name is used
+// as field key with the "prometheus" measurement for the prometheus-v2 schema,
+// otherwise it is used as measurement with a "value" field.
+type nativeConverter struct {
+	logger       common.Logger
+	acc          telegraf.Accumulator
+	prometheusV2 bool
+}
+
+// convert adds the supported metrics to the accumulator and removes them from
+// the given metrics to leave the others to the line-protocol converter.
+func (c *nativeConverter) convert(md pmetric.Metrics) {
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			tags := otel2influx.ResourceToTags(c.logger, rm.Resource(), make(map[string]string))
+			tags = otel2influx.InstrumentationScopeToTags(sm.Scope(), tags)
+
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				switch m.Type() {
+				case pmetric.MetricTypeHistogram:
+					for k := 0; k < m.Histogram().DataPoints().Len(); k++ {
+						dp := m.Histogram().DataPoints().At(k)
+						c.add(m.Name(), tags, dp.Attributes(), dp.Timestamp(), dp.Flags(), histogramValue(dp), telegraf.Histogram)
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					for k := 0; k < m.ExponentialHistogram().DataPoints().Len(); k++ {
+						dp := m.ExponentialHistogram().DataPoints().At(k)
+						c.add(m.Name(), tags, dp.Attributes(), dp.Timestamp(), dp.Flags(), exponentialValue(dp), telegraf.Histogram)
+					}
+				case pmetric.MetricTypeSummary:
+					for k := 0; k < m.Summary().DataPoints().Len(); k++ {
+						dp := m.Summary().DataPoints().At(k)
+						c.add(m.Name(), tags, dp.Attributes(), dp.Timestamp(), dp.Flags(), summaryValue(dp), telegraf.Summary)
+					}
+				default:
+					return false
+				}
+				return true
+			})
+		}
+	}
+}
+
+func (c *nativeConverter) add(
+	name string,
+	commonTags map[string]string,
+	attributes pcommon.Map,
+	ts pcommon.Timestamp,
+	flags pmetric.DataPointFlags,
+	value interface{},
+	tp telegraf.ValueType,
+) {
+	if flags.NoRecordedValue() {
+		return
+	}
+
+	tags := make(map[string]string, len(commonTags)+attributes.Len())
+	for k, v := range commonTags {
+		tags[k] = v
+	}
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		if s, err := otel2influx.AttributeValueToInfluxTagValue(v); err != nil {
+			c.logger.Debug("invalid data point attribute value", "key", k, err)
+		} else {
+			tags[k] = s
+		}
+		return true
+	})
+
+	measurement, key := name, "value"
+	if c.prometheusV2 {
+		measurement, key = common.MeasurementPrometheus, name
+	}
+	fields := map[string]interface{}{key: value}
+
+	if tp == telegraf.Summary {
+		c.acc.AddSummary(measurement, fields, tags, ts.AsTime())
+	} else {
+		c.acc.AddHistogram(measurement, fields, tags, ts.AsTime())
+	}
+}
+
+func histogramValue(dp pmetric.HistogramDataPoint) *telegraf.HistogramValue {
+	v := &telegraf.HistogramValue{
+		Count:   dp.Count(),
+		Sum:     dp.Sum(),
+		Buckets: make([]telegraf.Bucket, 0, dp.ExplicitBounds().Len()+1),
+	}
+
+	// Telegraf uses cumulative counts with an explicit +Inf bucket
+	var count uint64
+	for i := 0; i < dp.ExplicitBounds().Len(); i++ {
+		if i < dp.BucketCounts().Len() {
+			count += dp.BucketCounts().At(i)
+		}
+		v.Buckets = append(v.Buckets, telegraf.Bucket{UpperBound: dp.ExplicitBounds().At(i), Count: count})
+	}
+	v.Buckets = append(v.Buckets, telegraf.Bucket{UpperBound: math.Inf(1), Count: dp.Count()})
+	return v
+}
+
+func exponentialValue(dp pmetric.ExponentialHistogramDataPoint) *telegraf.HistogramValue {
+	return &telegraf.HistogramValue{
+		Count: dp.Count(),
+		Sum:   dp.Sum(),
+		Exponential: &telegraf.ExponentialBuckets{
+			Schema:    dp.Scale(),
+			ZeroCount: dp.ZeroCount(),
+			Positive: telegraf.BucketRange{
+				Offset: dp.Positive().Offset(),
+				Counts: dp.Positive().BucketCounts().AsRaw(),
+			},
+			Negative: telegraf.BucketRange{
+				Offset: dp.Negative().Offset(),
+				Counts: dp.Negative().BucketCounts().AsRaw(),
+			},
+		},
+	}
+}
+
+func summaryValue(dp pmetric.SummaryDataPoint) *telegraf.SummaryValue {
+	v := &telegraf.SummaryValue{
+		Count:     dp.Count(),
+		Sum:       dp.Sum(),
+		Quantiles: make([]telegraf.Quantile, 0, dp.QuantileValues().Len()),
+	}
+	for i := 0; i < dp.QuantileValues().Len(); i++ {
+		q := dp.QuantileValues().At(i)
+		v.Quantiles = append(v.Quantiles, telegraf.Quantile{Quantile: q.Quantile(), Value: q.Value()})
+	}
+	return v
+}
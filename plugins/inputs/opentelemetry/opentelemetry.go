@@ -26,6 +26,7 @@ var sampleConfig string
 type OpenTelemetry struct {
 	ServiceAddress string `toml:"service_address"`
 	MetricsSchema  string `toml:"metrics_schema"`
+	NativeValues   bool   `toml:"native_values"`
 
 	tls.ServerConfig
 	Timeout config.Duration `toml:"timeout"`
@@ -66,7 +67,7 @@ func (o *OpenTelemetry) Start(accumulator telegraf.Accumulator) error {
 		return err
 	}
 	ptraceotlp.RegisterGRPCServer(o.grpcServer, traceService)
-	ms, err := newMetricsService(logger, influxWriter, o.MetricsSchema)
+	ms, err := newMetricsService(logger, influxWriter, o.MetricsSchema, o.NativeValues)
 	if err != nil {
 		return err
 	}
@@ -3,11 +3,15 @@ package opentelemetry
 import (
 	"context"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"math"
 	"net"
 	"testing"
 	"time"
 
+	"github.com/influxdata/influxdb-observability/common"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"google.golang.org/grpc"
@@ -76,3 +80,112 @@ func TestOpenTelemetry(t *testing.T) {
 	require.Equal(t, telegraf.Counter, got.Type)
 	require.Equal(t, "library-name", got.Tags["otel.library.name"])
 }
+
+func TestNativeValues(t *testing.T) {
+	ts := time.Unix(0, 1622848686000000000)
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("host.name", "potato")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("library-name")
+
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("request_duration_seconds")
+	m.SetEmptyExponentialHistogram()
+	edp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+	edp.Attributes().PutStr("handler", "api")
+	edp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	edp.SetCount(7)
+	edp.SetSum(12.5)
+	edp.SetScale(3)
+	edp.SetZeroCount(1)
+	edp.Positive().SetOffset(-1)
+	edp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 0, 1})
+	edp.Negative().BucketCounts().FromRaw([]uint64{2})
+
+	m = sm.Metrics().AppendEmpty()
+	m.SetName("response_size_bytes")
+	m.SetEmptyHistogram()
+	hdp := m.Histogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	hdp.SetCount(4)
+	hdp.SetSum(2500)
+	hdp.ExplicitBounds().FromRaw([]float64{100, 1000})
+	hdp.BucketCounts().FromRaw([]uint64{1, 2, 1})
+
+	m = sm.Metrics().AppendEmpty()
+	m.SetName("rpc_duration_seconds")
+	m.SetEmptySummary()
+	sdp := m.Summary().DataPoints().AppendEmpty()
+	sdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	sdp.SetCount(3)
+	sdp.SetSum(1.5)
+	qv := sdp.QuantileValues().AppendEmpty()
+	qv.SetQuantile(0.5)
+	qv.SetValue(0.4)
+
+	m = sm.Metrics().AppendEmpty()
+	m.SetName("cpu_temp")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(87.332)
+
+	tags := map[string]string{"host.name": "potato", "otel.library.name": "library-name"}
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"host.name": "potato", "otel.library.name": "library-name", "handler": "api"},
+			map[string]interface{}{
+				"request_duration_seconds": &telegraf.HistogramValue{
+					Count: 7,
+					Sum:   12.5,
+					Exponential: &telegraf.ExponentialBuckets{
+						Schema:    3,
+						ZeroCount: 1,
+						Positive:  telegraf.BucketRange{Offset: -1, Counts: []uint64{1, 2, 0, 1}},
+						Negative:  telegraf.BucketRange{Offset: 0, Counts: []uint64{2}},
+					},
+				},
+			},
+			ts,
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			tags,
+			map[string]interface{}{
+				"response_size_bytes": &telegraf.HistogramValue{
+					Count: 4,
+					Sum:   2500,
+					Buckets: []telegraf.Bucket{
+						{UpperBound: 100, Count: 1},
+						{UpperBound: 1000, Count: 3},
+						{UpperBound: math.Inf(1), Count: 4},
+					},
+				},
+			},
+			ts,
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			tags,
+			map[string]interface{}{
+				"rpc_duration_seconds": &telegraf.SummaryValue{
+					Count:     3,
+					Sum:       1.5,
+					Quantiles: []telegraf.Quantile{{Quantile: 0.5, Value: 0.4}},
+				},
+			},
+			ts,
+			telegraf.Summary,
+		),
+	}
+
+	var acc testutil.Accumulator
+	converter := &nativeConverter{logger: common.NoopLogger{}, acc: &acc, prometheusV2: true}
+	converter.convert(md)
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+
+	// Other metrics are left to the line-protocol converter
+	require.Equal(t, 1, sm.Metrics().Len())
+	require.Equal(t, "cpu_temp", sm.Metrics().At(0).Name())
+}
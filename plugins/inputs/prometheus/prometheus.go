@@ -35,6 +35,9 @@ var sampleConfig string
 
 const acceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3`
 
+// Prefer the OpenMetrics text format if enabled
+const openMetricsAcceptHeader = `application/openmetrics-text;version=1.0.0,application/openmetrics-text;version=0.0.1;q=0.75,` + acceptHeader
+
 type MonitorMethod string
 
 const (
@@ -66,8 +69,8 @@ type Prometheus struct {
 	ConsulConfig ConsulConfig `toml:"consul"`
 
 	// Bearer Token authorization file path
-	BearerToken       string `toml:"bearer_token"`
-	BearerTokenString string `toml:"bearer_token_string"`
+	BearerToken       string        `toml:"bearer_token"`
+	BearerTokenString config.Secret `toml:"bearer_token_string"`
 
 	// Basic authentication credentials
 	Username string `toml:"username"`
@@ -83,6 +86,10 @@ type Prometheus struct {
 
 	IgnoreTimestamp bool `toml:"ignore_timestamp"`
 
+	NativeValues bool `toml:"native_values"`
+
+	OpenMetrics bool `toml:"openmetrics"`
+
 	Log telegraf.Logger
 
 	httpconfig.HTTPClientConfig
@@ -143,6 +150,13 @@ func (*Prometheus) SampleConfig() string {
 }
 
 func (p *Prometheus) Init() error {
+	if p.NativeValues && p.MetricVersion != 2 {
+		return errors.New("native_values requires metric_version = 2")
+	}
+	if p.OpenMetrics && p.MetricVersion != 2 {
+		return errors.New("openmetrics requires metric_version = 2")
+	}
+
 	// Config processing for node scrape scope for monitor_kubernetes_pods
 	p.isNodeScrapeScope = strings.EqualFold(p.PodScrapeScope, "node")
 	if p.isNodeScrapeScope {
@@ -223,6 +237,9 @@ func (p *Prometheus) Init() error {
 		"User-Agent": internal.ProductToken(),
 		"Accept":     acceptHeader,
 	}
+	if p.OpenMetrics {
+		p.headers["Accept"] = openMetricsAcceptHeader
+	}
 
 	p.kubernetesPods = map[PodID]URLAndAddress{}
 
@@ -406,8 +423,13 @@ func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error
 			return err
 		}
 		req.Header.Set("Authorization", "Bearer "+string(token))
-	} else if p.BearerTokenString != "" {
-		req.Header.Set("Authorization", "Bearer "+p.BearerTokenString)
+	} else if !p.BearerTokenString.Empty() {
+		token, err := p.BearerTokenString.Get()
+		if err != nil {
+			return fmt.Errorf("getting token secret failed: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+string(token))
+		config.ReleaseSecret(token)
 	} else if p.Username != "" || p.Password != "" {
 		req.SetBasicAuth(p.Username, p.Password)
 	}
@@ -444,6 +466,7 @@ func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error
 		parser := parserV2.Parser{
 			Header:          resp.Header,
 			IgnoreTimestamp: p.IgnoreTimestamp,
+			NativeValues:    p.NativeValues,
 		}
 		metrics, err = parser.Parse(body)
 	} else {
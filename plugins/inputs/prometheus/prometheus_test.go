@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -425,6 +426,49 @@ func TestPrometheusGeneratesGaugeMetricsV2(t *testing.T) {
 	require.True(t, acc.HasTimestamp("prometheus", time.Unix(1490802350, 0)))
 }
 
+func TestPrometheusGeneratesOpenMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Accept"), "application/openmetrics-text") {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		_, err := fmt.Fprint(w, `# TYPE http_request_duration_seconds counter
+# UNIT http_request_duration_seconds seconds
+http_request_duration_seconds_total{path="/"} 12.5 # {trace_id="abc123"} 0.5 1490802350.0
+http_request_duration_seconds_created{path="/"} 1490802000.0
+# EOF
+`)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	p := &Prometheus{
+		Log:           testutil.Logger{},
+		URLs:          []string{ts.URL},
+		MetricVersion: 2,
+		OpenMetrics:   true,
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(p.Gather))
+
+	require.True(t, acc.HasFloatField("prometheus", "http_request_duration_seconds_total"))
+	require.True(t, acc.HasFloatField("prometheus", "http_request_duration_seconds_created"))
+	require.True(t, acc.HasFloatField("prometheus", "http_request_duration_seconds_total_exemplar"))
+	require.Equal(t, "abc123", acc.TagSetValue("prometheus", "trace_id"))
+	require.Equal(t, "seconds", acc.TagValue("prometheus", "unit"))
+
+	// The format relies on the metric version 2 parser
+	p = &Prometheus{
+		Log:         testutil.Logger{},
+		URLs:        []string{ts.URL},
+		OpenMetrics: true,
+	}
+	require.EqualError(t, p.Init(), "openmetrics requires metric_version = 2")
+}
+
 func TestPrometheusGeneratesMetricsWithIgnoreTimestamp(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, err := fmt.Fprintln(w, sampleTextFormat)
@@ -54,6 +54,7 @@ type Tail struct {
 	Log        telegraf.Logger `toml:"-"`
 	tailers    map[string]*tail.Tail
 	offsets    map[string]int64
+	stateMu    sync.Mutex // protects tailers and offsets
 	parserFunc telegraf.ParserFunc
 	wg         sync.WaitGroup
 
@@ -108,7 +109,24 @@ func (t *Tail) Init() error {
 }
 
 func (t *Tail) GetState() interface{} {
-	return t.offsets
+	t.stateMu.Lock()
+	defer t.stateMu.Unlock()
+
+	offsets := make(map[string]int64, len(t.offsets))
+	for k, v := range t.offsets {
+		offsets[k] = v
+	}
+
+	// Use the current position of the running tailers. Tailers that did not
+	// yet open their file report a zero offset, so keep the known one.
+	if !t.Pipe && !t.FromBeginning {
+		for filename, tailer := range t.tailers {
+			if offset, err := tailer.Tell(); err == nil && offset > 0 {
+				offsets[filename] = offset
+			}
+		}
+	}
+	return offsets
 }
 
 func (t *Tail) SetState(state interface{}) error {
@@ -116,6 +134,9 @@ func (t *Tail) SetState(state interface{}) error {
 	if !ok {
 		return errors.New("state has to be of type 'map[string]int64'")
 	}
+
+	t.stateMu.Lock()
+	defer t.stateMu.Unlock()
 	for k, v := range offsetsState {
 		t.offsets[k] = v
 	}
@@ -151,7 +172,9 @@ func (t *Tail) Start(acc telegraf.Accumulator) error {
 		return err
 	}
 
+	t.stateMu.Lock()
 	t.tailers = make(map[string]*tail.Tail)
+	t.stateMu.Unlock()
 
 	err = t.tailNewFiles(t.FromBeginning)
 
@@ -169,6 +192,9 @@ func (t *Tail) tailNewFiles(fromBeginning bool) error {
 		poll = true
 	}
 
+	t.stateMu.Lock()
+	defer t.stateMu.Unlock()
+
 	// Create a "tailer" for each file
 	for _, filepath := range t.Files {
 		g, err := globpath.Compile(filepath)
@@ -365,6 +391,7 @@ func (t *Tail) receiver(parser telegraf.Parser, tailer *tail.Tail) {
 }
 
 func (t *Tail) Stop() {
+	t.stateMu.Lock()
 	for _, tailer := range t.tailers {
 		if !t.Pipe && !t.FromBeginning {
 			// store offset for resume
@@ -381,16 +408,20 @@ func (t *Tail) Stop() {
 			t.Log.Errorf("Stopping tail on %q: %s", tailer.Filename, err.Error())
 		}
 	}
+	t.tailers = make(map[string]*tail.Tail)
+	t.stateMu.Unlock()
 
 	t.cancel()
 	t.wg.Wait()
 
 	// persist offsets
+	t.stateMu.Lock()
 	offsetsMutex.Lock()
 	for k, v := range t.offsets {
 		offsets[k] = v
 	}
 	offsetsMutex.Unlock()
+	t.stateMu.Unlock()
 }
 
 func (t *Tail) SetParserFunc(fn telegraf.ParserFunc) {
@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -48,6 +49,7 @@ type WinEventLog struct {
 	subscription     EvtHandle
 	subscriptionFlag EvtSubscribeFlag
 	bookmark         EvtHandle
+	bookmarkMu       sync.Mutex // protects bookmark
 }
 
 const bufferSize = 1 << 14
@@ -87,6 +89,9 @@ func (w *WinEventLog) Stop() {
 }
 
 func (w *WinEventLog) GetState() interface{} {
+	w.bookmarkMu.Lock()
+	defer w.bookmarkMu.Unlock()
+
 	bookmarkXML, err := w.renderBookmark(w.bookmark)
 	if err != nil {
 		w.Log.Errorf("State-persistence failed, cannot render bookmark: %w", err)
@@ -110,7 +115,9 @@ func (w *WinEventLog) SetState(state interface{}) error {
 	if err != nil {
 		return fmt.Errorf("creating bookmark failed: %w", err)
 	}
+	w.bookmarkMu.Lock()
 	w.bookmark = bookmark
+	w.bookmarkMu.Unlock()
 	w.subscriptionFlag = EvtSubscribeStartAfterBookmark
 
 	return nil
@@ -315,7 +322,9 @@ func (w *WinEventLog) evtSubscribe() (EvtHandle, error) {
 
 	var bookmark EvtHandle
 	if w.subscriptionFlag == EvtSubscribeStartAfterBookmark {
+		w.bookmarkMu.Lock()
 		bookmark = w.bookmark
+		w.bookmarkMu.Unlock()
 	}
 	subsHandle, err := _EvtSubscribe(0, uintptr(sigEvent), logNamePtr, xqueryPtr, bookmark, 0, 0, w.subscriptionFlag)
 	if err != nil {
@@ -360,7 +369,10 @@ func (w *WinEventLog) fetchEvents(subsHandle EvtHandle) ([]Event, error) {
 		if event, err := w.renderEvent(eventHandle); err == nil {
 			events = append(events, event)
 		}
-		if err := _EvtUpdateBookmark(w.bookmark, eventHandle); err != nil && evterr == nil {
+		w.bookmarkMu.Lock()
+		err = _EvtUpdateBookmark(w.bookmark, eventHandle)
+		w.bookmarkMu.Unlock()
+		if err != nil && evterr == nil {
 			evterr = err
 		}
 
@@ -226,8 +226,12 @@ func (a *Elasticsearch) Connect() error {
 		}
 	}
 
-	a.IndexName, a.tagKeys = a.GetTagKeys(a.IndexName)
-	a.pipelineName, a.pipelineTagKeys = a.GetTagKeys(a.UsePipeline)
+	// The index name is rewritten, so only do this on the first connect and
+	// not when reconnecting
+	if a.tagKeys == nil {
+		a.IndexName, a.tagKeys = a.GetTagKeys(a.IndexName)
+		a.pipelineName, a.pipelineTagKeys = a.GetTagKeys(a.UsePipeline)
+	}
 
 	return nil
 }
@@ -309,6 +313,10 @@ func (a *Elasticsearch) Write(metrics []telegraf.Metric) error {
 
 	res, err := bulkRequest.Do(ctx)
 
+	if elastic.IsStatusCode(err, http.StatusUnauthorized) || elastic.IsForbidden(err) {
+		// Allow reconnecting with rotated credentials
+		return &telegraf.AuthenticationError{Err: fmt.Errorf("error sending bulk request to Elasticsearch: %w", err)}
+	}
 	if err != nil {
 		return fmt.Errorf("error sending bulk request to Elasticsearch: %w", err)
 	}
@@ -4,6 +4,7 @@ package execd
 import (
 	"bufio"
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/influxdata/telegraf/internal/process"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"github.com/influxdata/telegraf/plugins/serializers"
+	"github.com/influxdata/telegraf/plugins/serializers/influx"
 )
 
 //go:embed sample.conf
@@ -24,10 +26,13 @@ type Execd struct {
 	Environment              []string        `toml:"environment"`
 	RestartDelay             config.Duration `toml:"restart_delay"`
 	IgnoreSerializationError bool            `toml:"ignore_serialization_error"`
+	Acknowledge              bool            `toml:"acknowledge"`
+	AckTimeout               config.Duration `toml:"ack_timeout"`
 	Log                      telegraf.Logger
 
 	process    *process.Process
 	serializer serializers.Serializer
+	tracker    *process.AckTracker
 }
 
 func (*Execd) SampleConfig() string {
@@ -42,6 +47,14 @@ func (e *Execd) Init() error {
 	if len(e.Command) == 0 {
 		return fmt.Errorf("no command specified")
 	}
+	if e.Acknowledge {
+		if _, ok := e.serializer.(*influx.Serializer); !ok {
+			return errors.New("acknowledge requires the influx data format")
+		}
+		if e.AckTimeout <= 0 {
+			return errors.New("ack_timeout must be positive")
+		}
+	}
 
 	var err error
 
@@ -53,6 +66,10 @@ func (e *Execd) Init() error {
 	e.process.RestartDelay = time.Duration(e.RestartDelay)
 	e.process.ReadStdoutFn = e.cmdReadOut
 	e.process.ReadStderrFn = e.cmdReadErr
+	if e.Acknowledge {
+		e.tracker = process.NewAckTracker()
+		e.process.ReadStdoutFn = e.cmdReadOutAck
+	}
 
 	return nil
 }
@@ -78,6 +95,10 @@ func (e *Execd) Close() error {
 }
 
 func (e *Execd) Write(metrics []telegraf.Metric) error {
+	if e.Acknowledge {
+		return e.writeAck(metrics)
+	}
+
 	for _, m := range metrics {
 		b, err := e.serializer.Serialize(m)
 		if err != nil {
@@ -95,6 +116,63 @@ func (e *Execd) Write(metrics []telegraf.Metric) error {
 	return nil
 }
 
+// writeAck writes the metrics, each preceded by its ID, and waits for the
+// process to acknowledge them. The batch is kept for retrying if any of the
+// metrics is rejected or not acknowledged in time.
+func (e *Execd) writeAck(metrics []telegraf.Metric) error {
+	var buf []byte
+	entries := make([]*process.AckEntry, 0, len(metrics))
+	for _, m := range metrics {
+		b, err := e.serializer.Serialize(m)
+		if err != nil {
+			if !e.IgnoreSerializationError {
+				e.resolve(entries, err)
+				return fmt.Errorf("error serializing metrics: %w", err)
+			}
+			e.Log.Errorf("Skipping metric due to a serialization error: %v", err)
+			continue
+		}
+		entry := e.tracker.Add(m)
+		entries = append(entries, entry)
+		buf = append(buf, process.FormatAckID(entry.ID)...)
+		buf = append(buf, b...)
+	}
+
+	if _, err := e.process.Stdin.Write(buf); err != nil {
+		e.resolve(entries, err)
+		return fmt.Errorf("error writing metrics: %w", err)
+	}
+
+	timeout := time.NewTimer(time.Duration(e.AckTimeout))
+	defer timeout.Stop()
+
+	var rejected int
+	var lastErr error
+	for _, entry := range entries {
+		select {
+		case <-entry.Done():
+		case <-timeout.C:
+			e.resolve(entries, process.ErrAckTimeout)
+			return fmt.Errorf("waiting for acknowledgement of metrics: %w", process.ErrAckTimeout)
+		}
+		if err := entry.Err(); err != nil {
+			rejected++
+			lastErr = err
+		}
+	}
+	if rejected > 0 {
+		return fmt.Errorf("%d metrics rejected by the process, last error: %w", rejected, lastErr)
+	}
+	return nil
+}
+
+// resolve stops waiting for acknowledgements of the given entries.
+func (e *Execd) resolve(entries []*process.AckEntry, err error) {
+	for _, entry := range entries {
+		e.tracker.Resolve(entry.ID, err)
+	}
+}
+
 func (e *Execd) cmdReadErr(out io.Reader) {
 	scanner := bufio.NewScanner(out)
 
@@ -115,8 +193,34 @@ func (e *Execd) cmdReadOut(out io.Reader) {
 	}
 }
 
+// cmdReadOutAck processes the acknowledgements of the process and logs all
+// other output.
+func (e *Execd) cmdReadOutAck(out io.Reader) {
+	scanner := bufio.NewScanner(out)
+
+	for scanner.Scan() {
+		msg, ok := process.ParseAck(scanner.Bytes())
+		if !ok {
+			e.Log.Info(scanner.Text())
+			continue
+		}
+		switch msg.Type {
+		case process.Ack:
+			e.tracker.Resolve(msg.ID, nil)
+		case process.Nack:
+			e.Log.Errorf("Metric rejected by the process: %s", msg.Message)
+			e.tracker.Resolve(msg.ID, errors.New(msg.Message))
+		}
+	}
+
+	// The process terminated, so the pending metrics are lost
+	e.tracker.ResolveAll(errors.New("process terminated"))
+}
+
 func init() {
 	outputs.Add("execd", func() telegraf.Output {
-		return &Execd{}
+		return &Execd{
+			AckTimeout: config.Duration(30 * time.Second),
+		}
 	})
 }
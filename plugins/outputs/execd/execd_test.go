@@ -17,8 +17,10 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/shim"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
 	influxSerializer "github.com/influxdata/telegraf/plugins/serializers/influx"
+	"github.com/influxdata/telegraf/plugins/serializers/json"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -145,9 +147,49 @@ func TestPartiallyUnserializableCanBeSkipped(t *testing.T) {
 	require.NoError(t, e.Close())
 }
 
+func TestExternalOutputAck(t *testing.T) {
+	serializer := &influxSerializer.Serializer{}
+	require.NoError(t, serializer.Init())
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	e := &Execd{
+		Command:      []string{exe, "-ackoutput"},
+		Environment:  []string{"PLUGINS_OUTPUTS_EXECD_MODE=application"},
+		RestartDelay: config.Duration(5 * time.Second),
+		Acknowledge:  true,
+		AckTimeout:   config.Duration(5 * time.Second),
+		serializer:   serializer,
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, e.Init())
+	require.NoError(t, e.Connect())
+	defer e.Close()
+
+	valid := metric.New("cpu", map[string]string{}, map[string]interface{}{"idle": 50}, now)
+	invalid := metric.New("cpu", map[string]string{}, map[string]interface{}{"fail": true}, now)
+	require.NoError(t, e.Write([]telegraf.Metric{valid, valid}))
+	require.ErrorContains(t, e.Write([]telegraf.Metric{valid, invalid}), "1 metrics rejected by the process")
+}
+
+func TestAckRequiresInflux(t *testing.T) {
+	e := &Execd{
+		Command:     []string{"cat"},
+		Acknowledge: true,
+		AckTimeout:  config.Duration(5 * time.Second),
+		serializer:  &json.Serializer{},
+		Log:         testutil.Logger{},
+	}
+	require.ErrorContains(t, e.Init(), "requires the influx data format")
+}
+
 var testoutput = flag.Bool("testoutput", false,
 	"if true, act like line input program instead of test")
 
+var ackoutput = flag.Bool("ackoutput", false,
+	"if true, act like an output acknowledging metrics instead of test")
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	runMode := os.Getenv("PLUGINS_OUTPUTS_EXECD_MODE")
@@ -155,6 +197,10 @@ func TestMain(m *testing.M) {
 		runOutputConsumerProgram()
 		os.Exit(0)
 	}
+	if *ackoutput && runMode == "application" {
+		runAckOutputProgram()
+		os.Exit(0)
+	}
 	code := m.Run()
 	os.Exit(code)
 }
@@ -193,3 +239,41 @@ func runOutputConsumerProgram() {
 		}
 	}
 }
+
+func runAckOutputProgram() {
+	s := shim.New()
+	if err := s.AddOutput(&failingOutput{}); err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+	if err := s.Run(shim.PollIntervalDisabled); err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+}
+
+// failingOutput fails to write metrics with a "fail" field
+type failingOutput struct{}
+
+func (*failingOutput) SampleConfig() string {
+	return ""
+}
+
+func (*failingOutput) Connect() error {
+	return nil
+}
+
+func (*failingOutput) Close() error {
+	return nil
+}
+
+func (*failingOutput) Write(metrics []telegraf.Metric) error {
+	for _, m := range metrics {
+		if m.HasField("fail") {
+			return errors.New("failure requested")
+		}
+	}
+	return nil
+}
@@ -250,6 +250,17 @@ func TestStatusCode(t *testing.T) {
 				require.Error(t, err)
 			},
 		},
+		{
+			name: "401 status is an authentication error",
+			plugin: &HTTP{
+				URL: u.String(),
+			},
+			statusCode: http.StatusUnauthorized,
+			errFunc: func(t *testing.T, err error) {
+				var authErr *telegraf.AuthenticationError
+				require.ErrorAs(t, err, &authErr)
+			},
+		},
 		{
 			name: "Do not retry on configured non-retryable statuscode",
 			plugin: &HTTP{
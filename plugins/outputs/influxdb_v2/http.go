@@ -311,7 +311,8 @@ func (c *httpClient) writeBatch(ctx context.Context, bucket string, metrics []te
 		c.log.Errorf("Failed to write metric to %s (will be dropped: %s): %s\n", bucket, resp.Status, desc)
 		return nil
 	case http.StatusUnauthorized, http.StatusForbidden:
-		return fmt.Errorf("failed to write metric to %s (%s): %s", bucket, resp.Status, desc)
+		err := fmt.Errorf("failed to write metric to %s (%s): %s", bucket, resp.Status, desc)
+		return &telegraf.AuthenticationError{Err: err}
 	case http.StatusTooManyRequests,
 		http.StatusServiceUnavailable,
 		http.StatusBadGateway,
@@ -96,6 +96,7 @@ func (i *InfluxDB) Close() error {
 	for _, client := range i.clients {
 		client.Close()
 	}
+	i.clients = nil
 	return nil
 }
 
@@ -104,19 +105,28 @@ func (i *InfluxDB) Close() error {
 func (i *InfluxDB) Write(metrics []telegraf.Metric) error {
 	ctx := context.Background()
 
-	var err error
+	var authFailed bool
 	p := rand.Perm(len(i.clients))
 	for _, n := range p {
 		client := i.clients[n]
-		err = client.Write(ctx, metrics)
+		err := client.Write(ctx, metrics)
 		if err == nil {
 			return nil
 		}
+		var authErr *telegraf.AuthenticationError
+		if errors.As(err, &authErr) {
+			authFailed = true
+		}
 
 		i.Log.Errorf("When writing to [%s]: %v", client.URL(), err)
 	}
 
-	return fmt.Errorf("failed to send metrics to any configured server(s)")
+	err := fmt.Errorf("failed to send metrics to any configured server(s)")
+	if authFailed {
+		// Allow reconnecting with a rotated token
+		return &telegraf.AuthenticationError{Err: err}
+	}
+	return err
 }
 
 func (i *InfluxDB) getHTTPClient(address *url.URL, proxy *url.URL) (Client, error) {
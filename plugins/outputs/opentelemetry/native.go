@@ -0,0 +1,164 @@
+package opentelemetry
+
+import (
+	"math"
+	"strings"
+
+	"github.com/influxdata/influxdb-observability/common"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/influxdata/telegraf"
+)
+
+// Tags holding the instrumentation scope, see the semantic conventions
+const (
+	scopeNameTag    = "otel.library.name"
+	scopeVersionTag = "otel.library.version"
+)
+
+// nativeBatch converts histogram and summary fields holding all buckets or
+// quantiles. Those fields are not supported by the line-protocol converter.
+type nativeBatch struct {
+	metrics   pmetric.Metrics
+	resources map[string]pmetric.ResourceMetrics
+	scopes    map[string]pmetric.ScopeMetrics
+	series    map[string]pmetric.Metric
+}
+
+func newNativeBatch() *nativeBatch {
+	return &nativeBatch{
+		metrics:   pmetric.NewMetrics(),
+		resources: make(map[string]pmetric.ResourceMetrics),
+		scopes:    make(map[string]pmetric.ScopeMetrics),
+		series:    make(map[string]pmetric.Metric),
+	}
+}
+
+// add converts the given field of the metric and returns false if the field
+// does not hold a histogram or summary value.
+func (b *nativeBatch) add(metric telegraf.Metric, field *telegraf.Field) bool {
+	var histogram *telegraf.HistogramValue
+	var summary *telegraf.SummaryValue
+	switch v := field.Value.(type) {
+	case *telegraf.HistogramValue:
+		histogram = v
+	case *telegraf.SummaryValue:
+		summary = v
+	default:
+		return false
+	}
+
+	// Split the tags into resource and data-point attributes the same way
+	// the line-protocol converter does
+	var scopeName, scopeVersion string
+	var resourceKey strings.Builder
+	resource := pcommon.NewMap()
+	attributes := pcommon.NewMap()
+	for _, tag := range metric.TagList() {
+		switch {
+		case tag.Key == scopeNameTag:
+			scopeName = tag.Value
+		case tag.Key == scopeVersionTag:
+			scopeVersion = tag.Value
+		case common.ResourceNamespace.MatchString(tag.Key):
+			resource.PutStr(tag.Key, tag.Value)
+			resourceKey.WriteString(tag.Key + "\x00" + tag.Value + "\x00")
+		default:
+			attributes.PutStr(tag.Key, tag.Value)
+		}
+	}
+
+	rm, found := b.resources[resourceKey.String()]
+	if !found {
+		rm = b.metrics.ResourceMetrics().AppendEmpty()
+		resource.CopyTo(rm.Resource().Attributes())
+		b.resources[resourceKey.String()] = rm
+	}
+	scopeKey := resourceKey.String() + "\x01" + scopeName + "\x00" + scopeVersion
+	sm, found := b.scopes[scopeKey]
+	if !found {
+		sm = rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(scopeName)
+		sm.Scope().SetVersion(scopeVersion)
+		b.scopes[scopeKey] = sm
+	}
+
+	name := field.Key
+	if metric.Name() != common.MeasurementPrometheus {
+		name = metric.Name() + "_" + field.Key
+	}
+	ts := pcommon.NewTimestampFromTime(metric.Time())
+
+	switch {
+	case summary != nil:
+		m := b.lookup(sm, scopeKey, name, pmetric.MetricTypeSummary)
+		dp := m.Summary().DataPoints().AppendEmpty()
+		attributes.CopyTo(dp.Attributes())
+		dp.SetTimestamp(ts)
+		dp.SetCount(summary.Count)
+		dp.SetSum(summary.Sum)
+		for _, q := range summary.Quantiles {
+			qv := dp.QuantileValues().AppendEmpty()
+			qv.SetQuantile(q.Quantile)
+			qv.SetValue(q.Value)
+		}
+	case histogram.Exponential != nil:
+		// Prefer the exponential buckets as they provide the higher resolution
+		e := histogram.Exponential
+		m := b.lookup(sm, scopeKey, name, pmetric.MetricTypeExponentialHistogram)
+		dp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+		attributes.CopyTo(dp.Attributes())
+		dp.SetTimestamp(ts)
+		dp.SetCount(histogram.Count)
+		dp.SetSum(histogram.Sum)
+		dp.SetScale(e.Schema)
+		dp.SetZeroCount(e.ZeroCount)
+		dp.Positive().SetOffset(e.Positive.Offset)
+		dp.Positive().BucketCounts().FromRaw(e.Positive.Counts)
+		dp.Negative().SetOffset(e.Negative.Offset)
+		dp.Negative().BucketCounts().FromRaw(e.Negative.Counts)
+	default:
+		m := b.lookup(sm, scopeKey, name, pmetric.MetricTypeHistogram)
+		dp := m.Histogram().DataPoints().AppendEmpty()
+		attributes.CopyTo(dp.Attributes())
+		dp.SetTimestamp(ts)
+		dp.SetCount(histogram.Count)
+		dp.SetSum(histogram.Sum)
+
+		// OpenTelemetry uses non-cumulative counts with an implicit
+		// overflow bucket
+		var previous uint64
+		for _, bucket := range histogram.Buckets {
+			if math.IsInf(bucket.UpperBound, 1) {
+				break
+			}
+			dp.ExplicitBounds().Append(bucket.UpperBound)
+			dp.BucketCounts().Append(bucket.Count - previous)
+			previous = bucket.Count
+		}
+		dp.BucketCounts().Append(histogram.Count - previous)
+	}
+
+	return true
+}
+
+func (b *nativeBatch) lookup(sm pmetric.ScopeMetrics, scopeKey, name string, mt pmetric.MetricType) pmetric.Metric {
+	key := scopeKey + "\x01" + name + "\x00" + mt.String()
+	if m, found := b.series[key]; found {
+		return m
+	}
+
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(name)
+	switch mt {
+	case pmetric.MetricTypeSummary:
+		m.SetEmptySummary()
+	case pmetric.MetricTypeExponentialHistogram:
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeHistogram:
+		m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	}
+	b.series[key] = m
+	return m
+}
@@ -123,7 +123,20 @@ func (o *OpenTelemetry) Close() error {
 
 func (o *OpenTelemetry) Write(metrics []telegraf.Metric) error {
 	batch := o.metricsConverter.NewBatch()
+	native := newNativeBatch()
 	for _, metric := range metrics {
+		// Histogram and summary fields holding all buckets or quantiles are
+		// converted separately
+		fields := make(map[string]interface{}, len(metric.FieldList()))
+		for _, field := range metric.FieldList() {
+			if !native.add(metric, field) {
+				fields[field.Key] = field.Value
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
 		var vType common.InfluxMetricValueType
 		switch metric.Type() {
 		case telegraf.Gauge:
@@ -140,14 +153,16 @@ func (o *OpenTelemetry) Write(metrics []telegraf.Metric) error {
 			o.Log.Warnf("unrecognized metric type %Q", metric.Type())
 			continue
 		}
-		err := batch.AddPoint(metric.Name(), metric.Tags(), metric.Fields(), metric.Time(), vType)
+		err := batch.AddPoint(metric.Name(), metric.Tags(), fields, metric.Time(), vType)
 		if err != nil {
 			o.Log.Warnf("failed to add point: %s", err)
 			continue
 		}
 	}
 
-	md := pmetricotlp.NewExportRequestFromMetrics(batch.GetMetrics())
+	converted := batch.GetMetrics()
+	native.metrics.ResourceMetrics().MoveAndAppendTo(converted.ResourceMetrics())
+	md := pmetricotlp.NewExportRequestFromMetrics(converted)
 	if md.Metrics().ResourceMetrics().Len() == 0 {
 		return nil
 	}
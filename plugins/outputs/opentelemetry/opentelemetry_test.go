@@ -2,6 +2,7 @@ package opentelemetry
 
 import (
 	"context"
+	"math"
 	"net"
 	"testing"
 	"time"
@@ -79,6 +80,133 @@ func TestOpenTelemetry(t *testing.T) {
 	require.JSONEq(t, string(expectJSON), string(gotJSON))
 }
 
+func TestOpenTelemetryNativeValues(t *testing.T) {
+	expect := pmetric.NewMetrics()
+	{
+		rm := expect.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("host.name", "potato")
+		ilm := rm.ScopeMetrics().AppendEmpty()
+		ilm.Scope().SetName("My Library Name")
+
+		m := ilm.Metrics().AppendEmpty()
+		m.SetName("request_duration_seconds")
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		edp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+		edp.Attributes().PutStr("handler", "api")
+		edp.SetTimestamp(pcommon.Timestamp(1622848686000000000))
+		edp.SetCount(7)
+		edp.SetSum(12.5)
+		edp.SetScale(0)
+		edp.SetZeroCount(1)
+		edp.Positive().SetOffset(-1)
+		edp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 0, 1})
+		edp.Negative().BucketCounts().FromRaw([]uint64{2})
+
+		m = ilm.Metrics().AppendEmpty()
+		m.SetName("response_size_bytes")
+		m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		hdp := m.Histogram().DataPoints().AppendEmpty()
+		hdp.Attributes().PutStr("handler", "api")
+		hdp.SetTimestamp(pcommon.Timestamp(1622848686000000000))
+		hdp.SetCount(4)
+		hdp.SetSum(2500)
+		hdp.ExplicitBounds().FromRaw([]float64{100, 1000})
+		hdp.BucketCounts().FromRaw([]uint64{1, 2, 1})
+
+		m = ilm.Metrics().AppendEmpty()
+		m.SetName("rpc_duration_seconds")
+		m.SetEmptySummary()
+		sdp := m.Summary().DataPoints().AppendEmpty()
+		sdp.SetTimestamp(pcommon.Timestamp(1622848686000000000))
+		sdp.SetCount(3)
+		sdp.SetSum(1.5)
+		qv := sdp.QuantileValues().AppendEmpty()
+		qv.SetQuantile(0.5)
+		qv.SetValue(0.4)
+	}
+	m := newMockOtelService(t)
+	t.Cleanup(m.Cleanup)
+
+	metricsConverter, err := influx2otel.NewLineProtocolToOtelMetrics(common.NoopLogger{})
+	require.NoError(t, err)
+	plugin := &OpenTelemetry{
+		ServiceAddress:       m.Address(),
+		Timeout:              config.Duration(time.Second),
+		Headers:              map[string]string{"test": "header1"},
+		metricsConverter:     metricsConverter,
+		grpcClientConn:       m.GrpcClient(),
+		metricsServiceClient: pmetricotlp.NewGRPCClient(m.GrpcClient()),
+	}
+
+	tags := map[string]string{
+		"handler":           "api",
+		"otel.library.name": "My Library Name",
+		"host.name":         "potato",
+	}
+	input := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus",
+			tags,
+			map[string]interface{}{
+				"request_duration_seconds": &telegraf.HistogramValue{
+					Count:   7,
+					Sum:     12.5,
+					Buckets: []telegraf.Bucket{{UpperBound: 1, Count: 3}},
+					Exponential: &telegraf.ExponentialBuckets{
+						ZeroThreshold: 1e-128,
+						ZeroCount:     1,
+						Positive:      telegraf.BucketRange{Offset: -1, Counts: []uint64{1, 2, 0, 1}},
+						Negative:      telegraf.BucketRange{Offset: 0, Counts: []uint64{2}},
+					},
+				},
+			},
+			time.Unix(0, 1622848686000000000),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"response",
+			tags,
+			map[string]interface{}{
+				"size_bytes": &telegraf.HistogramValue{
+					Count: 4,
+					Sum:   2500,
+					Buckets: []telegraf.Bucket{
+						{UpperBound: 100, Count: 1},
+						{UpperBound: 1000, Count: 3},
+						{UpperBound: math.Inf(1), Count: 4},
+					},
+				},
+			},
+			time.Unix(0, 1622848686000000000),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"rpc",
+			map[string]string{
+				"otel.library.name": "My Library Name",
+				"host.name":         "potato",
+			},
+			map[string]interface{}{
+				"duration_seconds": &telegraf.SummaryValue{
+					Count:     3,
+					Sum:       1.5,
+					Quantiles: []telegraf.Quantile{{Quantile: 0.5, Value: 0.4}},
+				},
+			},
+			time.Unix(0, 1622848686000000000),
+			telegraf.Summary,
+		),
+	}
+	require.NoError(t, plugin.Write(input))
+
+	marshaller := pmetric.JSONMarshaler{}
+	expectJSON, err := marshaller.MarshalMetrics(expect)
+	require.NoError(t, err)
+	gotJSON, err := marshaller.MarshalMetrics(m.GotMetrics())
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectJSON), string(gotJSON))
+}
+
 var _ pmetricotlp.GRPCServer = (*mockOtelService)(nil)
 
 type mockOtelService struct {
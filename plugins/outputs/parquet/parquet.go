@@ -0,0 +1,282 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package parquet
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/v13/parquet"
+	"github.com/apache/arrow/go/v13/parquet/pqarrow"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	serializer "github.com/influxdata/telegraf/plugins/serializers/parquet"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+// Characters not allowed in file names
+var invalidFilenameChars = regexp.MustCompile(`[^\w.-]`)
+
+type Parquet struct {
+	Directory        string          `toml:"directory"`
+	Compression      string          `toml:"compression"`
+	RotationInterval config.Duration `toml:"rotation_interval"`
+	RotationMaxSize  config.Size     `toml:"rotation_max_size"`
+	Log              telegraf.Logger `toml:"-"`
+
+	props *parquet.WriterProperties
+	files map[string]*file
+	sync.Mutex
+}
+
+// file is a Parquet file of a measurement being written
+type file struct {
+	filename string
+	schema   *serializer.Schema
+	writer   *pqarrow.FileWriter
+	out      *countingFile
+	timer    *time.Timer
+}
+
+// countingFile keeps track of the bytes written to the file
+type countingFile struct {
+	*os.File
+	written int64
+}
+
+func (f *countingFile) Write(b []byte) (int, error) {
+	n, err := f.File.Write(b)
+	f.written += int64(n)
+	return n, err
+}
+
+func (*Parquet) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *Parquet) Init() error {
+	if p.Directory == "" {
+		p.Directory = "."
+	}
+
+	props, err := serializer.WriterProperties(p.Compression)
+	if err != nil {
+		return err
+	}
+	p.props = props
+
+	return nil
+}
+
+func (p *Parquet) Connect() error {
+	if err := os.MkdirAll(p.Directory, 0750); err != nil {
+		return fmt.Errorf("creating directory failed: %w", err)
+	}
+
+	// Files still hidden were not finished, e.g. due to a crash, and cannot be
+	// read as they are lacking the metadata written on completion.
+	leftovers, err := filepath.Glob(filepath.Join(p.Directory, ".*.parquet"))
+	if err != nil {
+		return fmt.Errorf("searching unfinished files failed: %w", err)
+	}
+	for _, fn := range leftovers {
+		p.Log.Warnf("Found unfinished file %q of a previous run, its data cannot be recovered", fn)
+	}
+
+	p.files = make(map[string]*file)
+	return nil
+}
+
+func (p *Parquet) Close() error {
+	p.Lock()
+	defer p.Unlock()
+
+	var errs []error
+	for name, f := range p.files {
+		if err := p.finish(f); err != nil {
+			errs = append(errs, err)
+		}
+		delete(p.files, name)
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Parquet) Write(metrics []telegraf.Metric) error {
+	p.Lock()
+	defer p.Unlock()
+
+	// Group the metrics by measurement keeping their order
+	var names []string
+	batches := make(map[string][]telegraf.Metric)
+	for _, m := range metrics {
+		if _, found := batches[m.Name()]; !found {
+			names = append(names, m.Name())
+		}
+		batches[m.Name()] = append(batches[m.Name()], m)
+	}
+
+	for _, name := range names {
+		if err := p.write(name, batches[name]); err != nil {
+			return fmt.Errorf("writing %q failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// write writes the metrics of a measurement to the current file of the
+// measurement. A new file is started if the schema of the file does not cover
+// a metric or if the file exceeds the maximum size.
+func (p *Parquet) write(name string, metrics []telegraf.Metric) error {
+	f := p.files[name]
+
+	var pending []telegraf.Metric
+	for i, m := range metrics {
+		if f != nil && f.schema.Covers(m) {
+			pending = append(pending, m)
+			continue
+		}
+
+		var schema *serializer.Schema
+		if f == nil {
+			schema = serializer.NewSchema(metrics[i:])
+		} else {
+			p.Log.Debugf("Schema of %q changed, starting a new file", name)
+			delete(p.files, name)
+			if err := p.flush(f, pending); err != nil {
+				return err
+			}
+			if err := p.finish(f); err != nil {
+				return err
+			}
+			schema = f.schema.Extend(metrics[i : i+1])
+		}
+
+		var err error
+		f, err = p.create(name, schema)
+		if err != nil {
+			return err
+		}
+		p.files[name] = f
+		pending = []telegraf.Metric{m}
+	}
+
+	if err := p.flush(f, pending); err != nil {
+		delete(p.files, name)
+		return err
+	}
+
+	if p.RotationMaxSize > 0 && f.size() >= int64(p.RotationMaxSize) {
+		delete(p.files, name)
+		return p.finish(f)
+	}
+	return nil
+}
+
+// create starts a new file for the measurement. The file is hidden until it is
+// finished, as readers can only access complete files.
+func (p *Parquet) create(name string, schema *serializer.Schema) (*file, error) {
+	now := time.Now().UTC()
+	filename := fmt.Sprintf("%s-%s.parquet", invalidFilenameChars.ReplaceAllString(name, "_"), now.Format("20060102T150405.000000000Z"))
+	filename = filepath.Join(p.Directory, filename)
+
+	out, err := os.OpenFile(hiddenFilename(filename), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("creating file failed: %w", err)
+	}
+	cf := &countingFile{File: out}
+
+	writer, err := pqarrow.NewFileWriter(schema.Arrow(), cf, p.props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		_ = out.Close()
+		_ = os.Remove(out.Name())
+		return nil, fmt.Errorf("creating writer failed: %w", err)
+	}
+
+	f := &file{
+		filename: filename,
+		schema:   schema,
+		writer:   writer,
+		out:      cf,
+	}
+	if p.RotationInterval > 0 {
+		f.timer = time.AfterFunc(time.Duration(p.RotationInterval), func() { p.rotate(name, f) })
+	}
+	return f, nil
+}
+
+// rotate completes the file of the measurement once the rotation interval
+// elapsed, independent of metrics being written.
+func (p *Parquet) rotate(name string, f *file) {
+	p.Lock()
+	defer p.Unlock()
+
+	// The file might have been finished in the meantime
+	if p.files[name] != f {
+		return
+	}
+	delete(p.files, name)
+	if err := p.finish(f); err != nil {
+		p.Log.Error(err)
+	}
+}
+
+// finish completes the file and makes it visible.
+func (p *Parquet) finish(f *file) error {
+	if f.timer != nil {
+		f.timer.Stop()
+	}
+	if err := f.writer.Close(); err != nil {
+		return fmt.Errorf("finishing file %q failed: %w", f.filename, err)
+	}
+	if err := os.Rename(hiddenFilename(f.filename), f.filename); err != nil {
+		return fmt.Errorf("renaming file %q failed: %w", f.filename, err)
+	}
+	p.Log.Debugf("Finished file %q", f.filename)
+	return nil
+}
+
+// flush writes the metrics to the file. In case of an error the file is
+// finished to keep the data written so far and the metrics go to a new file
+// on retry.
+func (p *Parquet) flush(f *file, metrics []telegraf.Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	record := f.schema.Record(metrics)
+	defer record.Release()
+
+	if err := f.writer.Write(record); err != nil {
+		if errFinish := p.finish(f); errFinish != nil {
+			p.Log.Error(errFinish)
+		}
+		return fmt.Errorf("writing to file %q failed: %w", f.filename, err)
+	}
+	return nil
+}
+
+// size returns the estimated size of the file including the data not yet
+// flushed to disk.
+func (f *file) size() int64 {
+	return f.out.written + f.writer.RowGroupTotalCompressedBytes()
+}
+
+func hiddenFilename(filename string) string {
+	return filepath.Join(filepath.Dir(filename), "."+filepath.Base(filename))
+}
+
+func init() {
+	outputs.Add("parquet", func() telegraf.Output {
+		return &Parquet{
+			RotationInterval: config.Duration(time.Hour),
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package parquet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/parquet"
+	"github.com/apache/arrow/go/v13/parquet/pqarrow"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestWriteByMeasurement(t *testing.T) {
+	dir := t.TempDir()
+	plugin := &Parquet{
+		Directory: dir,
+		Log:       testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"host": "a"}, map[string]interface{}{"usage": 1.0}, time.Unix(0, 0)),
+		testutil.MustMetric("mem", map[string]string{"host": "a"}, map[string]interface{}{"used": int64(2)}, time.Unix(0, 0)),
+		testutil.MustMetric("cpu", map[string]string{"host": "b"}, map[string]interface{}{"usage": 3.0}, time.Unix(0, 0)),
+	}
+	require.NoError(t, plugin.Write(metrics))
+	require.NoError(t, plugin.Write(metrics[:1]))
+
+	// Files are hidden until complete
+	require.Empty(t, completeFiles(t, dir))
+	require.NoError(t, plugin.Close())
+
+	files := completeFiles(t, dir)
+	require.Len(t, files, 2)
+	require.Equal(t, map[string]int64{"cpu": 3, "mem": 1}, countRows(t, files))
+}
+
+func TestWriteSchemaChange(t *testing.T) {
+	dir := t.TempDir()
+	plugin := &Parquet{
+		Directory: dir,
+		Log:       testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"usage": 1.0}, time.Unix(0, 0)),
+		testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"usage": 2.0}, time.Unix(0, 0)),
+	}
+	require.NoError(t, plugin.Write(metrics))
+
+	// A new tag completes the current file
+	metrics = []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"host": "a"}, map[string]interface{}{"usage": 3.0}, time.Unix(0, 0)),
+	}
+	require.NoError(t, plugin.Write(metrics))
+	require.Len(t, completeFiles(t, dir), 1)
+
+	// A field type change completes the current file
+	metrics = []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"host": "a"}, map[string]interface{}{"usage": "high"}, time.Unix(0, 0)),
+	}
+	require.NoError(t, plugin.Write(metrics))
+	require.Len(t, completeFiles(t, dir), 2)
+	require.NoError(t, plugin.Close())
+
+	files := completeFiles(t, dir)
+	require.Len(t, files, 3)
+
+	var rows []int64
+	for _, fn := range files {
+		table := readTable(t, fn)
+		rows = append(rows, table.NumRows())
+		table.Release()
+	}
+	require.ElementsMatch(t, []int64{2, 1, 1}, rows)
+}
+
+func TestWriteNumericTypeChange(t *testing.T) {
+	dir := t.TempDir()
+	plugin := &Parquet{
+		Directory: dir,
+		Log:       testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+
+	// Alternating numeric types widen the column once instead of starting a
+	// new file for every change
+	for i := 0; i < 5; i++ {
+		metrics := []telegraf.Metric{
+			testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"usage": int64(i)}, time.Unix(0, 0)),
+		}
+		require.NoError(t, plugin.Write(metrics))
+		metrics = []telegraf.Metric{
+			testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"usage": float64(i) + 0.5}, time.Unix(0, 0)),
+		}
+		require.NoError(t, plugin.Write(metrics))
+	}
+	require.Len(t, completeFiles(t, dir), 1)
+	require.NoError(t, plugin.Close())
+
+	files := completeFiles(t, dir)
+	require.Len(t, files, 2)
+
+	var rows []int64
+	for _, fn := range files {
+		table := readTable(t, fn)
+		rows = append(rows, table.NumRows())
+		table.Release()
+	}
+	require.ElementsMatch(t, []int64{1, 9}, rows)
+}
+
+func TestWriteRotation(t *testing.T) {
+	dir := t.TempDir()
+	plugin := &Parquet{
+		Directory:       dir,
+		RotationMaxSize: config.Size(1),
+		Log:             testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"usage": 1.0}, time.Unix(0, 0))
+	require.NoError(t, plugin.Write([]telegraf.Metric{m}))
+	require.NoError(t, plugin.Write([]telegraf.Metric{m}))
+	require.Len(t, completeFiles(t, dir), 2)
+
+	require.NoError(t, plugin.Close())
+}
+
+func TestWriteRotationInterval(t *testing.T) {
+	dir := t.TempDir()
+	plugin := &Parquet{
+		Directory:        dir,
+		RotationInterval: config.Duration(10 * time.Millisecond),
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	// Files are rotated without further writes
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"usage": 1.0}, time.Unix(0, 0))
+	require.NoError(t, plugin.Write([]telegraf.Metric{m}))
+	require.Eventually(t, func() bool {
+		return len(completeFiles(t, dir)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, plugin.Write([]telegraf.Metric{m}))
+	require.Eventually(t, func() bool {
+		return len(completeFiles(t, dir)) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestConnectUnfinishedFiles(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, ".cpu-20230601T120000.000000000Z.parquet")
+	require.NoError(t, os.WriteFile(fn, []byte("PAR1"), 0640))
+
+	logger := &testutil.CaptureLogger{}
+	plugin := &Parquet{
+		Directory: dir,
+		Log:       logger,
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	require.NoError(t, plugin.Close())
+
+	warnings := logger.Warnings()
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], fn)
+}
+
+func completeFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "[^.]*.parquet"))
+	require.NoError(t, err)
+	return files
+}
+
+func countRows(t *testing.T, files []string) map[string]int64 {
+	rows := make(map[string]int64)
+	for _, fn := range files {
+		table := readTable(t, fn)
+		name := table.Column(0).Data().Chunk(0).ValueStr(0)
+		rows[name] += table.NumRows()
+		table.Release()
+	}
+	return rows
+}
+
+func readTable(t *testing.T, fn string) arrow.Table {
+	f, err := os.Open(fn)
+	require.NoError(t, err)
+	defer f.Close()
+
+	table, err := pqarrow.ReadTable(
+		context.Background(),
+		f,
+		parquet.NewReaderProperties(memory.DefaultAllocator),
+		pqarrow.ArrowReadProperties{},
+		memory.DefaultAllocator,
+	)
+	require.NoError(t, err)
+	return table
+}
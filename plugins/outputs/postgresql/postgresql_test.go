@@ -17,6 +17,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/plugins/outputs/postgresql/utils"
 	"github.com/influxdata/telegraf/testutil"
 )
@@ -249,6 +250,34 @@ func newPostgresqlTest(tb testing.TB) *PostgresqlTest {
 	return pt
 }
 
+func TestConfigLogLevel(t *testing.T) {
+	// The plugin's log_level sets the driver's level and not the general
+	// plugin option of the same name
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[outputs.postgresql]]
+  log_level = "trace"
+`)))
+	require.Len(t, c.Outputs, 1)
+	require.Empty(t, c.Outputs[0].Config.LogLevel)
+	require.Equal(t, "trace", c.Outputs[0].Output.(*Postgresql).LogLevel)
+}
+
+func TestConfigRetryMaxBackoff(t *testing.T) {
+	// The plugin's retry_max_backoff applies to connection retries and does
+	// not limit the general write retry options
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(`
+[[outputs.postgresql]]
+  retry_max_backoff = "15s"
+  retry_initial_backoff = "30s"
+`)))
+	require.Len(t, c.Outputs, 1)
+	require.Zero(t, c.Outputs[0].Config.RetryMaxBackoff)
+	require.Equal(t, 30*time.Second, c.Outputs[0].Config.RetryInitialBackoff)
+	require.Equal(t, config.Duration(15*time.Second), c.Outputs[0].Output.(*Postgresql).RetryMaxBackoff)
+}
+
 func TestPostgresqlConnectIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
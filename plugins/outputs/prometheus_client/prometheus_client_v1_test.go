@@ -268,6 +268,113 @@ rpc_duration_seconds{quantile="0.9"} 9001
 rpc_duration_seconds{quantile="0.99"} 76656
 rpc_duration_seconds_sum 1.7560473e+07
 rpc_duration_seconds_count 2693
+`),
+		},
+		{
+			name: "histogram value",
+			output: &PrometheusClient{
+				Listen:            ":0",
+				MetricVersion:     1,
+				CollectorsExclude: []string{"gocollector", "process"},
+				Path:              "/metrics",
+				Log:               logger,
+			},
+			metrics: []telegraf.Metric{
+				testutil.MustMetric(
+					"http_request_duration_seconds",
+					map[string]string{},
+					map[string]interface{}{
+						"value": &telegraf.HistogramValue{
+							Count:   3,
+							Sum:     1.5,
+							Buckets: []telegraf.Bucket{{UpperBound: 0.5, Count: 2}, {UpperBound: 1, Count: 3}},
+						},
+					},
+					time.Unix(0, 0),
+					telegraf.Histogram,
+				),
+			},
+			expected: []byte(`
+# HELP http_request_duration_seconds Telegraf collected metric
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{le="0.5"} 2
+http_request_duration_seconds_bucket{le="1"} 3
+http_request_duration_seconds_bucket{le="+Inf"} 3
+http_request_duration_seconds_sum 1.5
+http_request_duration_seconds_count 3
+`),
+		},
+		{
+			name: "exponential histogram value",
+			output: &PrometheusClient{
+				Listen:            ":0",
+				MetricVersion:     1,
+				CollectorsExclude: []string{"gocollector", "process"},
+				Path:              "/metrics",
+				Log:               logger,
+			},
+			metrics: []telegraf.Metric{
+				testutil.MustMetric(
+					"latency",
+					map[string]string{},
+					map[string]interface{}{
+						"seconds": &telegraf.HistogramValue{
+							Count: 6,
+							Sum:   5,
+							Exponential: &telegraf.ExponentialBuckets{
+								Schema:    0,
+								ZeroCount: 1,
+								Positive:  telegraf.BucketRange{Offset: 0, Counts: []uint64{2, 1}},
+								Negative:  telegraf.BucketRange{Offset: 1, Counts: []uint64{2}},
+							},
+						},
+					},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []byte(`
+# HELP latency_seconds Telegraf collected metric
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="-2"} 2
+latency_seconds_bucket{le="0"} 3
+latency_seconds_bucket{le="2"} 5
+latency_seconds_bucket{le="4"} 6
+latency_seconds_bucket{le="+Inf"} 6
+latency_seconds_sum 5
+latency_seconds_count 6
+`),
+		},
+		{
+			name: "summary value",
+			output: &PrometheusClient{
+				Listen:            ":0",
+				MetricVersion:     1,
+				CollectorsExclude: []string{"gocollector", "process"},
+				Path:              "/metrics",
+				Log:               logger,
+			},
+			metrics: []telegraf.Metric{
+				testutil.MustMetric(
+					"rpc_duration_seconds",
+					map[string]string{},
+					map[string]interface{}{
+						"value": &telegraf.SummaryValue{
+							Count:     10,
+							Sum:       20,
+							Quantiles: []telegraf.Quantile{{Quantile: 0.5, Value: 1.5}, {Quantile: 0.9, Value: 4}},
+						},
+					},
+					time.Unix(0, 0),
+					telegraf.Summary,
+				),
+			},
+			expected: []byte(`
+# HELP rpc_duration_seconds Telegraf collected metric
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 1.5
+rpc_duration_seconds{quantile="0.9"} 4
+rpc_duration_seconds_sum 20
+rpc_duration_seconds_count 10
 `),
 		},
 	}
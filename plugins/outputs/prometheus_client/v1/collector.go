@@ -2,6 +2,7 @@ package v1
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strconv"
@@ -172,13 +173,13 @@ func addSample(fam *MetricFamily, sample *Sample, sampleID SampleID) {
 	fam.Samples[sampleID] = sample
 }
 
-func (c *Collector) addMetricFamily(point telegraf.Metric, sample *Sample, mname string, sampleID SampleID) {
+func (c *Collector) addMetricFamily(valueType telegraf.ValueType, sample *Sample, mname string, sampleID SampleID) {
 	var fam *MetricFamily
 	var ok bool
 	if fam, ok = c.fam[mname]; !ok {
 		fam = &MetricFamily{
 			Samples:           make(map[SampleID]*Sample),
-			TelegrafValueType: point.Type(),
+			TelegrafValueType: valueType,
 			LabelSet:          make(map[string]int),
 		}
 		c.fam[mname] = fam
@@ -237,11 +238,33 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 			}
 		}
 
+		// Histogram and summary values are complete, so they are exported
+		// independent of the metric's type using the naming of other fields.
+		for _, field := range point.FieldList() {
+			sample, valueType, ok := nativeSample(field.Value)
+			if !ok {
+				continue
+			}
+			sample.Labels = labels
+			sample.Timestamp = point.Time()
+			sample.Expiration = now.Add(c.ExpirationInterval)
+
+			mname := sanitize(point.Name())
+			if field.Key != "value" {
+				mname = sanitize(fmt.Sprintf("%s_%s", point.Name(), field.Key))
+			}
+			if !isValidTagName(mname) {
+				continue
+			}
+			c.addMetricFamily(valueType, sample, mname, sampleID)
+		}
+
 		switch point.Type() {
 		case telegraf.Summary:
 			var mname string
 			var sum float64
 			var count uint64
+			var found bool
 			summaryvalue := make(map[float64]float64)
 			for fn, fv := range point.Fields() {
 				var value float64
@@ -255,6 +278,7 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 				default:
 					continue
 				}
+				found = true
 
 				switch fn {
 				case "sum":
@@ -268,6 +292,10 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 					}
 				}
 			}
+			// Metrics only holding summary values were exported above
+			if !found {
+				continue
+			}
 			sample := &Sample{
 				Labels:       labels,
 				SummaryValue: summaryvalue,
@@ -282,12 +310,13 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 				continue
 			}
 
-			c.addMetricFamily(point, sample, mname, sampleID)
+			c.addMetricFamily(point.Type(), sample, mname, sampleID)
 
 		case telegraf.Histogram:
 			var mname string
 			var sum float64
 			var count uint64
+			var found bool
 			histogramvalue := make(map[float64]uint64)
 			for fn, fv := range point.Fields() {
 				var value float64
@@ -301,6 +330,7 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 				default:
 					continue
 				}
+				found = true
 
 				switch fn {
 				case "sum":
@@ -314,6 +344,10 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 					}
 				}
 			}
+			// Metrics only holding histogram values were exported above
+			if !found {
+				continue
+			}
 			sample := &Sample{
 				Labels:         labels,
 				HistogramValue: histogramvalue,
@@ -328,7 +362,7 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 				continue
 			}
 
-			c.addMetricFamily(point, sample, mname, sampleID)
+			c.addMetricFamily(point.Type(), sample, mname, sampleID)
 
 		default:
 			for fn, fv := range point.Fields() {
@@ -375,13 +409,62 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 				if !isValidTagName(mname) {
 					continue
 				}
-				c.addMetricFamily(point, sample, mname, sampleID)
+				c.addMetricFamily(point.Type(), sample, mname, sampleID)
 			}
 		}
 	}
 	return nil
 }
 
+// nativeSample converts a histogram or summary value into a sample without
+// labels and timestamps. Exponential buckets are converted into explicit
+// buckets as native histograms are not supported by this metric version.
+func nativeSample(value interface{}) (*Sample, telegraf.ValueType, bool) {
+	switch v := value.(type) {
+	case *telegraf.HistogramValue:
+		buckets := make(map[float64]uint64, len(v.Buckets))
+		for _, b := range v.Buckets {
+			buckets[b.UpperBound] = b.Count
+		}
+		if len(v.Buckets) == 0 && v.Exponential != nil {
+			buckets = explicitBuckets(v.Exponential)
+		}
+		return &Sample{HistogramValue: buckets, Count: v.Count, Sum: v.Sum}, telegraf.Histogram, true
+	case *telegraf.SummaryValue:
+		quantiles := make(map[float64]float64, len(v.Quantiles))
+		for _, q := range v.Quantiles {
+			quantiles[q.Quantile] = q.Value
+		}
+		return &Sample{SummaryValue: quantiles, Count: v.Count, Sum: v.Sum}, telegraf.Summary, true
+	}
+	return nil, 0, false
+}
+
+// explicitBuckets returns the cumulative counts of the exponential buckets
+// keyed by their upper bound. The zero bucket is bounded by the zero
+// threshold.
+func explicitBuckets(e *telegraf.ExponentialBuckets) map[float64]uint64 {
+	bound := func(index int) float64 {
+		return math.Exp2(float64(index) * math.Exp2(-float64(e.Schema)))
+	}
+
+	buckets := make(map[float64]uint64, len(e.Negative.Counts)+len(e.Positive.Counts)+1)
+	var cumulative uint64
+
+	// Negative buckets with a higher index cover smaller values
+	for i := len(e.Negative.Counts) - 1; i >= 0; i-- {
+		cumulative += e.Negative.Counts[i]
+		buckets[-bound(int(e.Negative.Offset)+i)] = cumulative
+	}
+	cumulative += e.ZeroCount
+	buckets[e.ZeroThreshold] = cumulative
+	for i, c := range e.Positive.Counts {
+		cumulative += c
+		buckets[bound(int(e.Positive.Offset)+i+1)] = cumulative
+	}
+	return buckets
+}
+
 func (c *Collector) Expire(now time.Time) {
 	c.Lock()
 	defer c.Unlock()
@@ -0,0 +1,143 @@
+package arrow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// Converter creates metrics from the rows of Arrow records. Besides the
+// measurement, tag and timestamp columns all columns become fields. It is
+// shared with the parsers of other formats read as Arrow records, e.g.
+// Parquet.
+type Converter struct {
+	MetricName        string
+	MeasurementColumn string
+	TagColumns        []string
+	TimestampColumn   string
+	TimestampFormat   string
+	DefaultTags       map[string]string
+
+	tagColumns map[string]bool
+}
+
+func (c *Converter) Init() error {
+	c.tagColumns = make(map[string]bool, len(c.TagColumns))
+	for _, name := range c.TagColumns {
+		if name == c.MeasurementColumn || name == c.TimestampColumn {
+			return fmt.Errorf("column %q cannot be used as tag", name)
+		}
+		c.tagColumns[name] = true
+	}
+	return nil
+}
+
+// Convert returns one metric per row of the record. Null values are skipped
+// as are rows without any field.
+func (c *Converter) Convert(record arrow.Record) ([]telegraf.Metric, error) {
+	now := time.Now()
+	schema := record.Schema()
+
+	metrics := make([]telegraf.Metric, 0, record.NumRows())
+	for row := 0; row < int(record.NumRows()); row++ {
+		name := c.MetricName
+		t := now
+		tags := make(map[string]string, len(c.DefaultTags)+len(c.tagColumns))
+		for k, v := range c.DefaultTags {
+			tags[k] = v
+		}
+		fields := make(map[string]interface{}, int(record.NumCols()))
+
+		for i, column := range record.Columns() {
+			if column.IsNull(row) {
+				continue
+			}
+			key := schema.Field(i).Name
+			switch {
+			case key == c.MeasurementColumn:
+				if v := column.ValueStr(row); v != "" {
+					name = v
+				}
+			case key == c.TimestampColumn:
+				ts, err := c.timestamp(column, row)
+				if err != nil {
+					return nil, fmt.Errorf("parsing timestamp in row %d failed: %w", row, err)
+				}
+				t = ts
+			case c.tagColumns[key]:
+				tags[key] = column.ValueStr(row)
+			default:
+				fields[key] = value(column, row)
+			}
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+		metrics = append(metrics, metric.New(name, tags, fields, t))
+	}
+	return metrics, nil
+}
+
+func (c *Converter) timestamp(column arrow.Array, row int) (time.Time, error) {
+	switch col := column.(type) {
+	case *array.Timestamp:
+		unit := col.DataType().(*arrow.TimestampType).Unit
+		return col.Value(row).ToTime(unit), nil
+	case *array.Date32:
+		return col.Value(row).ToTime(), nil
+	case *array.Date64:
+		return col.Value(row).ToTime(), nil
+	}
+
+	if c.TimestampFormat == "" {
+		return time.Time{}, fmt.Errorf("timestamp format required for column of type %s", column.DataType())
+	}
+	return internal.ParseTimestamp(c.TimestampFormat, value(column, row), time.UTC)
+}
+
+// value returns the value of the column in the given row as field value.
+func value(column arrow.Array, row int) interface{} {
+	switch col := column.(type) {
+	case *array.Int8:
+		return int64(col.Value(row))
+	case *array.Int16:
+		return int64(col.Value(row))
+	case *array.Int32:
+		return int64(col.Value(row))
+	case *array.Int64:
+		return col.Value(row)
+	case *array.Uint8:
+		return uint64(col.Value(row))
+	case *array.Uint16:
+		return uint64(col.Value(row))
+	case *array.Uint32:
+		return uint64(col.Value(row))
+	case *array.Uint64:
+		return col.Value(row)
+	case *array.Float16:
+		return float64(col.Value(row).Float32())
+	case *array.Float32:
+		return float64(col.Value(row))
+	case *array.Float64:
+		return col.Value(row)
+	case *array.Boolean:
+		return col.Value(row)
+	case *array.String:
+		return col.Value(row)
+	case *array.LargeString:
+		return col.Value(row)
+	case *array.Binary:
+		return string(col.Value(row))
+	case *array.Timestamp:
+		unit := col.DataType().(*arrow.TimestampType).Unit
+		return col.Value(row).ToTime(unit).UnixNano()
+	}
+	return column.ValueStr(row)
+}
@@ -0,0 +1,139 @@
+package arrow
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+// Magic string at the start of the IPC file format
+var fileMagic = []byte("ARROW1")
+
+type readerAtSeeker interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+}
+
+type Parser struct {
+	MeasurementColumn string   `toml:"arrow_measurement_column"`
+	TagColumns        []string `toml:"arrow_tag_columns"`
+	TimestampColumn   string   `toml:"arrow_timestamp_column"`
+	TimestampFormat   string   `toml:"arrow_timestamp_format"`
+
+	converter Converter
+}
+
+func (p *Parser) Init() error {
+	p.converter.MeasurementColumn = p.MeasurementColumn
+	p.converter.TagColumns = p.TagColumns
+	p.converter.TimestampColumn = p.TimestampColumn
+	p.converter.TimestampFormat = p.TimestampFormat
+	return p.converter.Init()
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+
+	var metrics []telegraf.Metric
+	err := p.ParseStream(bytes.NewReader(buf), func(batch []telegraf.Metric) error {
+		metrics = append(metrics, batch...)
+		return nil
+	})
+	return metrics, err
+}
+
+// ParseStream parses data in the IPC stream or file format and calls the
+// given function for each record batch. As the file format requires random
+// access, readers not supporting it are read into memory for this format.
+func (p *Parser) ParseStream(r io.Reader, fn func([]telegraf.Metric) error) error {
+	ra, ok := r.(readerAtSeeker)
+	if !ok {
+		br := bufio.NewReader(r)
+		if magic, err := br.Peek(len(fileMagic)); err != nil || !bytes.Equal(magic, fileMagic) {
+			return p.parseStream(br, fn)
+		}
+		buf, err := io.ReadAll(br)
+		if err != nil {
+			return err
+		}
+		ra = bytes.NewReader(buf)
+	}
+
+	magic := make([]byte, len(fileMagic))
+	if _, err := ra.ReadAt(magic, 0); err != nil || !bytes.Equal(magic, fileMagic) {
+		return p.parseStream(ra, fn)
+	}
+	return p.parseFile(ra, fn)
+}
+
+func (p *Parser) parseStream(r io.Reader, fn func([]telegraf.Metric) error) error {
+	reader, err := ipc.NewReader(r, ipc.WithAllocator(memory.DefaultAllocator))
+	if err != nil {
+		return fmt.Errorf("reading schema failed: %w", err)
+	}
+	defer reader.Release()
+
+	for reader.Next() {
+		metrics, err := p.converter.Convert(reader.Record())
+		if err != nil {
+			return err
+		}
+		if err := fn(metrics); err != nil {
+			return err
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return fmt.Errorf("reading record batch failed: %w", err)
+	}
+	return nil
+}
+
+func (p *Parser) parseFile(r readerAtSeeker, fn func([]telegraf.Metric) error) error {
+	reader, err := ipc.NewFileReader(r, ipc.WithAllocator(memory.DefaultAllocator))
+	if err != nil {
+		return fmt.Errorf("reading footer failed: %w", err)
+	}
+	defer reader.Close()
+
+	for i := 0; i < reader.NumRecords(); i++ {
+		record, err := reader.Record(i)
+		if err != nil {
+			return fmt.Errorf("reading record batch failed: %w", err)
+		}
+		metrics, err := p.converter.Convert(record)
+		if err != nil {
+			return err
+		}
+		if err := fn(metrics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Parser) ParseLine(string) (telegraf.Metric, error) {
+	return nil, errors.New("parsing line is not supported by the arrow parser")
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.converter.DefaultTags = tags
+}
+
+func init() {
+	parsers.Add("arrow",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{converter: Converter{MetricName: defaultMetricName}}
+		},
+	)
+}
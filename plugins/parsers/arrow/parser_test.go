@@ -0,0 +1,119 @@
+package arrow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+var testSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "name", Type: arrow.BinaryTypes.String},
+	{Name: "host", Type: arrow.BinaryTypes.String},
+	{Name: "ts", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "usage", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	{Name: "cores", Type: arrow.PrimitiveTypes.Int32},
+}, nil)
+
+func testRecord(t *testing.T, offset int64) arrow.Record {
+	t.Helper()
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, testSchema)
+	defer builder.Release()
+
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"cpu", "cpu"}, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b"}, nil)
+	builder.Field(2).(*array.Int64Builder).AppendValues([]int64{offset, offset + 1}, nil)
+	builder.Field(3).(*array.Float64Builder).AppendValues([]float64{42.5, 0}, []bool{true, false})
+	builder.Field(4).(*array.Int32Builder).AppendValues([]int32{4, 8}, nil)
+	return builder.NewRecord()
+}
+
+func TestParseStream(t *testing.T) {
+	var buf bytes.Buffer
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(testSchema))
+	for _, offset := range []int64{0, 10} {
+		record := testRecord(t, offset)
+		require.NoError(t, writer.Write(record))
+		record.Release()
+	}
+	require.NoError(t, writer.Close())
+
+	parser := &Parser{
+		MeasurementColumn: "name",
+		TagColumns:        []string{"host"},
+		TimestampColumn:   "ts",
+		TimestampFormat:   "unix",
+	}
+	require.NoError(t, parser.Init())
+	parser.SetDefaultTags(map[string]string{"source": "test"})
+
+	var batches int
+	var actual []telegraf.Metric
+	err := parser.ParseStream(&buf, func(metrics []telegraf.Metric) error {
+		batches++
+		actual = append(actual, metrics...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, batches)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"host": "a", "source": "test"}, map[string]interface{}{"usage": 42.5, "cores": int64(4)}, time.Unix(0, 0)),
+		testutil.MustMetric("cpu", map[string]string{"host": "b", "source": "test"}, map[string]interface{}{"cores": int64(8)}, time.Unix(1, 0)),
+		testutil.MustMetric("cpu", map[string]string{"host": "a", "source": "test"}, map[string]interface{}{"usage": 42.5, "cores": int64(4)}, time.Unix(10, 0)),
+		testutil.MustMetric("cpu", map[string]string{"host": "b", "source": "test"}, map[string]interface{}{"cores": int64(8)}, time.Unix(11, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestParseFileFormat(t *testing.T) {
+	// The file format requires a seekable writer
+	fn := filepath.Join(t.TempDir(), "test.arrow")
+	f, err := os.Create(fn)
+	require.NoError(t, err)
+	writer, err := ipc.NewFileWriter(f, ipc.WithSchema(testSchema))
+	require.NoError(t, err)
+	record := testRecord(t, 0)
+	require.NoError(t, writer.Write(record))
+	record.Release()
+	require.NoError(t, writer.Close())
+	require.NoError(t, f.Close())
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+
+	parser := &Parser{TagColumns: []string{"host"}}
+	require.NoError(t, parser.Init())
+	parser.converter.MetricName = "arrow"
+
+	metrics, err := parser.Parse(buf)
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	require.Equal(t, "arrow", metrics[0].Name())
+	require.Equal(t, map[string]interface{}{"name": "cpu", "ts": int64(0), "usage": 42.5, "cores": int64(4)}, metrics[0].Fields())
+}
+
+func TestTimestampFormatRequired(t *testing.T) {
+	var buf bytes.Buffer
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(testSchema))
+	record := testRecord(t, 0)
+	require.NoError(t, writer.Write(record))
+	record.Release()
+	require.NoError(t, writer.Close())
+
+	parser := &Parser{TimestampColumn: "ts"}
+	require.NoError(t, parser.Init())
+
+	_, err := parser.Parse(buf.Bytes())
+	require.ErrorContains(t, err, "timestamp format required for column of type int64")
+}
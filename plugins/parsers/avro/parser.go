@@ -33,6 +33,7 @@ type Parser struct {
 	Timestamp       string            `toml:"avro_timestamp"`
 	TimestampFormat string            `toml:"avro_timestamp_format"`
 	FieldSeparator  string            `toml:"avro_field_separator"`
+	UnionMode       string            `toml:"avro_union_mode"`
 	DefaultTags     map[string]string `toml:"tags"`
 
 	Log         telegraf.Logger `toml:"-"`
@@ -51,6 +52,13 @@ func (p *Parser) Init() error {
 			return fmt.Errorf("invalid timestamp format '%v'", p.TimestampFormat)
 		}
 	}
+	switch p.UnionMode {
+	case "":
+		p.UnionMode = "flatten"
+	case "flatten", "nullable":
+	default:
+		return fmt.Errorf("invalid union mode %q", p.UnionMode)
+	}
 	if p.SchemaRegistry != "" {
 		p.registryObj = newSchemaRegistry(p.SchemaRegistry)
 	}
@@ -133,6 +141,14 @@ func (p *Parser) createMetric(data map[string]interface{}, schema string) (teleg
 	fields := make(map[string]interface{})
 	tags := make(map[string]string)
 
+	var schemaObj map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &schemaObj); err != nil {
+		return nil, fmt.Errorf("unmarshaling schema failed: %w", err)
+	}
+	if p.UnionMode == "nullable" {
+		unwrapNullable(data, schemaObj)
+	}
+
 	// Set default tag values
 	for k, v := range p.DefaultTags {
 		tags[k] = v
@@ -140,6 +156,10 @@ func (p *Parser) createMetric(data map[string]interface{}, schema string) (teleg
 	// Avro doesn't have a Tag/Field distinction, so we have to tell
 	// Telegraf which items are our tags.
 	for _, tag := range p.Tags {
+		if _, found := data[tag]; !found && p.UnionMode == "nullable" {
+			// Null values are removed from the data
+			continue
+		}
 		sTag, err := internal.ToString(data[tag])
 		if err != nil {
 			p.Log.Warnf("Could not convert %v to string for tag %q: %v", data[tag], tag, err)
@@ -197,10 +217,6 @@ func (p *Parser) createMetric(data map[string]interface{}, schema string) (teleg
 		}
 	}
 
-	var schemaObj map[string]interface{}
-	if err := json.Unmarshal([]byte(schema), &schemaObj); err != nil {
-		return nil, fmt.Errorf("unmarshaling schema failed: %w", err)
-	}
 	if len(fields) == 0 {
 		// A telegraf metric needs at least one field.
 		return nil, errors.New("number of fields is 0; unable to create metric")
@@ -247,6 +263,32 @@ func (p *Parser) createMetric(data map[string]interface{}, schema string) (teleg
 	return metric.New(name, tags, fields, timestamp), nil
 }
 
+// unwrapNullable replaces the values of fields being a union of null and a
+// single other type by the plain value and removes null values.
+func unwrapNullable(data, schemaObj map[string]interface{}) {
+	definitions, _ := schemaObj["fields"].([]interface{})
+	for _, d := range definitions {
+		def, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := def["name"].(string)
+		union, ok := def["type"].([]interface{})
+		if !ok || len(union) != 2 || (union[0] != "null" && union[1] != "null") {
+			continue
+		}
+
+		switch v := data[name].(type) {
+		case nil:
+			delete(data, name)
+		case map[string]interface{}:
+			for _, value := range v {
+				data[name] = value
+			}
+		}
+	}
+}
+
 func init() {
 	parsers.Add("avro",
 		func(defaultMetricName string) telegraf.Parser {
@@ -0,0 +1,125 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/parquet"
+	"github.com/apache/arrow/go/v13/parquet/file"
+	"github.com/apache/arrow/go/v13/parquet/pqarrow"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/parsers"
+	"github.com/influxdata/telegraf/plugins/parsers/arrow"
+)
+
+// Number of rows read at once
+const defaultBatchSize = 10000
+
+type Parser struct {
+	MeasurementColumn string   `toml:"parquet_measurement_column"`
+	TagColumns        []string `toml:"parquet_tag_columns"`
+	TimestampColumn   string   `toml:"parquet_timestamp_column"`
+	TimestampFormat   string   `toml:"parquet_timestamp_format"`
+	BatchSize         int64    `toml:"parquet_batch_size"`
+
+	converter arrow.Converter
+}
+
+func (p *Parser) Init() error {
+	if p.BatchSize == 0 {
+		p.BatchSize = defaultBatchSize
+	}
+	if p.BatchSize < 0 {
+		return errors.New("batch size must be positive")
+	}
+
+	p.converter.MeasurementColumn = p.MeasurementColumn
+	p.converter.TagColumns = p.TagColumns
+	p.converter.TimestampColumn = p.TimestampColumn
+	p.converter.TimestampFormat = p.TimestampFormat
+	return p.converter.Init()
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+
+	var metrics []telegraf.Metric
+	err := p.ParseStream(bytes.NewReader(buf), func(batch []telegraf.Metric) error {
+		metrics = append(metrics, batch...)
+		return nil
+	})
+	return metrics, err
+}
+
+// ParseStream parses a Parquet file and calls the given function for each
+// batch of rows. As the metadata is located at the end of the file, readers
+// not supporting random access, like decompressing readers, are read into
+// memory first.
+func (p *Parser) ParseStream(r io.Reader, fn func([]telegraf.Metric) error) error {
+	ra, ok := r.(parquet.ReaderAtSeeker)
+	if !ok {
+		buf, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		ra = bytes.NewReader(buf)
+	}
+
+	pf, err := file.NewParquetReader(ra)
+	if err != nil {
+		return fmt.Errorf("opening file failed: %w", err)
+	}
+	defer pf.Close()
+
+	props := pqarrow.ArrowReadProperties{BatchSize: p.BatchSize}
+	fr, err := pqarrow.NewFileReader(pf, props, memory.DefaultAllocator)
+	if err != nil {
+		return fmt.Errorf("reading schema failed: %w", err)
+	}
+	if pf.NumRowGroups() == 0 {
+		return nil
+	}
+
+	reader, err := fr.GetRecordReader(context.Background(), nil, nil)
+	if err != nil {
+		return fmt.Errorf("reading row groups failed: %w", err)
+	}
+	defer reader.Release()
+
+	for reader.Next() {
+		metrics, err := p.converter.Convert(reader.Record())
+		if err != nil {
+			return err
+		}
+		if err := fn(metrics); err != nil {
+			return err
+		}
+	}
+	if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading rows failed: %w", err)
+	}
+	return nil
+}
+
+func (p *Parser) ParseLine(string) (telegraf.Metric, error) {
+	return nil, errors.New("parsing line is not supported by the parquet parser")
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.converter.DefaultTags = tags
+}
+
+func init() {
+	parsers.Add("parquet",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{converter: arrow.Converter{MetricName: defaultMetricName}}
+		},
+	)
+}
@@ -0,0 +1,85 @@
+package parquet
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	serializer "github.com/influxdata/telegraf/plugins/serializers/parquet"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestParseSerialized(t *testing.T) {
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": 42.5, "cores": int64(4), "online": true},
+			time.Unix(1, 0),
+		),
+		testutil.MustMetric(
+			"mem",
+			map[string]string{"host": "b"},
+			map[string]interface{}{"used": uint64(1024), "state": "ok"},
+			time.Unix(2, 500),
+		),
+	}
+
+	s := &serializer.Serializer{}
+	require.NoError(t, s.Init())
+	buf, err := s.SerializeBatch(expected)
+	require.NoError(t, err)
+
+	parser := &Parser{
+		MeasurementColumn: "measurement",
+		TagColumns:        []string{"host"},
+		TimestampColumn:   "time",
+	}
+	require.NoError(t, parser.Init())
+
+	actual, err := parser.Parse(buf)
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestParseStreamBatches(t *testing.T) {
+	var metrics []telegraf.Metric
+	for i := 0; i < 10; i++ {
+		m := testutil.MustMetric("test", map[string]string{}, map[string]interface{}{"value": int64(i)}, time.Unix(int64(i), 0))
+		metrics = append(metrics, m)
+	}
+
+	s := &serializer.Serializer{}
+	require.NoError(t, s.Init())
+	buf, err := s.SerializeBatch(metrics)
+	require.NoError(t, err)
+
+	parser := &Parser{
+		MeasurementColumn: "measurement",
+		TimestampColumn:   "time",
+		BatchSize:         4,
+	}
+	require.NoError(t, parser.Init())
+
+	var sizes []int
+	var actual []telegraf.Metric
+	err = parser.ParseStream(bytes.NewReader(buf), func(batch []telegraf.Metric) error {
+		sizes = append(sizes, len(batch))
+		actual = append(actual, batch...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 4, 2}, sizes)
+	testutil.RequireMetricsEqual(t, metrics, actual)
+}
+
+func TestInvalidTagColumn(t *testing.T) {
+	parser := &Parser{
+		TimestampColumn: "time",
+		TagColumns:      []string{"time"},
+	}
+	require.EqualError(t, parser.Init(), `column "time" cannot be used as tag`)
+}
@@ -0,0 +1,284 @@
+package prometheus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// Suffixes of the series belonging to an OpenMetrics family
+var openMetricsSuffixes = []string{"_total", "_created", "_count", "_sum", "_bucket", "_gcount", "_gsum", "_info"}
+
+// isOpenMetrics returns true if the content type denotes the OpenMetrics text
+// format.
+func isOpenMetrics(header http.Header) bool {
+	mediatype, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediatype == "application/openmetrics-text"
+}
+
+// parseOpenMetrics parses the OpenMetrics text format. In addition to the
+// classic format, units are added as tag, exemplars are kept as separate
+// metrics with the exemplar labels as tags and the "_created" series are
+// added as fields. With native values, the series of a summary or histogram
+// are combined into a single value.
+func (p *Parser) parseOpenMetrics(buf []byte) ([]telegraf.Metric, error) {
+	// The format requires an EOF marker, be lenient for e.g. files
+	if !bytes.HasSuffix(bytes.TrimRight(buf, "\n"), []byte("# EOF")) {
+		buf = append(buf, []byte("# EOF\n")...)
+	}
+
+	now := time.Now()
+	parser := textparse.NewOpenMetricsParser(buf)
+
+	// Metadata of the current family
+	var family, unit string
+	mtype := textparse.MetricTypeUnknown
+
+	// Series of a family sharing the labels and timestamp, like the count and
+	// sum of a histogram, end up in the same metric
+	type seriesKey struct {
+		hash uint64
+		ts   int64
+	}
+	index := make(map[seriesKey]telegraf.Metric)
+
+	var metrics []telegraf.Metric
+	for {
+		entry, err := parser.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading OpenMetrics format failed: %w", err)
+		}
+
+		switch entry {
+		case textparse.EntryType:
+			name, t := parser.Type()
+			if string(name) != family {
+				family, unit = string(name), ""
+			}
+			mtype = t
+			continue
+		case textparse.EntryUnit:
+			name, u := parser.Unit()
+			if string(name) != family {
+				family, mtype = string(name), textparse.MetricTypeUnknown
+			}
+			unit = string(u)
+			continue
+		case textparse.EntryHelp:
+			name, _ := parser.Help()
+			if string(name) != family {
+				family, unit, mtype = string(name), "", textparse.MetricTypeUnknown
+			}
+			continue
+		case textparse.EntrySeries:
+		default:
+			continue
+		}
+
+		_, ts, value := parser.Series()
+		var lset labels.Labels
+		parser.Metric(&lset)
+		name := lset.Get(labels.MetricName)
+
+		// Series without metadata are of unknown type
+		seriesType, seriesUnit := textparse.MetricTypeUnknown, ""
+		if belongsToFamily(name, family) {
+			seriesType, seriesUnit = mtype, unit
+		}
+		vtype := openMetricsValueType(seriesType)
+		if math.IsNaN(value) && (vtype == telegraf.Counter || vtype == telegraf.Gauge || vtype == telegraf.Untyped) {
+			continue
+		}
+
+		tags := make(map[string]string, len(lset)+len(p.DefaultTags))
+		for k, v := range p.DefaultTags {
+			tags[k] = v
+		}
+		lset.Range(func(l labels.Label) {
+			switch l.Name {
+			case labels.MetricName:
+			case "le", "quantile":
+				tags[l.Name] = normalizeBound(l.Value)
+			default:
+				tags[l.Name] = l.Value
+			}
+		})
+		if _, found := tags["unit"]; !found && seriesUnit != "" {
+			tags["unit"] = seriesUnit
+		}
+
+		t := now
+		if ts != nil && !p.IgnoreTimestamp {
+			t = time.UnixMilli(*ts)
+		}
+
+		if p.NativeValues && isNativeSeries(name, family, vtype) {
+			// All series of the same labels except the bound and the same
+			// timestamp make up one value named after the family
+			nativeTags := make(map[string]string, len(tags))
+			for k, v := range tags {
+				if k != "le" && k != "quantile" {
+					nativeTags[k] = v
+				}
+			}
+			m := metric.New("prometheus", nativeTags, nil, t, vtype)
+			key := seriesKey{hash: m.HashID(), ts: t.UnixNano()}
+			if existing, found := index[key]; found && existing.Type() == vtype {
+				m = existing
+			} else {
+				index[key] = m
+				metrics = append(metrics, m)
+			}
+			if err := addNativeSeries(m, family, name, tags, value); err != nil {
+				return nil, fmt.Errorf("converting %q failed: %w", name, err)
+			}
+		} else {
+			m := metric.New("prometheus", tags, map[string]interface{}{name: value}, t, vtype)
+			key := seriesKey{hash: m.HashID(), ts: t.UnixNano()}
+			if existing, found := index[key]; found && existing.Type() == vtype {
+				existing.AddField(name, value)
+			} else {
+				index[key] = m
+				metrics = append(metrics, m)
+			}
+		}
+
+		var e exemplar.Exemplar
+		if !parser.Exemplar(&e) {
+			continue
+		}
+		exemplarTags := make(map[string]string, len(tags)+len(e.Labels))
+		for k, v := range tags {
+			exemplarTags[k] = v
+		}
+		e.Labels.Range(func(l labels.Label) {
+			if _, found := exemplarTags[l.Name]; !found {
+				exemplarTags[l.Name] = l.Value
+			}
+		})
+		exemplarTime := t
+		if e.HasTs && !p.IgnoreTimestamp {
+			exemplarTime = time.UnixMilli(e.Ts)
+		}
+		fields := map[string]interface{}{name + "_exemplar": e.Value}
+		metrics = append(metrics, metric.New("prometheus", exemplarTags, fields, exemplarTime, vtype))
+	}
+
+	return metrics, nil
+}
+
+// belongsToFamily returns true if the series with the given name is part of
+// the given family.
+func belongsToFamily(name, family string) bool {
+	if family == "" || !strings.HasPrefix(name, family) {
+		return false
+	}
+	if name == family {
+		return true
+	}
+	for _, suffix := range openMetricsSuffixes {
+		if name == family+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// isNativeSeries returns true if the series is part of the native value of a
+// summary or histogram family. The "_created" series is kept as field.
+func isNativeSeries(name, family string, vtype telegraf.ValueType) bool {
+	if !belongsToFamily(name, family) || name == family+"_created" {
+		return false
+	}
+	return vtype == telegraf.Summary || vtype == telegraf.Histogram
+}
+
+// addNativeSeries adds the given series to the summary or histogram value of
+// the family in the metric, creating the value if necessary.
+func addNativeSeries(m telegraf.Metric, family, name string, tags map[string]string, value float64) error {
+	field, found := m.GetField(family)
+
+	if m.Type() == telegraf.Summary {
+		v, ok := field.(*telegraf.SummaryValue)
+		if !found || !ok {
+			v = &telegraf.SummaryValue{}
+			m.AddField(family, v)
+		}
+		switch name {
+		case family + "_count":
+			v.Count = uint64(math.Round(value))
+		case family + "_sum":
+			v.Sum = value
+		case family:
+			q, err := strconv.ParseFloat(tags["quantile"], 64)
+			if err != nil {
+				return fmt.Errorf("invalid quantile %q: %w", tags["quantile"], err)
+			}
+			v.Quantiles = append(v.Quantiles, telegraf.Quantile{Quantile: q, Value: value})
+		}
+		return nil
+	}
+
+	v, ok := field.(*telegraf.HistogramValue)
+	if !found || !ok {
+		v = &telegraf.HistogramValue{}
+		m.AddField(family, v)
+	}
+	switch name {
+	case family + "_count", family + "_gcount":
+		v.Count = uint64(math.Round(value))
+	case family + "_sum", family + "_gsum":
+		v.Sum = value
+	case family + "_bucket":
+		bound, err := strconv.ParseFloat(tags["le"], 64)
+		if err != nil {
+			return fmt.Errorf("invalid bucket bound %q: %w", tags["le"], err)
+		}
+		v.Buckets = append(v.Buckets, telegraf.Bucket{UpperBound: bound, Count: uint64(math.Round(value))})
+	}
+	return nil
+}
+
+// openMetricsValueType maps the OpenMetrics types to Telegraf value types.
+// Info and stateset metrics are gauges in Prometheus, gauge histograms keep
+// their histogram layout.
+func openMetricsValueType(t textparse.MetricType) telegraf.ValueType {
+	switch t {
+	case textparse.MetricTypeCounter:
+		return telegraf.Counter
+	case textparse.MetricTypeGauge, textparse.MetricTypeInfo, textparse.MetricTypeStateset:
+		return telegraf.Gauge
+	case textparse.MetricTypeHistogram, textparse.MetricTypeGaugeHistogram:
+		return telegraf.Histogram
+	case textparse.MetricTypeSummary:
+		return telegraf.Summary
+	}
+	return telegraf.Untyped
+}
+
+// normalizeBound formats bucket bounds and quantiles like the classic format,
+// e.g. "1" instead of the canonical OpenMetrics "1.0".
+func normalizeBound(s string) string {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -21,10 +21,16 @@ import (
 	"github.com/influxdata/telegraf/plugins/parsers/prometheus/common"
 )
 
+// Maximum number of buckets of a native histogram after expanding the spans
+// into a dense range. At the highest resolution, this covers 256 powers of two.
+const maxNativeBuckets = 1 << 16
+
 type Parser struct {
 	DefaultTags     map[string]string `toml:"-"`
 	Header          http.Header       `toml:"-"` // set by the prometheus input
 	IgnoreTimestamp bool              `toml:"prometheus_ignore_timestamp"`
+	NativeValues    bool              `toml:"prometheus_native_values"`
+	OpenMetrics     bool              `toml:"prometheus_openmetrics"`
 }
 
 func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
@@ -38,6 +44,10 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 		buf = append(buf, []byte("\n")...)
 	}
 
+	if p.OpenMetrics || isOpenMetrics(p.Header) {
+		return p.parseOpenMetrics(buf)
+	}
+
 	// Read raw data
 	buffer := bytes.NewBuffer(buf)
 	reader := bufio.NewReader(buffer)
@@ -74,7 +84,15 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 			tags := common.MakeLabels(m, p.DefaultTags)
 			t := p.GetTimestamp(m, now)
 
-			if mf.GetType() == dto.MetricType_SUMMARY {
+			if p.NativeValues && (mf.GetType() == dto.MetricType_SUMMARY || mf.GetType() == dto.MetricType_HISTOGRAM) {
+				// summary or histogram metric as a single value
+				value, err := nativeValue(m)
+				if err != nil {
+					return nil, fmt.Errorf("converting %q failed: %w", metricName, err)
+				}
+				fields := map[string]interface{}{metricName: value}
+				metrics = append(metrics, metric.New("prometheus", tags, fields, t, common.ValueType(mf.GetType())))
+			} else if mf.GetType() == dto.MetricType_SUMMARY {
 				// summary metric
 				telegrafMetrics := makeQuantiles(m, tags, metricName, mf.GetType(), t)
 				metrics = append(metrics, telegrafMetrics...)
@@ -180,6 +198,112 @@ func makeBuckets(m *dto.Metric, tags map[string]string, metricName string, metri
 	return metrics
 }
 
+// Get the complete summary or histogram including native buckets
+func nativeValue(m *dto.Metric) (interface{}, error) {
+	if m.Summary != nil {
+		s := m.GetSummary()
+		v := &telegraf.SummaryValue{
+			Count:     s.GetSampleCount(),
+			Sum:       s.GetSampleSum(),
+			Quantiles: make([]telegraf.Quantile, 0, len(s.Quantile)),
+		}
+		for _, q := range s.Quantile {
+			v.Quantiles = append(v.Quantiles, telegraf.Quantile{Quantile: q.GetQuantile(), Value: q.GetValue()})
+		}
+		return v, nil
+	}
+
+	h := m.GetHistogram()
+	v := &telegraf.HistogramValue{
+		Count: h.GetSampleCount(),
+		Sum:   h.GetSampleSum(),
+	}
+	if h.SampleCountFloat != nil {
+		v.Count = uint64(math.Round(h.GetSampleCountFloat()))
+	}
+	for _, b := range h.Bucket {
+		count := b.GetCumulativeCount()
+		if b.CumulativeCountFloat != nil {
+			count = uint64(math.Round(b.GetCumulativeCountFloat()))
+		}
+		v.Buckets = append(v.Buckets, telegraf.Bucket{UpperBound: b.GetUpperBound(), Count: count})
+	}
+
+	// Native histograms have spans, a zero bucket or at least a zero threshold
+	if len(h.PositiveSpan) == 0 && len(h.NegativeSpan) == 0 && h.GetZeroThreshold() == 0 &&
+		h.GetZeroCount() == 0 && h.GetZeroCountFloat() == 0 {
+		return v, nil
+	}
+	positive, err := bucketRange(h.PositiveSpan, h.PositiveDelta, h.PositiveCount)
+	if err != nil {
+		return nil, fmt.Errorf("positive buckets: %w", err)
+	}
+	negative, err := bucketRange(h.NegativeSpan, h.NegativeDelta, h.NegativeCount)
+	if err != nil {
+		return nil, fmt.Errorf("negative buckets: %w", err)
+	}
+	v.Exponential = &telegraf.ExponentialBuckets{
+		Schema:        h.GetSchema(),
+		ZeroThreshold: h.GetZeroThreshold(),
+		ZeroCount:     h.GetZeroCount(),
+		Positive:      positive,
+		Negative:      negative,
+	}
+	if h.ZeroCountFloat != nil {
+		v.Exponential.ZeroCount = uint64(math.Round(h.GetZeroCountFloat()))
+	}
+	return v, nil
+}
+
+// Convert the sparse, delta-encoded native buckets into a dense range. In
+// contrast to Telegraf, Prometheus bucket i covers the range (base^(i-1), base^i].
+// Ranges exceeding maxNativeBuckets are rejected to limit memory usage.
+func bucketRange(spans []*dto.BucketSpan, deltas []int64, counts []float64) (telegraf.BucketRange, error) {
+	var r telegraf.BucketRange
+	if len(spans) == 0 {
+		return r, nil
+	}
+
+	var total int64
+	for i, span := range spans {
+		if i > 0 {
+			if span.GetOffset() < 0 {
+				return r, fmt.Errorf("invalid offset %d of span %d", span.GetOffset(), i)
+			}
+			total += int64(span.GetOffset())
+		}
+		total += int64(span.GetLength())
+		if total > maxNativeBuckets {
+			return r, fmt.Errorf("more than %d buckets", maxNativeBuckets)
+		}
+	}
+	r.Counts = make([]uint64, 0, total)
+
+	r.Offset = spans[0].GetOffset() - 1
+	var value int64
+	var n int
+	for i, span := range spans {
+		if i > 0 {
+			for j := int32(0); j < span.GetOffset(); j++ {
+				r.Counts = append(r.Counts, 0)
+			}
+		}
+		for j := uint32(0); j < span.GetLength(); j++ {
+			switch {
+			case n < len(deltas):
+				value += deltas[n]
+				r.Counts = append(r.Counts, uint64(value))
+			case n < len(counts):
+				r.Counts = append(r.Counts, uint64(math.Round(counts[n])))
+			default:
+				r.Counts = append(r.Counts, 0)
+			}
+			n++
+		}
+	}
+	return r, nil
+}
+
 // Get name and value from metric
 func getNameAndValue(m *dto.Metric, metricName string) map[string]interface{} {
 	fields := make(map[string]interface{})
@@ -1,16 +1,20 @@
 package prometheus
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/parsers/prometheus/common"
@@ -647,3 +651,341 @@ func TestHistogramInfBucketPresence(t *testing.T) {
 
 	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime(), testutil.SortMetrics())
 }
+
+func TestParsingNativeValues(t *testing.T) {
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"handler": "prometheus"},
+			map[string]interface{}{
+				"http_request_duration_microseconds": &telegraf.SummaryValue{
+					Count: 9,
+					Sum:   1.8909097205e+07,
+					Quantiles: []telegraf.Quantile{
+						{Quantile: 0.5, Value: 552048.506},
+						{Quantile: 0.9, Value: 5.876804288e+06},
+						{Quantile: 0.99, Value: 5.876804288e+06},
+					},
+				},
+			},
+			time.Unix(0, 0),
+			telegraf.Summary,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"verb": "POST", "resource": "bindings"},
+			map[string]interface{}{
+				"apiserver_request_latencies": &telegraf.HistogramValue{
+					Count: 2025,
+					Sum:   1.02726334e+08,
+					Buckets: []telegraf.Bucket{
+						{UpperBound: 125000, Count: 1994},
+						{UpperBound: 250000, Count: 1997},
+						{UpperBound: 500000, Count: 2000},
+						{UpperBound: 1e+06, Count: 2005},
+						{UpperBound: 2e+06, Count: 2012},
+						{UpperBound: 4e+06, Count: 2017},
+						{UpperBound: 8e+06, Count: 2024},
+						{UpperBound: math.Inf(1), Count: 2025},
+					},
+				},
+			},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+	}
+
+	parser := Parser{NativeValues: true}
+	metrics, err := parser.Parse([]byte(validUniqueSummary + validUniqueHistogram))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestParsingNativeHistogram(t *testing.T) {
+	mf := &dto.MetricFamily{
+		Name: proto.String("request_duration_seconds"),
+		Type: dto.MetricType_HISTOGRAM.Enum(),
+		Metric: []*dto.Metric{
+			{
+				Histogram: &dto.Histogram{
+					SampleCount:   proto.Uint64(7),
+					SampleSum:     proto.Float64(12.5),
+					Schema:        proto.Int32(0),
+					ZeroThreshold: proto.Float64(1e-128),
+					ZeroCount:     proto.Uint64(1),
+					PositiveSpan: []*dto.BucketSpan{
+						{Offset: proto.Int32(0), Length: proto.Uint32(2)},
+						{Offset: proto.Int32(1), Length: proto.Uint32(1)},
+					},
+					PositiveDelta: []int64{1, 1, -1},
+					NegativeSpan:  []*dto.BucketSpan{{Offset: proto.Int32(1), Length: proto.Uint32(1)}},
+					NegativeDelta: []int64{2},
+				},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	_, err := pbutil.WriteDelimited(&buf, mf)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{},
+			map[string]interface{}{
+				"request_duration_seconds": &telegraf.HistogramValue{
+					Count: 7,
+					Sum:   12.5,
+					Exponential: &telegraf.ExponentialBuckets{
+						Schema:        0,
+						ZeroThreshold: 1e-128,
+						ZeroCount:     1,
+						Positive:      telegraf.BucketRange{Offset: -1, Counts: []uint64{1, 2, 0, 1}},
+						Negative:      telegraf.BucketRange{Offset: 0, Counts: []uint64{2}},
+					},
+				},
+			},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited")
+	parser := Parser{Header: header, NativeValues: true}
+	metrics, err := parser.Parse(buf.Bytes())
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+}
+
+func TestParsingNativeHistogramTooManyBuckets(t *testing.T) {
+	mf := &dto.MetricFamily{
+		Name: proto.String("request_duration_seconds"),
+		Type: dto.MetricType_HISTOGRAM.Enum(),
+		Metric: []*dto.Metric{
+			{
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(2),
+					SampleSum:   proto.Float64(1),
+					Schema:      proto.Int32(0),
+					PositiveSpan: []*dto.BucketSpan{
+						{Offset: proto.Int32(0), Length: proto.Uint32(1)},
+						{Offset: proto.Int32(math.MaxInt32), Length: proto.Uint32(1)},
+					},
+					PositiveDelta: []int64{1, 0},
+				},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	_, err := pbutil.WriteDelimited(&buf, mf)
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited")
+	parser := Parser{Header: header, NativeValues: true}
+	_, err = parser.Parse(buf.Bytes())
+	require.EqualError(t, err, `converting "request_duration_seconds" failed: positive buckets: more than 65536 buckets`)
+}
+
+func TestParsingOpenMetrics(t *testing.T) {
+	input := `# TYPE http_requests counter
+# UNIT http_requests requests
+# HELP http_requests Number of requests.
+http_requests_total{code="200"} 1027 # {trace_id="0af7651916cd43dd8448eb211c80319c"} 1 1520879607.789
+http_requests_created{code="200"} 1520430000.123
+# TYPE build info
+build_info{version="1.2.3"} 1
+# TYPE state stateset
+state{state="running"} 1
+state{state="stopped"} 0
+# TYPE queue_size gaugehistogram
+queue_size_bucket{le="1.0"} 3
+queue_size_bucket{le="+Inf"} 5
+queue_size_gcount 5
+queue_size_gsum 7.5
+# TYPE latency_seconds summary
+# UNIT latency_seconds seconds
+latency_seconds{quantile="0.5"} 0.25
+latency_seconds_count 10 1520879607.0
+latency_seconds_sum 3.5 1520879607.0
+untyped_value 42
+# EOF
+`
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"code": "200", "unit": "requests"},
+			map[string]interface{}{
+				"http_requests_total":   float64(1027),
+				"http_requests_created": 1520430000.123,
+			},
+			time.Unix(0, 0),
+			telegraf.Counter,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"code": "200", "unit": "requests", "trace_id": "0af7651916cd43dd8448eb211c80319c"},
+			map[string]interface{}{"http_requests_total_exemplar": float64(1)},
+			time.UnixMilli(1520879607789),
+			telegraf.Counter,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"version": "1.2.3"},
+			map[string]interface{}{"build_info": float64(1)},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"state": "running"},
+			map[string]interface{}{"state": float64(1)},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"state": "stopped"},
+			map[string]interface{}{"state": float64(0)},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"le": "1"},
+			map[string]interface{}{"queue_size_bucket": float64(3)},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"le": "+Inf"},
+			map[string]interface{}{"queue_size_bucket": float64(5)},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{},
+			map[string]interface{}{"queue_size_gcount": float64(5), "queue_size_gsum": 7.5},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"quantile": "0.5", "unit": "seconds"},
+			map[string]interface{}{"latency_seconds": 0.25},
+			time.Unix(0, 0),
+			telegraf.Summary,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"unit": "seconds"},
+			map[string]interface{}{"latency_seconds_count": float64(10), "latency_seconds_sum": 3.5},
+			time.Unix(1520879607, 0),
+			telegraf.Summary,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{},
+			map[string]interface{}{"untyped_value": float64(42)},
+			time.Unix(0, 0),
+			telegraf.Untyped,
+		),
+	}
+
+	parser := Parser{Header: http.Header{"Content-Type": []string{"application/openmetrics-text; version=1.0.0; charset=utf-8"}}}
+	metrics, err := parser.Parse([]byte(input))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+
+	// The timestamps of the series and exemplars are kept
+	require.Equal(t, time.UnixMilli(1520879607789), metrics[1].Time())
+	require.Equal(t, time.Unix(1520879607, 0), metrics[9].Time())
+
+	// The EOF marker is optional for the parser option
+	parser = Parser{OpenMetrics: true}
+	metrics, err = parser.Parse([]byte("# TYPE up gauge\nup 1\n"))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		testutil.MustMetric("prometheus", map[string]string{}, map[string]interface{}{"up": float64(1)}, time.Unix(0, 0), telegraf.Gauge),
+	}, metrics, testutil.IgnoreTime())
+}
+
+func TestParsingOpenMetricsNativeValues(t *testing.T) {
+	input := `# TYPE request_duration_seconds histogram
+# UNIT request_duration_seconds seconds
+request_duration_seconds_bucket{path="/",le="0.1"} 8 # {trace_id="0af7651916cd43dd8448eb211c80319c"} 0.05
+request_duration_seconds_bucket{path="/",le="1.0"} 10
+request_duration_seconds_bucket{path="/",le="+Inf"} 11
+request_duration_seconds_count{path="/"} 11
+request_duration_seconds_sum{path="/"} 4.5
+request_duration_seconds_created{path="/"} 1520430000.123
+# TYPE latency_seconds summary
+latency_seconds{quantile="0.5"} 0.25
+latency_seconds{quantile="0.9"} 0.75
+latency_seconds_count 10
+latency_seconds_sum 3.5
+# TYPE up gauge
+up 1
+# EOF
+`
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"path": "/", "unit": "seconds"},
+			map[string]interface{}{
+				"request_duration_seconds": &telegraf.HistogramValue{
+					Count: 11,
+					Sum:   4.5,
+					Buckets: []telegraf.Bucket{
+						{UpperBound: 0.1, Count: 8},
+						{UpperBound: 1, Count: 10},
+						{UpperBound: math.Inf(1), Count: 11},
+					},
+				},
+				"request_duration_seconds_created": 1520430000.123,
+			},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{"path": "/", "unit": "seconds", "le": "0.1", "trace_id": "0af7651916cd43dd8448eb211c80319c"},
+			map[string]interface{}{"request_duration_seconds_bucket_exemplar": 0.05},
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{},
+			map[string]interface{}{
+				"latency_seconds": &telegraf.SummaryValue{
+					Count: 10,
+					Sum:   3.5,
+					Quantiles: []telegraf.Quantile{
+						{Quantile: 0.5, Value: 0.25},
+						{Quantile: 0.9, Value: 0.75},
+					},
+				},
+			},
+			time.Unix(0, 0),
+			telegraf.Summary,
+		),
+		testutil.MustMetric(
+			"prometheus",
+			map[string]string{},
+			map[string]interface{}{"up": float64(1)},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+	}
+
+	parser := Parser{OpenMetrics: true, NativeValues: true}
+	metrics, err := parser.Parse([]byte(input))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -25,6 +26,8 @@ type Execd struct {
 	Command      []string        `toml:"command"`
 	Environment  []string        `toml:"environment"`
 	RestartDelay config.Duration `toml:"restart_delay"`
+	Acknowledge  bool            `toml:"acknowledge"`
+	AckTimeout   config.Duration `toml:"ack_timeout"`
 	Log          telegraf.Logger
 
 	parser           telegraf.Parser
@@ -32,11 +35,15 @@ type Execd struct {
 	serializer       serializers.Serializer
 	acc              telegraf.Accumulator
 	process          *process.Process
+	tracker          *process.AckTracker
+	done             chan struct{}
+	wg               sync.WaitGroup
 }
 
 func New() *Execd {
 	return &Execd{
 		RestartDelay: config.Duration(10 * time.Second),
+		AckTimeout:   config.Duration(30 * time.Second),
 		serializerConfig: &serializers.Config{
 			DataFormat: "influx",
 		},
@@ -67,6 +74,10 @@ func (e *Execd) Start(acc telegraf.Accumulator) error {
 	e.process.RestartDelay = time.Duration(e.RestartDelay)
 	e.process.ReadStdoutFn = e.cmdReadOut
 	e.process.ReadStderrFn = e.cmdReadErr
+	if e.Acknowledge {
+		e.tracker = process.NewAckTracker()
+		e.process.ReadStdoutFn = e.cmdReadOutAck
+	}
 
 	if err = e.process.Start(); err != nil {
 		// if there was only one argument, and it contained spaces, warn the user
@@ -79,10 +90,24 @@ func (e *Execd) Start(acc telegraf.Accumulator) error {
 		return fmt.Errorf("failed to start process %s: %w", e.Command, err)
 	}
 
+	if e.Acknowledge {
+		e.done = make(chan struct{})
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			e.expireAcks()
+		}()
+	}
+
 	return nil
 }
 
 func (e *Execd) Add(m telegraf.Metric, _ telegraf.Accumulator) error {
+	if e.Acknowledge {
+		e.write([]telegraf.Metric{m})
+		return nil
+	}
+
 	b, err := e.serializer.Serialize(m)
 	if err != nil {
 		return fmt.Errorf("metric serializing error: %w", err)
@@ -100,8 +125,139 @@ func (e *Execd) Add(m telegraf.Metric, _ telegraf.Accumulator) error {
 	return nil
 }
 
+// AddBatch writes all metrics of the batch to the process at once.
+func (e *Execd) AddBatch(metrics []telegraf.Metric, _ telegraf.Accumulator) error {
+	if e.Acknowledge {
+		e.write(metrics)
+		return nil
+	}
+
+	// See Add for why tracking metrics are dropped
+	defer func() {
+		for _, m := range metrics {
+			m.Drop()
+		}
+	}()
+
+	b, err := e.serializer.SerializeBatch(metrics)
+	if err != nil {
+		return fmt.Errorf("metric serializing error: %w", err)
+	}
+
+	_, err = e.process.Stdin.Write(b)
+	if err != nil {
+		return fmt.Errorf("error writing to process stdin: %w", err)
+	}
+	return nil
+}
+
 func (e *Execd) Stop() {
 	e.process.Stop()
+	if e.done != nil {
+		close(e.done)
+		e.wg.Wait()
+	}
+}
+
+// write passes the metrics, each preceded by its ID, to the process and keeps
+// track of them until the process acknowledges them.
+func (e *Execd) write(metrics []telegraf.Metric) {
+	var buf []byte
+	entries := make([]*process.AckEntry, 0, len(metrics))
+	for _, m := range metrics {
+		b, err := e.serializer.Serialize(m)
+		if err != nil {
+			e.Log.Errorf("Metric serializing error: %v", err)
+			m.Reject()
+			continue
+		}
+		entry := e.tracker.Add(m)
+		entries = append(entries, entry)
+		buf = append(buf, process.FormatAckID(entry.ID)...)
+		buf = append(buf, b...)
+	}
+	if len(buf) == 0 {
+		return
+	}
+
+	if _, err := e.process.Stdin.Write(buf); err != nil {
+		e.Log.Errorf("Error writing to process stdin: %v", err)
+		for _, entry := range entries {
+			if _, found := e.tracker.Resolve(entry.ID, err); found {
+				entry.Metric.Reject()
+			}
+		}
+	}
+}
+
+// expireAcks rejects the metrics not acknowledged by the process in time.
+func (e *Execd) expireAcks() {
+	ticker := time.NewTicker(time.Duration(e.AckTimeout))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+			expired := e.tracker.Expire(time.Duration(e.AckTimeout))
+			if len(expired) > 0 {
+				e.Log.Errorf("Rejecting %d metrics not acknowledged by the process in time", len(expired))
+			}
+			for _, entry := range expired {
+				entry.Metric.Reject()
+			}
+		}
+	}
+}
+
+// cmdReadOutAck reads the processed metrics and the acknowledgements of the
+// process. Metrics following an ID line are derived from the metric with that
+// ID and inherit its tracking information.
+func (e *Execd) cmdReadOutAck(out io.Reader) {
+	scanner := bufio.NewScanner(out)
+	scanBuf := make([]byte, 4096)
+	scanner.Buffer(scanBuf, 262144)
+
+	var current uint64
+	for scanner.Scan() {
+		if msg, ok := process.ParseAck(scanner.Bytes()); ok {
+			switch msg.Type {
+			case process.AckID:
+				current = msg.ID
+			case process.Ack:
+				if entry, found := e.tracker.Resolve(msg.ID, nil); found {
+					entry.Metric.Accept()
+				}
+			case process.Nack:
+				if entry, found := e.tracker.Resolve(msg.ID, errors.New(msg.Message)); found {
+					e.Log.Errorf("Metric rejected by the process: %s", msg.Message)
+					entry.Metric.Reject()
+				}
+			}
+			continue
+		}
+
+		metrics, err := e.parser.Parse(scanner.Bytes())
+		if err != nil {
+			e.Log.Errorf("Parse error: %s", err)
+		}
+		for _, m := range metrics {
+			if derived, found := e.tracker.Derive(current, m); found {
+				m = derived
+			}
+			e.acc.AddMetric(m)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		e.Log.Errorf("Error reading stdout: %s", err)
+	}
+
+	// The process terminated, so the pending metrics are lost
+	for _, entry := range e.tracker.ResolveAll(errors.New("process terminated")) {
+		entry.Metric.Reject()
+	}
 }
 
 func (e *Execd) cmdReadOut(out io.Reader) {
@@ -175,6 +331,9 @@ func (e *Execd) Init() error {
 	if len(e.Command) == 0 {
 		return errors.New("no command specified")
 	}
+	if e.Acknowledge && e.AckTimeout <= 0 {
+		return errors.New("ack_timeout must be positive")
+	}
 	return nil
 }
 
@@ -5,13 +5,16 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/shim"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
 	influxSerializer "github.com/influxdata/telegraf/plugins/serializers/influx"
 	"github.com/influxdata/telegraf/testutil"
@@ -135,9 +138,108 @@ func TestParseLinesWithNewLines(t *testing.T) {
 	testutil.RequireMetricEqual(t, expectedMetric, processedMetric)
 }
 
+func TestExternalProcessorBatch(t *testing.T) {
+	e := New()
+	e.Log = testutil.Logger{}
+
+	parser := &influx.Parser{}
+	require.NoError(t, parser.Init())
+	e.SetParser(parser)
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	e.Command = []string{exe, "-countmultiplier"}
+	e.Environment = []string{"PLUGINS_PROCESSORS_EXECD_MODE=application", "FIELD_NAME=count"}
+	e.RestartDelay = config.Duration(5 * time.Second)
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, e.Start(acc))
+
+	now := time.Now()
+	batch := make([]telegraf.Metric, 0, 3)
+	expected := make([]telegraf.Metric, 0, 3)
+	for i := 0; i < 3; i++ {
+		ts := now.Add(time.Duration(i))
+		batch = append(batch, metric.New("test", map[string]string{}, map[string]interface{}{"count": i}, ts))
+		expected = append(expected, metric.New("test", map[string]string{}, map[string]interface{}{"count": 2 * i}, ts))
+	}
+	require.NoError(t, e.AddBatch(batch, acc))
+
+	acc.Wait(3)
+	e.Stop()
+
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestExternalProcessorAck(t *testing.T) {
+	e := New()
+	e.Log = testutil.Logger{}
+	e.Acknowledge = true
+
+	parser := &influx.Parser{}
+	require.NoError(t, parser.Init())
+	e.SetParser(parser)
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	e.Command = []string{exe, "-ackmultiplier"}
+	e.Environment = []string{"PLUGINS_PROCESSORS_EXECD_MODE=application", "FIELD_NAME=count"}
+	e.RestartDelay = config.Duration(5 * time.Second)
+	require.NoError(t, e.Init())
+
+	acc := &acceptingAccumulator{}
+	require.NoError(t, e.Start(acc))
+	defer e.Stop()
+
+	var mu sync.Mutex
+	delivered := make(map[telegraf.TrackingID]bool)
+	notify := func(di telegraf.DeliveryInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered[di.ID()] = di.Delivered()
+	}
+
+	now := time.Now()
+	valid, validID := metric.WithTracking(metric.New("test", map[string]string{}, map[string]interface{}{"count": 1}, now), notify)
+	invalid, invalidID := metric.WithTracking(metric.New("test", map[string]string{}, map[string]interface{}{"count": "one"}, now), notify)
+	require.NoError(t, e.Add(valid, acc))
+	require.NoError(t, e.Add(invalid, acc))
+
+	// The processed metric inherits the tracking information, the metric
+	// failing to be processed is rejected
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(delivered) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.True(t, delivered[validID])
+	require.False(t, delivered[invalidID])
+	mu.Unlock()
+
+	expected := []telegraf.Metric{
+		metric.New("test", map[string]string{}, map[string]interface{}{"count": 2}, now),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+// acceptingAccumulator accepts all metrics like an output writing them
+// successfully
+type acceptingAccumulator struct {
+	testutil.Accumulator
+}
+
+func (a *acceptingAccumulator) AddMetric(m telegraf.Metric) {
+	a.Accumulator.AddMetric(m)
+	m.Accept()
+}
+
 var countmultiplier = flag.Bool("countmultiplier", false,
 	"if true, act like line input program instead of test")
 
+var ackmultiplier = flag.Bool("ackmultiplier", false,
+	"if true, act like a processor acknowledging metrics instead of test")
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	runMode := os.Getenv("PLUGINS_PROCESSORS_EXECD_MODE")
@@ -145,6 +247,10 @@ func TestMain(m *testing.M) {
 		runCountMultiplierProgram()
 		os.Exit(0)
 	}
+	if *ackmultiplier && runMode == "application" {
+		runAckMultiplierProgram()
+		os.Exit(0)
+	}
 	code := m.Run()
 	os.Exit(code)
 }
@@ -199,3 +305,46 @@ func runCountMultiplierProgram() {
 		fmt.Fprint(os.Stdout, string(b))
 	}
 }
+
+func runAckMultiplierProgram() {
+	s := shim.New()
+	if err := s.AddStreamingProcessor(&ackMultiplier{field: os.Getenv("FIELD_NAME")}); err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // os.Exit called intentionally
+		os.Exit(1)
+	}
+	if err := s.Run(shim.PollIntervalDisabled); err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // os.Exit called intentionally
+		os.Exit(1)
+	}
+}
+
+// ackMultiplier doubles integer fields and fails for all other types
+type ackMultiplier struct {
+	field string
+}
+
+func (*ackMultiplier) SampleConfig() string {
+	return ""
+}
+
+func (*ackMultiplier) Start(telegraf.Accumulator) error {
+	return nil
+}
+
+func (p *ackMultiplier) Add(m telegraf.Metric, acc telegraf.Accumulator) error {
+	v, ok := m.Fields()[p.field].(int64)
+	if !ok {
+		return fmt.Errorf("%s is not an integer", p.field)
+	}
+	m.AddField(p.field, 2*v)
+	acc.AddMetric(m)
+	return nil
+}
+
+func (*ackMultiplier) Stop() {}
+
+func (*ackMultiplier) Synchronous() bool {
+	return true
+}
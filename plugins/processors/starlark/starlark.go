@@ -3,6 +3,7 @@ package starlark
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 
 	"go.starlark.net/starlark"
@@ -31,10 +32,17 @@ func (s *Starlark) Init() error {
 		return err
 	}
 
-	// The source should define an apply function.
-	err = s.AddFunction("apply", &common.Metric{})
-	if err != nil {
-		return err
+	// The source should define an apply function, an apply_batch function
+	// or both.
+	if s.IsDefined("apply_batch") {
+		if err := s.AddFunction("apply_batch", starlark.NewList(nil)); err != nil {
+			return err
+		}
+	}
+	if s.IsDefined("apply") || !s.IsDefined("apply_batch") {
+		if err := s.AddFunction("apply", &common.Metric{}); err != nil {
+			return err
+		}
 	}
 
 	// Preallocate a slice for return values.
@@ -50,7 +58,8 @@ func (s *Starlark) Start(_ telegraf.Accumulator) error {
 func (s *Starlark) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
 	parameters, found := s.GetParameters("apply")
 	if !found {
-		return fmt.Errorf("the parameters of the apply function could not be found")
+		// The script only defines an apply_batch function
+		return s.AddBatch([]telegraf.Metric{metric}, acc)
 	}
 	parameters[0].(*common.Metric).Wrap(metric)
 
@@ -108,6 +117,77 @@ func (s *Starlark) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
 	return nil
 }
 
+func (s *Starlark) AddBatch(metrics []telegraf.Metric, acc telegraf.Accumulator) error {
+	parameters, found := s.GetParameters("apply_batch")
+	if !found {
+		// The script only defines an apply function
+		var errs []error
+		for _, m := range metrics {
+			if err := s.Add(m, acc); err != nil {
+				m.Drop()
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	wrapped := make([]starlark.Value, 0, len(metrics))
+	for _, m := range metrics {
+		w := &common.Metric{}
+		w.Wrap(m)
+		wrapped = append(wrapped, w)
+	}
+	parameters[0] = starlark.NewList(wrapped)
+
+	rv, err := s.Call("apply_batch")
+	if err != nil {
+		s.LogError(err)
+		for _, m := range metrics {
+			m.Drop()
+		}
+		return err
+	}
+
+	switch rv := rv.(type) {
+	case *starlark.List:
+		returned := make(map[telegraf.Metric]bool, rv.Len())
+		iter := rv.Iterate()
+		defer iter.Done()
+		var v starlark.Value
+		for iter.Next(&v) {
+			switch v := v.(type) {
+			case *common.Metric:
+				m := v.Unwrap()
+				if returned[m] {
+					s.Log.Errorf("Duplicate metric reference detected")
+					continue
+				}
+				returned[m] = true
+				acc.AddMetric(m)
+			default:
+				s.Log.Errorf("Invalid type returned in list: %s", v.Type())
+			}
+		}
+
+		// Mark the metrics not returned by the script as successfully handled.
+		for _, m := range metrics {
+			if !returned[m] {
+				m.Accept()
+			}
+		}
+	case starlark.NoneType:
+		for _, m := range metrics {
+			m.Drop()
+		}
+	default:
+		for _, m := range metrics {
+			m.Drop()
+		}
+		return fmt.Errorf("invalid type returned: %T", rv)
+	}
+	return nil
+}
+
 func (s *Starlark) Stop() {
 }
 
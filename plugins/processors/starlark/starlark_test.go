@@ -238,6 +238,126 @@ def apply(metric):
 	}
 }
 
+func TestApplyBatch(t *testing.T) {
+	// Tests for the behavior of the processors AddBatch function.
+	var batchTests = []struct {
+		name     string
+		source   string
+		expected []telegraf.Metric
+	}{
+		{
+			name: "keep latest metric per series",
+			source: `
+def apply_batch(metrics):
+	latest = {}
+	for metric in metrics:
+		key = (metric.name, metric.tags.get("host"))
+		if key not in latest or latest[key].time < metric.time:
+			latest[key] = metric
+	return latest.values()
+`,
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"time_idle": 44},
+					time.Unix(2, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"time_idle": 43},
+					time.Unix(1, 0),
+				),
+			},
+		},
+		{
+			name: "drop batch",
+			source: `
+def apply_batch(metrics):
+	return None
+`,
+			expected: []telegraf.Metric{},
+		},
+		{
+			name: "apply fallback",
+			source: `
+def apply(metric):
+	metric.tags["applied"] = "true"
+	return metric
+`,
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a", "applied": "true"},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b", "applied": "true"},
+					map[string]interface{}{"time_idle": 43},
+					time.Unix(1, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a", "applied": "true"},
+					map[string]interface{}{"time_idle": 44},
+					time.Unix(2, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range batchTests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newStarlarkFromSource(tt.source)
+			require.NoError(t, plugin.Init())
+
+			input := []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"time_idle": 43},
+					time.Unix(1, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"time_idle": 44},
+					time.Unix(2, 0),
+				),
+			}
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Start(&acc))
+			require.NoError(t, plugin.AddBatch(input, &acc))
+			plugin.Stop()
+
+			testutil.RequireMetricsEqual(t, tt.expected, acc.GetTelegrafMetrics(), testutil.SortMetrics())
+		})
+	}
+}
+
+func TestApplyBatchOnly(t *testing.T) {
+	plugin := newStarlarkFromSource(`
+def apply_batch(metrics):
+	return [m for m in metrics if m.fields["value"] > 1]
+`)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": 2}, time.Unix(0, 0))
+	require.NoError(t, plugin.Add(m, &acc))
+	m = testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": 1}, time.Unix(0, 0))
+	require.NoError(t, plugin.Add(m, &acc))
+	plugin.Stop()
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": 2}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
 // Tests for the behavior of the Metric type.
 func TestMetric(t *testing.T) {
 	var tests = []struct {
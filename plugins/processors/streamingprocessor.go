@@ -8,6 +8,9 @@ import (
 // NewStreamingProcessorFromProcessor is a converter that turns a standard
 // processor into a streaming processor
 func NewStreamingProcessorFromProcessor(p telegraf.Processor) telegraf.StreamingProcessor {
+	if _, ok := p.(telegraf.BatchProcessor); ok {
+		return &batchStreamingProcessor{streamingProcessor{processor: p}}
+	}
 	sp := &streamingProcessor{
 		processor: p,
 	}
@@ -59,3 +62,14 @@ func (sp *streamingProcessor) Init() error {
 func (sp *streamingProcessor) Unwrap() telegraf.Processor {
 	return sp.processor
 }
+
+// batchStreamingProcessor wraps processors also implementing the
+// telegraf.BatchProcessor interface. The streamingProcessor is embedded by
+// value to keep its Log field settable by reflection.
+type batchStreamingProcessor struct {
+	streamingProcessor
+}
+
+func (sp *batchStreamingProcessor) AddBatch(metrics []telegraf.Metric, acc telegraf.Accumulator) error {
+	return sp.processor.(telegraf.BatchProcessor).AddBatch(metrics, acc)
+}
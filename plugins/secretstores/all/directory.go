@@ -0,0 +1,5 @@
+//go:build !custom || secretstores || secretstores.directory
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/secretstores/directory" // register plugin
@@ -0,0 +1,207 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package directory
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/secretstores"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+// Characters not allowed in secret keys
+var invalidKeyChars = regexp.MustCompile(`\W`)
+
+type Directory struct {
+	ID             string          `toml:"id"`
+	Path           string          `toml:"path"`
+	TrimWhitespace bool            `toml:"trim_whitespace"`
+	Watch          bool            `toml:"watch"`
+	Log            telegraf.Logger `toml:"-"`
+
+	// Content of the watched files
+	mu    sync.Mutex
+	cache map[string]*cachedFile
+}
+
+// cachedFile holds the content of a secret file along with the file
+// attributes used to detect changes
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+	value   []byte
+}
+
+func (*Directory) SampleConfig() string {
+	return sampleConfig
+}
+
+// Init initializes all internals of the secret-store
+func (d *Directory) Init() error {
+	if d.ID == "" {
+		return errors.New("id missing")
+	}
+	if d.Path == "" {
+		// Use the credentials passed by systemd via LoadCredential
+		d.Path = os.Getenv("CREDENTIALS_DIRECTORY")
+	}
+	if d.Path == "" {
+		return errors.New("path missing and CREDENTIALS_DIRECTORY not set")
+	}
+
+	info, err := os.Stat(d.Path)
+	if err != nil {
+		return fmt.Errorf("accessing directory %q failed: %w", d.Path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%q is not a directory", d.Path)
+	}
+	d.cache = make(map[string]*cachedFile)
+
+	return nil
+}
+
+// Get searches for the given key and return the secret
+func (d *Directory) Get(key string) ([]byte, error) {
+	files, err := d.files()
+	if err != nil {
+		return nil, err
+	}
+	fn, found := files[key]
+	if !found {
+		return nil, errors.New("not found")
+	}
+
+	if !d.Watch {
+		return d.read(fn)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Only read the file again if it changed
+	info, err := os.Stat(fn)
+	if err != nil {
+		return nil, fmt.Errorf("accessing secret file failed: %w", err)
+	}
+	cached, found := d.cache[fn]
+	if found && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return bytes.Clone(cached.value), nil
+	}
+	if found {
+		d.Log.Debugf("Secret %q changed, reading it again", key)
+	}
+
+	value, err := d.read(fn)
+	if err != nil {
+		return nil, err
+	}
+	d.cache[fn] = &cachedFile{modTime: info.ModTime(), size: info.Size(), value: value}
+	return bytes.Clone(value), nil
+}
+
+// Set sets the given secret for the given key
+func (d *Directory) Set(_, _ string) error {
+	return errors.New("secret-store does not support creating secrets")
+}
+
+// List lists all known secret keys
+func (d *Directory) List() ([]string, error) {
+	files, err := d.files()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(files))
+	for k := range files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// GetResolver returns a function to resolve the given key.
+func (d *Directory) GetResolver(key string) (telegraf.ResolveFunc, error) {
+	resolver := func() ([]byte, bool, error) {
+		s, err := d.Get(key)
+		return s, d.Watch, err
+	}
+	return resolver, nil
+}
+
+// Rotatable returns true if the files are not watched, as the files might be
+// replaced while Telegraf is running.
+func (d *Directory) Rotatable(_ string) bool {
+	return !d.Watch
+}
+
+// Refresh does nothing as the files are read again on every access.
+func (d *Directory) Refresh(_ string) error {
+	return nil
+}
+
+// files returns the secret files in the directory by their key. Hidden files
+// and directories, like the data directories of Kubernetes secret volumes,
+// are skipped. Characters not allowed in keys are replaced by underscores.
+func (d *Directory) files() (map[string]string, error) {
+	entries, err := os.ReadDir(d.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory failed: %w", err)
+	}
+
+	files := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		// Follow symbolic links as used by Kubernetes for atomic updates
+		fn := filepath.Join(d.Path, name)
+		info, err := os.Stat(fn)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		key := invalidKeyChars.ReplaceAllString(name, "_")
+		if other, found := files[key]; found {
+			d.Log.Warnf("Files %q and %q map to the same key %q, ignoring the latter", filepath.Base(other), name, key)
+			continue
+		}
+		files[key] = fn
+	}
+	return files, nil
+}
+
+func (d *Directory) read(fn string) ([]byte, error) {
+	value, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("reading secret file failed: %w", err)
+	}
+	if d.TrimWhitespace {
+		trimmed := append([]byte(nil), bytes.TrimSpace(value)...)
+		config.ReleaseSecret(value)
+		value = trimmed
+	}
+	return value, nil
+}
+
+// Register the secret-store on load.
+func init() {
+	secretstores.Add("directory", func(id string) telegraf.SecretStore {
+		return &Directory{ID: id}
+	})
+}
@@ -0,0 +1,124 @@
+package directory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestSystemdCredentials(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db-password"), []byte("pa$$word\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("mytoken"), 0600))
+	t.Setenv("CREDENTIALS_DIRECTORY", dir)
+
+	plugin := &Directory{ID: "test", TrimWhitespace: true, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+	require.Equal(t, dir, plugin.Path)
+
+	keys, err := plugin.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"db_password", "token"}, keys)
+
+	secret, err := plugin.Get("db_password")
+	require.NoError(t, err)
+	require.Equal(t, "pa$$word", string(secret))
+
+	_, err = plugin.Get("unknown")
+	require.ErrorContains(t, err, "not found")
+	require.ErrorContains(t, plugin.Set("token", "foo"), "not support")
+
+	// Without watching, secrets are resolved once
+	resolver, err := plugin.GetResolver("token")
+	require.NoError(t, err)
+	secret, dynamic, err := resolver()
+	require.NoError(t, err)
+	require.False(t, dynamic)
+	require.Equal(t, "mytoken", string(secret))
+}
+
+func TestKubernetesVolume(t *testing.T) {
+	// Mimic the layout of a secret volume with the data in a hidden,
+	// versioned directory referenced via symbolic links
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..2023_10_17_00_00_00.000000000")
+	require.NoError(t, os.Mkdir(data, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(data, "password"), []byte("secret"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(data, "tls.key"), []byte("key"), 0600))
+	require.NoError(t, os.Symlink(filepath.Base(data), filepath.Join(dir, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "password"), filepath.Join(dir, "password")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "tls.key"), filepath.Join(dir, "tls.key")))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "subdir"), 0700))
+
+	plugin := &Directory{ID: "test", Path: dir, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+
+	keys, err := plugin.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"password", "tls_key"}, keys)
+
+	secret, err := plugin.Get("tls_key")
+	require.NoError(t, err)
+	require.Equal(t, "key", string(secret))
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(fn, []byte("secret"), 0600))
+
+	plugin := &Directory{ID: "test", Path: dir, Watch: true, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+
+	resolver, err := plugin.GetResolver("password")
+	require.NoError(t, err)
+	secret, dynamic, err := resolver()
+	require.NoError(t, err)
+	require.True(t, dynamic)
+	require.Equal(t, "secret", string(secret))
+
+	// Rotated secrets are picked up by the resolver
+	require.NoError(t, os.WriteFile(fn, []byte("rotated secret"), 0600))
+	secret, _, err = resolver()
+	require.NoError(t, err)
+	require.Equal(t, "rotated secret", string(secret))
+
+	require.NoError(t, os.Remove(fn))
+	_, _, err = resolver()
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestKeyCollision(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db-password"), []byte("first"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db.password"), []byte("second"), 0600))
+
+	plugin := &Directory{ID: "test", Path: dir, Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+
+	secret, err := plugin.Get("db_password")
+	require.NoError(t, err)
+	require.Equal(t, "first", string(secret))
+}
+
+func TestInitFail(t *testing.T) {
+	t.Setenv("CREDENTIALS_DIRECTORY", "")
+
+	plugin := &Directory{}
+	require.ErrorContains(t, plugin.Init(), "id missing")
+
+	plugin = &Directory{ID: "test"}
+	require.ErrorContains(t, plugin.Init(), "CREDENTIALS_DIRECTORY not set")
+
+	plugin = &Directory{ID: "test", Path: filepath.Join(t.TempDir(), "missing")}
+	require.ErrorContains(t, plugin.Init(), "accessing directory")
+
+	fn := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(fn, nil, 0600))
+	plugin = &Directory{ID: "test", Path: fn}
+	require.ErrorContains(t, plugin.Init(), "is not a directory")
+}
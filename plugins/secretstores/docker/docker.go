@@ -84,6 +84,17 @@ func (d *Docker) GetResolver(key string) (telegraf.ResolveFunc, error) {
 	return resolver, nil
 }
 
+// Rotatable returns true for non-dynamic secrets, as the secret files might
+// be updated while Telegraf is running.
+func (d *Docker) Rotatable(_ string) bool {
+	return !d.Dynamic
+}
+
+// Refresh does nothing as the secret files are read again on every access.
+func (d *Docker) Refresh(_ string) error {
+	return nil
+}
+
 // Register the secret-store on load.
 func init() {
 	secretstores.Add("docker", func(id string) telegraf.SecretStore {
@@ -0,0 +1,196 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package oauth2
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/secretstores"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+// Keys must be usable in secret references
+var validKey = regexp.MustCompile(`^\w+$`)
+
+type OAuth2 struct {
+	ID           string          `toml:"id"`
+	TokenURL     string          `toml:"token_url"`
+	ExpiryMargin config.Duration `toml:"token_expiry_margin"`
+	Timeout      config.Duration `toml:"timeout"`
+	Tokens       []*TokenConfig  `toml:"token"`
+	Log          telegraf.Logger `toml:"-"`
+	tls.ClientConfig
+
+	sources map[string]oauth2.TokenSource
+}
+
+type TokenConfig struct {
+	Key          string            `toml:"key"`
+	ClientID     config.Secret     `toml:"client_id"`
+	ClientSecret config.Secret     `toml:"client_secret"`
+	Scopes       []string          `toml:"scopes"`
+	Audience     string            `toml:"audience"`
+	Parameters   map[string]string `toml:"parameters"`
+}
+
+func (*OAuth2) SampleConfig() string {
+	return sampleConfig
+}
+
+// Init initializes all internals of the secret-store
+func (o *OAuth2) Init() error {
+	if o.ID == "" {
+		return errors.New("id missing")
+	}
+	if o.TokenURL == "" {
+		return errors.New("token_url missing")
+	}
+	if len(o.Tokens) == 0 {
+		return errors.New("no tokens defined")
+	}
+	if o.ExpiryMargin < 0 {
+		return errors.New("token_expiry_margin must not be negative")
+	}
+	if o.ExpiryMargin == 0 {
+		o.ExpiryMargin = config.Duration(time.Second)
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = config.Duration(5 * time.Second)
+	}
+
+	tlsCfg, err := o.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsCfg,
+		},
+		Timeout: time.Duration(o.Timeout),
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+
+	o.sources = make(map[string]oauth2.TokenSource, len(o.Tokens))
+	for _, cfg := range o.Tokens {
+		if !validKey.MatchString(cfg.Key) {
+			return fmt.Errorf("invalid token key %q", cfg.Key)
+		}
+		if _, found := o.sources[cfg.Key]; found {
+			return fmt.Errorf("duplicate token key %q", cfg.Key)
+		}
+		if cfg.ClientID.Empty() {
+			return fmt.Errorf("client_id missing for token %q", cfg.Key)
+		}
+
+		src := &tokenSource{ctx: ctx, url: o.TokenURL, cfg: cfg}
+		o.sources[cfg.Key] = oauth2.ReuseTokenSourceWithExpiry(nil, src, time.Duration(o.ExpiryMargin))
+	}
+
+	return nil
+}
+
+// Get searches for the given key and return the secret
+func (o *OAuth2) Get(key string) ([]byte, error) {
+	src, found := o.sources[key]
+	if !found {
+		return nil, errors.New("not found")
+	}
+
+	token, err := src.Token()
+	if err != nil {
+		return nil, fmt.Errorf("requesting token failed: %w", err)
+	}
+	return []byte(token.AccessToken), nil
+}
+
+// Set sets the given secret for the given key
+func (o *OAuth2) Set(_, _ string) error {
+	return errors.New("secret-store does not support creating secrets")
+}
+
+// List lists all known secret keys
+func (o *OAuth2) List() ([]string, error) {
+	keys := make([]string, 0, len(o.sources))
+	for k := range o.sources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// GetResolver returns a function to resolve the given key.
+func (o *OAuth2) GetResolver(key string) (telegraf.ResolveFunc, error) {
+	if _, found := o.sources[key]; !found {
+		return nil, errors.New("not found")
+	}
+
+	// Tokens are resolved on every use to refresh them before they expire.
+	// The token sources cache the tokens until then.
+	resolver := func() ([]byte, bool, error) {
+		s, err := o.Get(key)
+		return s, true, err
+	}
+	return resolver, nil
+}
+
+// tokenSource requests new tokens using the client credentials flow. The
+// credentials are read on every request so they can be secrets themselves.
+type tokenSource struct {
+	ctx context.Context
+	url string
+	cfg *TokenConfig
+}
+
+func (s *tokenSource) Token() (*oauth2.Token, error) {
+	id, err := s.cfg.ClientID.Get()
+	if err != nil {
+		return nil, fmt.Errorf("getting client_id failed: %w", err)
+	}
+	defer config.ReleaseSecret(id)
+
+	secret, err := s.cfg.ClientSecret.Get()
+	if err != nil {
+		return nil, fmt.Errorf("getting client_secret failed: %w", err)
+	}
+	defer config.ReleaseSecret(secret)
+
+	params := make(url.Values, len(s.cfg.Parameters)+1)
+	for k, v := range s.cfg.Parameters {
+		params.Set(k, v)
+	}
+	if s.cfg.Audience != "" {
+		params.Set("audience", s.cfg.Audience)
+	}
+
+	cfg := &clientcredentials.Config{
+		ClientID:       string(id),
+		ClientSecret:   string(secret),
+		TokenURL:       s.url,
+		Scopes:         s.cfg.Scopes,
+		EndpointParams: params,
+	}
+	return cfg.Token(s.ctx)
+}
+
+// Register the secret-store on load.
+func init() {
+	secretstores.Add("oauth2", func(id string) telegraf.SecretStore {
+		return &OAuth2{ID: id}
+	})
+}
@@ -0,0 +1,218 @@
+package oauth2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+// tokenServer returns a fake token endpoint issuing tokens with the given
+// lifetime in seconds and counting the issued tokens.
+func tokenServer(t *testing.T, lifetime int, issued *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// The credentials are URL-encoded as required by RFC 6749
+		id, secret, ok := r.BasicAuth()
+		if ok {
+			id, _ = url.QueryUnescape(id)
+			secret, _ = url.QueryUnescape(secret)
+		}
+		if !ok || id != "telegraf" || secret != "pa$$word" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		n := atomic.AddInt32(issued, 1)
+		token := fmt.Sprintf("token-%d;scope=%s;audience=%s;resource=%s",
+			n, r.PostForm.Get("scope"), r.PostForm.Get("audience"), r.PostForm.Get("resource"))
+		w.Header().Set("Content-Type", "application/json")
+		_, err := fmt.Fprintf(w, `{"access_token":%q,"token_type":"bearer","expires_in":%d}`, token, lifetime)
+		require.NoError(t, err)
+	}))
+}
+
+func TestGet(t *testing.T) {
+	var issued int32
+	server := tokenServer(t, 3600, &issued)
+	defer server.Close()
+
+	plugin := &OAuth2{
+		ID:       "test",
+		TokenURL: server.URL,
+		Tokens: []*TokenConfig{
+			{
+				Key:          "influxdb",
+				ClientID:     config.NewSecret([]byte("telegraf")),
+				ClientSecret: config.NewSecret([]byte("pa$$word")),
+				Scopes:       []string{"read", "write"},
+				Audience:     "metrics",
+			},
+			{
+				Key:          "azure",
+				ClientID:     config.NewSecret([]byte("telegraf")),
+				ClientSecret: config.NewSecret([]byte("pa$$word")),
+				Parameters:   map[string]string{"resource": "monitoring"},
+			},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	keys, err := plugin.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"azure", "influxdb"}, keys)
+
+	secret, err := plugin.Get("influxdb")
+	require.NoError(t, err)
+	require.Equal(t, "token-1;scope=read write;audience=metrics;resource=", string(secret))
+
+	// The token is cached until it expires
+	resolver, err := plugin.GetResolver("influxdb")
+	require.NoError(t, err)
+	secret, dynamic, err := resolver()
+	require.NoError(t, err)
+	require.True(t, dynamic)
+	require.Equal(t, "token-1;scope=read write;audience=metrics;resource=", string(secret))
+
+	secret, err = plugin.Get("azure")
+	require.NoError(t, err)
+	require.Equal(t, "token-2;scope=;audience=;resource=monitoring", string(secret))
+	require.EqualValues(t, 2, atomic.LoadInt32(&issued))
+
+	_, err = plugin.Get("unknown")
+	require.ErrorContains(t, err, "not found")
+	_, err = plugin.GetResolver("unknown")
+	require.ErrorContains(t, err, "not found")
+	require.ErrorContains(t, plugin.Set("influxdb", "foo"), "not support")
+}
+
+func TestRefresh(t *testing.T) {
+	var issued int32
+	server := tokenServer(t, 60, &issued)
+	defer server.Close()
+
+	plugin := &OAuth2{
+		ID:           "test",
+		TokenURL:     server.URL,
+		ExpiryMargin: config.Duration(time.Hour),
+		Tokens: []*TokenConfig{
+			{
+				Key:          "token",
+				ClientID:     config.NewSecret([]byte("telegraf")),
+				ClientSecret: config.NewSecret([]byte("pa$$word")),
+			},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	// Tokens expiring within the margin are requested again on every use
+	resolver, err := plugin.GetResolver("token")
+	require.NoError(t, err)
+	secret, _, err := resolver()
+	require.NoError(t, err)
+	require.Equal(t, "token-1;scope=;audience=;resource=", string(secret))
+	secret, _, err = resolver()
+	require.NoError(t, err)
+	require.Equal(t, "token-2;scope=;audience=;resource=", string(secret))
+}
+
+func TestInvalidCredentials(t *testing.T) {
+	var issued int32
+	server := tokenServer(t, 3600, &issued)
+	defer server.Close()
+
+	plugin := &OAuth2{
+		ID:       "test",
+		TokenURL: server.URL,
+		Tokens: []*TokenConfig{
+			{
+				Key:          "token",
+				ClientID:     config.NewSecret([]byte("telegraf")),
+				ClientSecret: config.NewSecret([]byte("wrong")),
+			},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	_, err := plugin.Get("token")
+	require.ErrorContains(t, err, "requesting token failed")
+	require.Zero(t, atomic.LoadInt32(&issued))
+}
+
+func TestInitFail(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *OAuth2
+		expected string
+	}{
+		{
+			name:     "missing id",
+			plugin:   &OAuth2{},
+			expected: "id missing",
+		},
+		{
+			name:     "missing url",
+			plugin:   &OAuth2{ID: "test"},
+			expected: "token_url missing",
+		},
+		{
+			name:     "no tokens",
+			plugin:   &OAuth2{ID: "test", TokenURL: "http://localhost"},
+			expected: "no tokens defined",
+		},
+		{
+			name: "invalid key",
+			plugin: &OAuth2{
+				ID:       "test",
+				TokenURL: "http://localhost",
+				Tokens:   []*TokenConfig{{Key: "my-token", ClientID: config.NewSecret([]byte("id"))}},
+			},
+			expected: `invalid token key "my-token"`,
+		},
+		{
+			name: "duplicate key",
+			plugin: &OAuth2{
+				ID:       "test",
+				TokenURL: "http://localhost",
+				Tokens: []*TokenConfig{
+					{Key: "token", ClientID: config.NewSecret([]byte("id"))},
+					{Key: "token", ClientID: config.NewSecret([]byte("id"))},
+				},
+			},
+			expected: `duplicate token key "token"`,
+		},
+		{
+			name: "missing client id",
+			plugin: &OAuth2{
+				ID:       "test",
+				TokenURL: "http://localhost",
+				Tokens:   []*TokenConfig{{Key: "token"}},
+			},
+			expected: `client_id missing for token "token"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.plugin.Init(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/influxdata/telegraf/config"
+)
+
+// secretResponse is the generic response of the Vault API for secrets
+type secretResponse struct {
+	LeaseID       string          `json:"lease_id"`
+	LeaseDuration int             `json:"lease_duration"`
+	Renewable     bool            `json:"renewable"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// authResponse is the response of the Vault API for logins and renewals
+type authResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+// apiError is returned for requests failing with an error status
+type apiError struct {
+	status int
+	errors []string
+}
+
+func (e *apiError) Error() string {
+	if len(e.errors) == 0 {
+		return fmt.Sprintf("received status code %d (%s)", e.status, http.StatusText(e.status))
+	}
+	return fmt.Sprintf("received status code %d (%s): %s", e.status, http.StatusText(e.status), strings.Join(e.errors, "; "))
+}
+
+// request sends an authenticated request to the Vault API and decodes the
+// response into the given result if not nil. Must be called with the lock
+// held.
+func (v *Vault) request(method, path string, body, result interface{}) error {
+	if v.AuthMethod == "token" {
+		token, err := v.Token.Get()
+		if err != nil {
+			return fmt.Errorf("getting token failed: %w", err)
+		}
+		defer config.ReleaseSecret(token)
+		return v.send(method, path, string(token), body, result)
+	}
+
+	if err := v.authenticate(); err != nil {
+		return err
+	}
+	err := v.send(method, path, v.login.token, body, result)
+
+	// The token might have been revoked, so try again with a new one
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusForbidden {
+		v.login = nil
+		if err := v.authenticate(); err != nil {
+			return err
+		}
+		return v.send(method, path, v.login.token, body, result)
+	}
+	return err
+}
+
+// authenticate logs in to Vault if there is no valid token. Renewable tokens
+// are renewed before they expire. Must be called with the lock held.
+func (v *Vault) authenticate() error {
+	now := v.now()
+	if v.login != nil && !v.login.expired(now) {
+		return nil
+	}
+
+	if v.login != nil && v.login.renewable {
+		var resp authResponse
+		err := v.send(http.MethodPost, "auth/token/renew-self", v.login.token, struct{}{}, &resp)
+		if err == nil && resp.Auth.LeaseDuration > 0 {
+			v.login = newLease(resp.Auth.LeaseDuration, now)
+			v.login.token = resp.Auth.ClientToken
+			v.login.renewable = resp.Auth.Renewable
+			return nil
+		}
+		if err != nil {
+			v.Log.Debugf("Renewing token failed, logging in again: %v", err)
+		}
+	}
+
+	body, err := v.loginData()
+	if err != nil {
+		return err
+	}
+	var resp authResponse
+	if err := v.send(http.MethodPost, "auth/"+v.AuthMount+"/login", "", body, &resp); err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return errors.New("login failed: no token received")
+	}
+	v.login = newLease(resp.Auth.LeaseDuration, now)
+	v.login.token = resp.Auth.ClientToken
+	v.login.renewable = resp.Auth.Renewable
+
+	return nil
+}
+
+func (v *Vault) loginData() (map[string]string, error) {
+	switch v.AuthMethod {
+	case "approle":
+		roleID, err := v.RoleID.Get()
+		if err != nil {
+			return nil, fmt.Errorf("getting role_id failed: %w", err)
+		}
+		defer config.ReleaseSecret(roleID)
+		data := map[string]string{"role_id": string(roleID)}
+
+		if !v.SecretID.Empty() {
+			secretID, err := v.SecretID.Get()
+			if err != nil {
+				return nil, fmt.Errorf("getting secret_id failed: %w", err)
+			}
+			defer config.ReleaseSecret(secretID)
+			data["secret_id"] = string(secretID)
+		}
+		return data, nil
+	case "kubernetes":
+		jwt, err := os.ReadFile(v.KubernetesTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading kubernetes token failed: %w", err)
+		}
+		return map[string]string{
+			"role": v.KubernetesRole,
+			"jwt":  strings.TrimSpace(string(jwt)),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported auth_method %q", v.AuthMethod)
+}
+
+// send sends a request with the given token to the Vault API.
+func (v *Vault) send(method, path, token string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request failed: %w", err)
+		}
+		reader = bytes.NewReader(buf)
+	}
+
+	u := strings.TrimRight(v.URL, "/") + "/v1/" + path
+	request, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return fmt.Errorf("creating request failed: %w", err)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		request.Header.Set("X-Vault-Token", token)
+	}
+	if v.Namespace != "" {
+		request.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	resp, err := v.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("executing request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var msg struct {
+			Errors []string `json:"errors"`
+		}
+		// The error messages are optional, so ignore decoding errors
+		_ = json.NewDecoder(resp.Body).Decode(&msg)
+		return &apiError{status: resp.StatusCode, errors: msg.Errors}
+	}
+
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding response failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,440 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package vault
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/secretstores"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type Vault struct {
+	ID                  string            `toml:"id"`
+	URL                 string            `toml:"url"`
+	Namespace           string            `toml:"namespace"`
+	AuthMethod          string            `toml:"auth_method"`
+	AuthMount           string            `toml:"auth_mount"`
+	Token               config.Secret     `toml:"token"`
+	RoleID              config.Secret     `toml:"role_id"`
+	SecretID            config.Secret     `toml:"secret_id"`
+	KubernetesRole      string            `toml:"kubernetes_role"`
+	KubernetesTokenFile string            `toml:"kubernetes_token_file"`
+	Mount               string            `toml:"mount"`
+	KVVersion           int               `toml:"kv_version"`
+	Path                string            `toml:"path"`
+	TransitMount        string            `toml:"transit_mount"`
+	TransitKey          string            `toml:"transit_key"`
+	Dynamic             map[string]string `toml:"dynamic"`
+	Timeout             config.Duration   `toml:"timeout"`
+	Log                 telegraf.Logger   `toml:"-"`
+	tls.ClientConfig
+
+	client  *http.Client
+	now     func() time.Time
+	dynamic map[string]dynamicRef
+
+	// State shared by all resolvers
+	mu     sync.Mutex
+	login  *lease
+	kv     map[string]string
+	leases map[string]*lease
+}
+
+// dynamicRef references a field of a dynamic secret
+type dynamicRef struct {
+	path  string
+	field string
+}
+
+// lease holds data obtained from Vault which needs to be refreshed after the
+// given time. A zero refresh time denotes data without expiry.
+type lease struct {
+	data      map[string]string
+	token     string
+	renewable bool
+	refresh   time.Time
+}
+
+func newLease(duration int, now time.Time) *lease {
+	l := &lease{}
+	if duration > 0 {
+		// Refresh the data before it actually expires to avoid using
+		// outdated secrets due to latency or clock skew.
+		l.refresh = now.Add(time.Duration(duration) * time.Second * 9 / 10)
+	}
+	return l
+}
+
+func (l *lease) expired(now time.Time) bool {
+	return !l.refresh.IsZero() && !now.Before(l.refresh)
+}
+
+func (*Vault) SampleConfig() string {
+	return sampleConfig
+}
+
+// Init initializes all internals of the secret-store
+func (v *Vault) Init() error {
+	if v.ID == "" {
+		return errors.New("id missing")
+	}
+	if v.URL == "" {
+		return errors.New("url missing")
+	}
+
+	switch v.AuthMethod {
+	case "", "token":
+		v.AuthMethod = "token"
+		if v.Token.Empty() {
+			return errors.New("token missing")
+		}
+	case "approle":
+		if v.RoleID.Empty() {
+			return errors.New("role_id missing")
+		}
+		if v.AuthMount == "" {
+			v.AuthMount = "approle"
+		}
+	case "kubernetes":
+		if v.KubernetesRole == "" {
+			return errors.New("kubernetes_role missing")
+		}
+		if v.KubernetesTokenFile == "" {
+			v.KubernetesTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+		}
+		if v.AuthMount == "" {
+			v.AuthMount = "kubernetes"
+		}
+	default:
+		return fmt.Errorf("invalid auth_method %q", v.AuthMethod)
+	}
+
+	if v.Mount == "" {
+		v.Mount = "secret"
+	}
+	switch v.KVVersion {
+	case 0:
+		v.KVVersion = 2
+	case 1, 2:
+	default:
+		return fmt.Errorf("invalid kv_version %d", v.KVVersion)
+	}
+	if v.TransitKey != "" && v.TransitMount == "" {
+		v.TransitMount = "transit"
+	}
+
+	if v.Path == "" && len(v.Dynamic) == 0 {
+		return errors.New("either path or dynamic secrets required")
+	}
+	v.dynamic = make(map[string]dynamicRef, len(v.Dynamic))
+	for key, ref := range v.Dynamic {
+		path, field, found := strings.Cut(ref, "#")
+		if !found || path == "" || field == "" {
+			return fmt.Errorf("invalid reference %q for dynamic secret %q, expected <path>#<field>", ref, key)
+		}
+		v.dynamic[key] = dynamicRef{path: strings.Trim(path, "/"), field: field}
+	}
+
+	tlsCfg, err := v.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+	if v.Timeout <= 0 {
+		v.Timeout = config.Duration(5 * time.Second)
+	}
+	v.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsCfg,
+		},
+		Timeout: time.Duration(v.Timeout),
+	}
+	v.now = time.Now
+	v.leases = make(map[string]*lease)
+
+	return nil
+}
+
+// Get searches for the given key and return the secret
+func (v *Vault) Get(key string) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if ref, found := v.dynamic[key]; found {
+		value, err := v.readDynamic(ref)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(value), nil
+	}
+
+	if err := v.loadKV(); err != nil {
+		return nil, err
+	}
+	value, found := v.kv[key]
+	if !found {
+		return nil, errors.New("not found")
+	}
+	return v.decrypt(value)
+}
+
+// Set sets the given secret for the given key
+func (v *Vault) Set(key, value string) error {
+	if _, found := v.dynamic[key]; found {
+		return errors.New("setting dynamic secrets not supported")
+	}
+	if v.Path == "" {
+		return errors.New("setting secrets requires a path")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.TransitKey != "" {
+		ciphertext, err := v.encrypt(value)
+		if err != nil {
+			return err
+		}
+		value = ciphertext
+	}
+
+	// Writing replaces the whole secret, so merge the key into the
+	// current data
+	data, err := v.readKV()
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+		data = make(map[string]json.RawMessage)
+	} else if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	data[key] = raw
+
+	var body interface{} = data
+	if v.KVVersion == 2 {
+		body = map[string]interface{}{"data": data}
+	}
+	if err := v.request(http.MethodPost, v.kvPath(), body, nil); err != nil {
+		return fmt.Errorf("writing secret failed: %w", err)
+	}
+
+	if v.kv != nil {
+		v.kv[key] = value
+	}
+	return nil
+}
+
+// List lists all known secret keys
+func (v *Vault) List() ([]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.loadKV(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(v.kv)+len(v.dynamic))
+	for k := range v.kv {
+		if _, found := v.dynamic[k]; !found {
+			keys = append(keys, k)
+		}
+	}
+	for k := range v.dynamic {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// GetResolver returns a function to resolve the given key.
+func (v *Vault) GetResolver(key string) (telegraf.ResolveFunc, error) {
+	// Dynamic secrets are resolved on every use to pick up new credentials
+	// once the lease expired.
+	if _, found := v.dynamic[key]; found {
+		resolver := func() ([]byte, bool, error) {
+			s, err := v.Get(key)
+			return s, true, err
+		}
+		return resolver, nil
+	}
+
+	// Read the static secrets to detect errors early
+	v.mu.Lock()
+	err := v.loadKV()
+	v.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := func() ([]byte, bool, error) {
+		s, err := v.Get(key)
+		return s, false, err
+	}
+	return resolver, nil
+}
+
+// Rotatable returns true for keys of the KV secret as those might be changed
+// in Vault. Dynamic secrets are resolved on every use anyway.
+func (v *Vault) Rotatable(key string) bool {
+	_, found := v.dynamic[key]
+	return !found
+}
+
+// Refresh discards the cached KV secret so it is read again on next access.
+func (v *Vault) Refresh(_ string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.kv = nil
+	return nil
+}
+
+// loadKV reads the KV secret if not done already. The values are kept
+// encrypted if a transit key is configured. Must be called with the lock
+// held.
+func (v *Vault) loadKV() error {
+	if v.kv != nil || v.Path == "" {
+		return nil
+	}
+
+	data, err := v.readKV()
+	if err != nil {
+		return fmt.Errorf("reading secret failed: %w", err)
+	}
+	v.kv = stringValues(data)
+	return nil
+}
+
+func (v *Vault) readKV() (map[string]json.RawMessage, error) {
+	var resp secretResponse
+	if err := v.request(http.MethodGet, v.kvPath(), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	// Version 2 of the KV engine wraps the data alongside the metadata
+	data := resp.Data
+	if v.KVVersion == 2 {
+		var versioned struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &versioned); err != nil {
+			return nil, err
+		}
+		data = versioned.Data
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = make(map[string]json.RawMessage)
+	}
+	return values, nil
+}
+
+func (v *Vault) kvPath() string {
+	path := strings.Trim(v.Path, "/")
+	if v.KVVersion == 1 {
+		return v.Mount + "/" + path
+	}
+	return v.Mount + "/data/" + path
+}
+
+// readDynamic returns the field of a dynamic secret, reading the secret
+// again if the lease expired. Must be called with the lock held.
+func (v *Vault) readDynamic(ref dynamicRef) (string, error) {
+	now := v.now()
+	l, found := v.leases[ref.path]
+	if !found || l.expired(now) {
+		var resp secretResponse
+		if err := v.request(http.MethodGet, ref.path, nil, &resp); err != nil {
+			return "", fmt.Errorf("reading dynamic secret failed: %w", err)
+		}
+		var data map[string]json.RawMessage
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return "", fmt.Errorf("decoding dynamic secret failed: %w", err)
+		}
+		l = newLease(resp.LeaseDuration, now)
+		l.data = stringValues(data)
+		v.leases[ref.path] = l
+	}
+
+	value, found := l.data[ref.field]
+	if !found {
+		return "", fmt.Errorf("field %q not found in %q", ref.field, ref.path)
+	}
+	return value, nil
+}
+
+func (v *Vault) decrypt(value string) ([]byte, error) {
+	if v.TransitKey == "" {
+		return []byte(value), nil
+	}
+
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"ciphertext": value}
+	if err := v.request(http.MethodPost, v.TransitMount+"/decrypt/"+v.TransitKey, body, &resp); err != nil {
+		return nil, fmt.Errorf("decrypting secret failed: %w", err)
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("base64 decoding failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (v *Vault) encrypt(value string) (string, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString([]byte(value))}
+	if err := v.request(http.MethodPost, v.TransitMount+"/encrypt/"+v.TransitKey, body, &resp); err != nil {
+		return "", fmt.Errorf("encrypting secret failed: %w", err)
+	}
+	return resp.Data.Ciphertext, nil
+}
+
+// stringValues converts the secret data to strings. Values which are not
+// JSON strings, e.g. numbers, are used verbatim.
+func stringValues(data map[string]json.RawMessage) map[string]string {
+	values := make(map[string]string, len(data))
+	for k, raw := range data {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			s = string(raw)
+		}
+		values[k] = s
+	}
+	return values
+}
+
+// Register the secret-store on load.
+func init() {
+	secretstores.Add("vault", func(id string) telegraf.SecretStore {
+		return &Vault{ID: id}
+	})
+}
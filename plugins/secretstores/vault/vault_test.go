@@ -0,0 +1,467 @@
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+// fakeVault is a minimal stand-in for the Vault API
+type fakeVault struct {
+	t *testing.T
+
+	sync.Mutex
+	kv         map[string]map[string]interface{}
+	tokens     map[string]bool
+	logins     int
+	renewals   int
+	renewFails bool
+	reads      map[string]int
+}
+
+func newFakeVault(t *testing.T) *fakeVault {
+	return &fakeVault{
+		t:      t,
+		kv:     make(map[string]map[string]interface{}),
+		tokens: map[string]bool{"root": true},
+		reads:  make(map[string]int),
+	}
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	var body map[string]interface{}
+	if r.Body != nil && r.Method == http.MethodPost {
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+	}
+
+	reply := func(code int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		require.NoError(f.t, json.NewEncoder(w).Encode(v))
+	}
+	fail := func(code int, msg string) {
+		reply(code, map[string]interface{}{"errors": []string{msg}})
+	}
+	login := func() {
+		f.logins++
+		token := fmt.Sprintf("token%d", f.logins)
+		f.tokens[token] = true
+		reply(http.StatusOK, map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": token, "lease_duration": 100, "renewable": true},
+		})
+	}
+
+	switch r.URL.Path {
+	case "/v1/auth/approle/login":
+		if body["role_id"] != "myrole" || body["secret_id"] != "mysecret" {
+			fail(http.StatusBadRequest, "invalid role or secret ID")
+			return
+		}
+		login()
+		return
+	case "/v1/auth/k8s/login":
+		if body["role"] != "telegraf" || body["jwt"] != "myjwt" {
+			fail(http.StatusBadRequest, "invalid role or jwt")
+			return
+		}
+		login()
+		return
+	}
+
+	token := r.Header.Get("X-Vault-Token")
+	if !f.tokens[token] {
+		fail(http.StatusForbidden, "permission denied")
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/auth/token/renew-self":
+		f.renewals++
+		if f.renewFails {
+			fail(http.StatusBadRequest, "token not renewable")
+			return
+		}
+		reply(http.StatusOK, map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": token, "lease_duration": 100, "renewable": true},
+		})
+	case "/v1/secret/data/telegraf":
+		switch r.Method {
+		case http.MethodGet:
+			data, found := f.kv["telegraf"]
+			if !found {
+				fail(http.StatusNotFound, "")
+				return
+			}
+			reply(http.StatusOK, map[string]interface{}{
+				"data": map[string]interface{}{"data": data, "metadata": map[string]interface{}{"version": 1}},
+			})
+		case http.MethodPost:
+			f.kv["telegraf"] = body["data"].(map[string]interface{})
+			reply(http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"version": 2}})
+		}
+	case "/v1/kv/telegraf":
+		reply(http.StatusOK, map[string]interface{}{"data": f.kv["telegraf"]})
+	case "/v1/transit/decrypt/mykey":
+		ciphertext := body["ciphertext"].(string)
+		var plaintext string
+		if _, err := fmt.Sscanf(ciphertext, "vault:v1:%s", &plaintext); err != nil {
+			fail(http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		reply(http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString([]byte(plaintext))},
+		})
+	case "/v1/database/creds/telegraf":
+		f.reads[r.URL.Path]++
+		n := f.reads[r.URL.Path]
+		reply(http.StatusOK, map[string]interface{}{
+			"lease_id":       fmt.Sprintf("database/creds/telegraf/%d", n),
+			"lease_duration": 60,
+			"renewable":      true,
+			"data": map[string]interface{}{
+				"username": fmt.Sprintf("user%d", n),
+				"password": fmt.Sprintf("pass%d", n),
+			},
+		})
+	default:
+		fail(http.StatusNotFound, "")
+	}
+}
+
+func TestKVVersion2(t *testing.T) {
+	fake := newFakeVault(t)
+	fake.kv["telegraf"] = map[string]interface{}{"username": "user", "port": 5432}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	plugin := &Vault{
+		ID:    "test",
+		URL:   server.URL,
+		Token: config.NewSecret([]byte("root")),
+		Path:  "telegraf",
+		Log:   testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	secret, err := plugin.Get("username")
+	require.NoError(t, err)
+	require.Equal(t, "user", string(secret))
+	secret, err = plugin.Get("port")
+	require.NoError(t, err)
+	require.Equal(t, "5432", string(secret))
+	_, err = plugin.Get("unknown")
+	require.ErrorContains(t, err, "not found")
+
+	keys, err := plugin.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"port", "username"}, keys)
+
+	// Setting a secret keeps the other keys
+	require.NoError(t, plugin.Set("password", "pa$$word"))
+	require.Equal(t, map[string]interface{}{"username": "user", "port": 5432.0, "password": "pa$$word"}, fake.kv["telegraf"])
+	secret, err = plugin.Get("password")
+	require.NoError(t, err)
+	require.Equal(t, "pa$$word", string(secret))
+
+	resolver, err := plugin.GetResolver("username")
+	require.NoError(t, err)
+	secret, dynamic, err := resolver()
+	require.NoError(t, err)
+	require.False(t, dynamic)
+	require.Equal(t, "user", string(secret))
+}
+
+func TestRefresh(t *testing.T) {
+	fake := newFakeVault(t)
+	fake.kv["telegraf"] = map[string]interface{}{"password": "pa$$word"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	plugin := &Vault{
+		ID:      "test",
+		URL:     server.URL,
+		Token:   config.NewSecret([]byte("root")),
+		Path:    "telegraf",
+		Dynamic: map[string]string{"db_password": "database/creds/telegraf#password"},
+		Log:     testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.True(t, plugin.Rotatable("password"))
+	require.False(t, plugin.Rotatable("db_password"))
+
+	secret, err := plugin.Get("password")
+	require.NoError(t, err)
+	require.Equal(t, "pa$$word", string(secret))
+
+	// Rotated secrets are only read after refreshing
+	fake.Lock()
+	fake.kv["telegraf"] = map[string]interface{}{"password": "rotated"}
+	fake.Unlock()
+	secret, err = plugin.Get("password")
+	require.NoError(t, err)
+	require.Equal(t, "pa$$word", string(secret))
+
+	require.NoError(t, plugin.Refresh("password"))
+	secret, err = plugin.Get("password")
+	require.NoError(t, err)
+	require.Equal(t, "rotated", string(secret))
+}
+
+func TestKVVersion1AppRoleTransit(t *testing.T) {
+	fake := newFakeVault(t)
+	fake.kv["telegraf"] = map[string]interface{}{"password": "vault:v1:secret"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	plugin := &Vault{
+		ID:         "test",
+		URL:        server.URL,
+		AuthMethod: "approle",
+		RoleID:     config.NewSecret([]byte("myrole")),
+		SecretID:   config.NewSecret([]byte("mysecret")),
+		Mount:      "kv",
+		KVVersion:  1,
+		Path:       "telegraf",
+		TransitKey: "mykey",
+		Log:        testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	secret, err := plugin.Get("password")
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(secret))
+	require.Equal(t, 1, fake.logins)
+}
+
+func TestKubernetesAuth(t *testing.T) {
+	fake := newFakeVault(t)
+	fake.kv["telegraf"] = map[string]interface{}{"username": "user"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("myjwt\n"), 0600))
+
+	plugin := &Vault{
+		ID:                  "test",
+		URL:                 server.URL,
+		AuthMethod:          "kubernetes",
+		AuthMount:           "k8s",
+		KubernetesRole:      "telegraf",
+		KubernetesTokenFile: tokenFile,
+		Path:                "telegraf",
+		Log:                 testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	secret, err := plugin.Get("username")
+	require.NoError(t, err)
+	require.Equal(t, "user", string(secret))
+	require.Equal(t, 1, fake.logins)
+}
+
+func TestTokenRenewal(t *testing.T) {
+	fake := newFakeVault(t)
+	fake.kv["telegraf"] = map[string]interface{}{"username": "user"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	plugin := &Vault{
+		ID:         "test",
+		URL:        server.URL,
+		AuthMethod: "approle",
+		RoleID:     config.NewSecret([]byte("myrole")),
+		SecretID:   config.NewSecret([]byte("mysecret")),
+		Path:       "telegraf",
+		Log:        testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	now := time.Now()
+	plugin.now = func() time.Time { return now }
+
+	_, err := plugin.Get("username")
+	require.NoError(t, err)
+	require.Equal(t, 1, fake.logins)
+
+	// The token is renewed shortly before it expires
+	plugin.kv = nil
+	now = now.Add(95 * time.Second)
+	_, err = plugin.Get("username")
+	require.NoError(t, err)
+	require.Equal(t, 1, fake.logins)
+	require.Equal(t, 1, fake.renewals)
+
+	// If the renewal fails, a new token is requested
+	fake.renewFails = true
+	plugin.kv = nil
+	now = now.Add(95 * time.Second)
+	_, err = plugin.Get("username")
+	require.NoError(t, err)
+	require.Equal(t, 2, fake.logins)
+	require.Equal(t, 2, fake.renewals)
+
+	// Revoked tokens are replaced
+	delete(fake.tokens, "token2")
+	plugin.kv = nil
+	_, err = plugin.Get("username")
+	require.NoError(t, err)
+	require.Equal(t, 3, fake.logins)
+}
+
+func TestDynamicSecrets(t *testing.T) {
+	fake := newFakeVault(t)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	plugin := &Vault{
+		ID:    "test",
+		URL:   server.URL,
+		Token: config.NewSecret([]byte("root")),
+		Dynamic: map[string]string{
+			"db_username": "database/creds/telegraf#username",
+			"db_password": "database/creds/telegraf#password",
+			"db_invalid":  "database/creds/telegraf#invalid",
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	now := time.Now()
+	plugin.now = func() time.Time { return now }
+
+	username, err := plugin.GetResolver("db_username")
+	require.NoError(t, err)
+	password, err := plugin.GetResolver("db_password")
+	require.NoError(t, err)
+
+	// Fields of the same secret share the lease
+	secret, dynamic, err := username()
+	require.NoError(t, err)
+	require.True(t, dynamic)
+	require.Equal(t, "user1", string(secret))
+	secret, _, err = password()
+	require.NoError(t, err)
+	require.Equal(t, "pass1", string(secret))
+
+	// The secret is read again once the lease expires
+	now = now.Add(30 * time.Second)
+	secret, _, err = username()
+	require.NoError(t, err)
+	require.Equal(t, "user1", string(secret))
+	now = now.Add(30 * time.Second)
+	secret, _, err = username()
+	require.NoError(t, err)
+	require.Equal(t, "user2", string(secret))
+	secret, _, err = password()
+	require.NoError(t, err)
+	require.Equal(t, "pass2", string(secret))
+	require.Equal(t, 2, fake.reads["/v1/database/creds/telegraf"])
+
+	_, err = plugin.Get("db_invalid")
+	require.ErrorContains(t, err, `field "invalid" not found`)
+	require.ErrorContains(t, plugin.Set("db_username", "user"), "not supported")
+
+	keys, err := plugin.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"db_invalid", "db_password", "db_username"}, keys)
+}
+
+func TestAuthFailure(t *testing.T) {
+	fake := newFakeVault(t)
+	fake.kv["telegraf"] = map[string]interface{}{"username": "user"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	plugin := &Vault{
+		ID:    "test",
+		URL:   server.URL,
+		Token: config.NewSecret([]byte("invalid")),
+		Path:  "telegraf",
+		Log:   testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	_, err := plugin.GetResolver("username")
+	require.ErrorContains(t, err, "received status code 403 (Forbidden): permission denied")
+}
+
+func TestInitFail(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Vault
+		expected string
+	}{
+		{
+			name:     "missing id",
+			plugin:   &Vault{},
+			expected: "id missing",
+		},
+		{
+			name:     "missing url",
+			plugin:   &Vault{ID: "test"},
+			expected: "url missing",
+		},
+		{
+			name:     "missing token",
+			plugin:   &Vault{ID: "test", URL: "http://localhost:8200", Path: "telegraf"},
+			expected: "token missing",
+		},
+		{
+			name:     "invalid auth method",
+			plugin:   &Vault{ID: "test", URL: "http://localhost:8200", AuthMethod: "ldap"},
+			expected: `invalid auth_method "ldap"`,
+		},
+		{
+			name: "invalid kv version",
+			plugin: &Vault{
+				ID:             "test",
+				URL:            "http://localhost:8200",
+				AuthMethod:     "kubernetes",
+				KubernetesRole: "telegraf",
+				KVVersion:      3,
+			},
+			expected: "invalid kv_version 3",
+		},
+		{
+			name: "no secrets",
+			plugin: &Vault{
+				ID:             "test",
+				URL:            "http://localhost:8200",
+				AuthMethod:     "kubernetes",
+				KubernetesRole: "telegraf",
+			},
+			expected: "either path or dynamic secrets required",
+		},
+		{
+			name: "invalid dynamic reference",
+			plugin: &Vault{
+				ID:             "test",
+				URL:            "http://localhost:8200",
+				AuthMethod:     "kubernetes",
+				KubernetesRole: "telegraf",
+				Dynamic:        map[string]string{"db": "database/creds/telegraf"},
+			},
+			expected: `invalid reference "database/creds/telegraf" for dynamic secret "db"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.plugin.Init(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package avro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+// Timeout for requests to the schema registry
+const registryTimeout = 10 * time.Second
+
+// Delay before retrying to register a schema after a failure
+const registryRetryDelay = time.Minute
+
+// If SchemaRegistry is set, the output is in the Confluent Wire Format
+// (https://docs.confluent.io/platform/current/schema-registry/serdes-develop/index.html#wire-format)
+// with the schema registered in the registry. Otherwise the output is Avro
+// binary format without the schema.
+
+// If Schema is not set, a schema is derived for each metric from its name,
+// tags and fields.
+
+type Serializer struct {
+	SchemaRegistry  string `toml:"avro_schema_registry"`
+	Subject         string `toml:"avro_schema_subject"`
+	Schema          string `toml:"avro_schema"`
+	Namespace       string `toml:"avro_namespace"`
+	Timestamp       string `toml:"avro_timestamp"`
+	TimestampFormat string `toml:"avro_timestamp_format"`
+
+	registry *schemaRegistry
+	schema   *schema
+	derived  map[string]*schema
+}
+
+func (s *Serializer) Init() error {
+	if s.Timestamp == "" && s.Schema == "" {
+		s.Timestamp = "timestamp"
+	}
+	if s.Timestamp != "" && sanitize(s.Timestamp) != s.Timestamp {
+		return fmt.Errorf("invalid timestamp field name %q", s.Timestamp)
+	}
+
+	switch s.TimestampFormat {
+	case "":
+		s.TimestampFormat = "unix_ns"
+	case "unix", "unix_ms", "unix_us", "unix_ns":
+	default:
+		return fmt.Errorf("invalid timestamp format %q", s.TimestampFormat)
+	}
+
+	if s.Schema != "" {
+		schema, err := parseSchema(s.Schema, s.Timestamp)
+		if err != nil {
+			return fmt.Errorf("invalid 'avro_schema': %w", err)
+		}
+		s.schema = schema
+	}
+	s.derived = make(map[string]*schema)
+
+	if s.SchemaRegistry != "" {
+		s.registry = newSchemaRegistry(s.SchemaRegistry, registryTimeout)
+	}
+
+	return nil
+}
+
+func (s *Serializer) Serialize(m telegraf.Metric) ([]byte, error) {
+	schema := s.schema
+	if schema == nil {
+		var err error
+		if schema, err = s.deriveSchema(m); err != nil {
+			return nil, fmt.Errorf("deriving schema failed: %w", err)
+		}
+	}
+
+	native := make(map[string]interface{}, len(schema.fields))
+	for _, field := range schema.fields {
+		var value interface{}
+		var found bool
+		if field.timestamp {
+			value, found = m.Time(), true
+		} else if value, found = m.GetTag(field.key); !found {
+			value, found = m.GetField(field.key)
+		}
+
+		if !found {
+			// Missing values are null if possible, otherwise the codec uses
+			// the default value or fails.
+			if field.nullable {
+				native[field.name] = nil
+			}
+			continue
+		}
+
+		v, err := s.convert(field.typ, value)
+		if err != nil {
+			return nil, fmt.Errorf("converting %q failed: %w", field.name, err)
+		}
+		native[field.name] = v
+	}
+
+	var buf []byte
+	if s.registry != nil {
+		if err := s.register(schema); err != nil {
+			return nil, err
+		}
+		// Magic byte zero followed by the schema ID
+		buf = make([]byte, 5, 64)
+		binary.BigEndian.PutUint32(buf[1:], schema.id)
+	}
+
+	buf, err := schema.codec.BinaryFromNative(buf, native)
+	if err != nil {
+		return nil, fmt.Errorf("encoding failed: %w", err)
+	}
+	return buf, nil
+}
+
+func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
+	var buf []byte
+	for _, m := range metrics {
+		serialized, err := s.Serialize(m)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, serialized...)
+	}
+	return buf, nil
+}
+
+// register registers the schema if not done yet. Failures are cached and
+// returned until the retry delay elapsed to avoid querying the registry for
+// every metric, e.g. during an outage.
+func (s *Serializer) register(schema *schema) error {
+	if schema.registered {
+		return nil
+	}
+	if schema.err != nil && time.Now().Before(schema.retryAt) {
+		return schema.err
+	}
+
+	subject := s.Subject
+	if subject == "" {
+		subject = schema.subject
+	}
+	id, err := s.registry.register(subject, schema.definition)
+	if err != nil {
+		schema.err = err
+		schema.retryAt = time.Now().Add(registryRetryDelay)
+		return err
+	}
+	schema.id = id
+	schema.registered = true
+	schema.err = nil
+	return nil
+}
+
+func (s *Serializer) deriveSchema(m telegraf.Metric) (*schema, error) {
+	schema, err := deriveSchema(m, s.Namespace, s.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reuse the known schema to avoid registering it again
+	if known, found := s.derived[schema.definition]; found {
+		return known, nil
+	}
+	if schema.codec, err = goavro.NewCodec(schema.definition); err != nil {
+		return nil, err
+	}
+	s.derived[schema.definition] = schema
+	return schema, nil
+}
+
+// convert returns the value in the native representation of the given Avro
+// type. Unions are supported for null and primitive or logical types.
+func (s *Serializer) convert(typ, value interface{}) (interface{}, error) {
+	switch t := typ.(type) {
+	case string:
+		return s.convertPrimitive(t, value)
+	case map[string]interface{}:
+		name, _ := t["type"].(string)
+		switch t["logicalType"] {
+		case "timestamp-millis", "timestamp-micros", "local-timestamp-millis", "local-timestamp-micros":
+			if ts, ok := value.(time.Time); ok {
+				return ts, nil
+			}
+		}
+		if name == "enum" {
+			return internal.ToString(value)
+		}
+		return s.convertPrimitive(name, value)
+	case []interface{}:
+		for _, branch := range t {
+			name := branchName(branch)
+			if name == "" || name == "null" {
+				continue
+			}
+			if v, err := s.convert(branch, value); err == nil {
+				return map[string]interface{}{name: v}, nil
+			}
+		}
+		return nil, fmt.Errorf("value %v matches no type of union %v", value, t)
+	}
+	return nil, fmt.Errorf("unsupported type %v", typ)
+}
+
+func (s *Serializer) convertPrimitive(name string, value interface{}) (interface{}, error) {
+	if ts, ok := value.(time.Time); ok {
+		value = s.timestamp(ts)
+	}
+
+	switch name {
+	case "boolean":
+		return internal.ToBool(value)
+	case "int":
+		v, err := internal.ToInt64(value)
+		if err == nil && (v < math.MinInt32 || v > math.MaxInt32) {
+			return nil, fmt.Errorf("value %d out of range for int", v)
+		}
+		return int32(v), err
+	case "long":
+		if v, ok := value.(uint64); ok && v > math.MaxInt64 {
+			return nil, fmt.Errorf("value %d out of range for long", v)
+		}
+		return internal.ToInt64(value)
+	case "float":
+		v, err := internal.ToFloat64(value)
+		return float32(v), err
+	case "double":
+		return internal.ToFloat64(value)
+	case "string":
+		return internal.ToString(value)
+	case "bytes":
+		v, err := internal.ToString(value)
+		return []byte(v), err
+	}
+	return nil, fmt.Errorf("unsupported type %q", name)
+}
+
+func (s *Serializer) timestamp(t time.Time) int64 {
+	switch s.TimestampFormat {
+	case "unix":
+		return t.Unix()
+	case "unix_ms":
+		return t.UnixNano() / 1_000_000
+	case "unix_us":
+		return t.UnixNano() / 1_000
+	}
+	return t.UnixNano()
+}
+
+// branchName returns the name of the union branch as expected by the codec
+// or an empty string for named and complex types.
+func branchName(branch interface{}) string {
+	switch b := branch.(type) {
+	case string:
+		return b
+	case map[string]interface{}:
+		name, _ := b["type"].(string)
+		switch name {
+		case "record", "enum", "fixed", "array", "map":
+			return ""
+		}
+		if logical, ok := b["logicalType"].(string); ok {
+			return name + "." + logical
+		}
+		return name
+	}
+	return ""
+}
+
+func init() {
+	serializers.Add("avro",
+		func() serializers.Serializer {
+			return &Serializer{}
+		},
+	)
+}
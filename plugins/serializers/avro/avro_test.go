@@ -0,0 +1,296 @@
+package avro
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	parser "github.com/influxdata/telegraf/plugins/parsers/avro"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+// mockRegistry implements the parts of the Confluent schema registry API
+// used by the serializer and parser.
+type mockRegistry struct {
+	sync.Mutex
+	schemas       []string
+	subjects      []string
+	registrations int
+}
+
+func (r *mockRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	switch {
+	case req.Method == http.MethodPost && strings.HasPrefix(req.URL.Path, "/subjects/"):
+		var body struct {
+			Schema string `json:"schema"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(body.Schema, "incompatible") {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error_code":409,"message":"schema is incompatible"}`))
+			return
+		}
+		r.registrations++
+		r.subjects = append(r.subjects, strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/subjects/"), "/versions"))
+		id := len(r.schemas)
+		for i, s := range r.schemas {
+			if s == body.Schema {
+				id = i
+			}
+		}
+		if id == len(r.schemas) {
+			r.schemas = append(r.schemas, body.Schema)
+		}
+		_, _ = fmt.Fprintf(w, `{"id":%d}`, id+1)
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/schemas/ids/"):
+		id, err := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/schemas/ids/"))
+		if err != nil || id < 1 || id > len(r.schemas) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"schema": r.schemas[id-1]})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSerializeDerivedSchema(t *testing.T) {
+	s := &Serializer{Namespace: "telegraf"}
+	require.NoError(t, s.Init())
+
+	m := testutil.MustMetric(
+		"cpu",
+		map[string]string{"host": "a", "cpu-id": "cpu0"},
+		map[string]interface{}{"usage": 42.5, "cores": int64(4), "online": true, "memory": uint64(1024), "host": "skipped"},
+		time.Unix(1, 500),
+	)
+	buf, err := s.Serialize(m)
+	require.NoError(t, err)
+
+	expected := `{"type":"record","name":"cpu","namespace":"telegraf","fields":[` +
+		`{"name":"cpu_id","type":["null","string"],"default":null},` +
+		`{"name":"host","type":["null","string"],"default":null},` +
+		`{"name":"cores","type":["null","long"],"default":null},` +
+		`{"name":"memory","type":["null","long"],"default":null},` +
+		`{"name":"online","type":["null","boolean"],"default":null},` +
+		`{"name":"usage","type":["null","double"],"default":null},` +
+		`{"name":"timestamp","type":"long"}]}`
+	require.Contains(t, s.derived, expected)
+	codec, err := goavro.NewCodec(expected)
+	require.NoError(t, err)
+
+	native, remaining, err := codec.NativeFromBinary(buf)
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+	require.Equal(t, map[string]interface{}{
+		"cpu_id":    map[string]interface{}{"string": "cpu0"},
+		"host":      map[string]interface{}{"string": "a"},
+		"cores":     map[string]interface{}{"long": int64(4)},
+		"memory":    map[string]interface{}{"long": int64(1024)},
+		"online":    map[string]interface{}{"boolean": true},
+		"usage":     map[string]interface{}{"double": 42.5},
+		"timestamp": int64(1000000500),
+	}, native)
+}
+
+func TestSerializeSchemaRegistry(t *testing.T) {
+	registry := &mockRegistry{}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	s := &Serializer{
+		SchemaRegistry:  server.URL,
+		TimestampFormat: "unix_ms",
+	}
+	require.NoError(t, s.Init())
+
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{"host": "a"}, map[string]interface{}{"usage": 42.5}, time.Unix(1, 0)),
+		testutil.MustMetric("mem", map[string]string{"host": "a"}, map[string]interface{}{"used": int64(1024)}, time.Unix(2, 0)),
+		testutil.MustMetric("cpu", map[string]string{"host": "b"}, map[string]interface{}{"usage": 10.0}, time.Unix(3, 0)),
+	}
+
+	p := &parser.Parser{
+		SchemaRegistry:  server.URL,
+		Tags:            []string{"host"},
+		Timestamp:       "timestamp",
+		TimestampFormat: "unix_ms",
+		UnionMode:       "nullable",
+		Log:             testutil.Logger{},
+	}
+	require.NoError(t, p.Init())
+
+	expectedIDs := []uint32{1, 2, 1}
+	for i, m := range metrics {
+		buf, err := s.Serialize(m)
+		require.NoError(t, err)
+		require.Equal(t, byte(0), buf[0])
+		require.Equal(t, expectedIDs[i], binary.BigEndian.Uint32(buf[1:5]))
+
+		actual, err := p.Parse(buf)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+
+		expected := m.Copy()
+		expected.AddField("timestamp", m.Time().UnixMilli())
+		testutil.RequireMetricEqual(t, expected, actual[0])
+	}
+
+	// Each schema is registered once using the record name as subject
+	require.Equal(t, 2, registry.registrations)
+	require.Equal(t, []string{"cpu", "mem"}, registry.subjects)
+}
+
+func TestSerializeSchemaRegistryError(t *testing.T) {
+	server := httptest.NewServer(&mockRegistry{})
+	defer server.Close()
+
+	s := &Serializer{
+		SchemaRegistry: server.URL,
+		Subject:        "telegraf-value",
+	}
+	require.NoError(t, s.Init())
+
+	m := testutil.MustMetric("incompatible", map[string]string{}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0))
+	_, err := s.Serialize(m)
+	require.EqualError(t, err, `registering schema for subject "telegraf-value" failed with status 409: schema is incompatible`)
+}
+
+func TestSerializeSchemaRegistryRetry(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error_code":50301,"message":"unavailable"}`))
+	}))
+	defer server.Close()
+
+	s := &Serializer{SchemaRegistry: server.URL}
+	require.NoError(t, s.Init())
+
+	// Failed registrations are not repeated for every metric
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0))
+	for i := 0; i < 3; i++ {
+		_, err := s.Serialize(m)
+		require.ErrorContains(t, err, "failed with status 503")
+	}
+	require.Equal(t, 1, requests)
+
+	// Registration is retried after the delay
+	schema, err := deriveSchema(m, s.Namespace, s.Timestamp)
+	require.NoError(t, err)
+	s.derived[schema.definition].retryAt = time.Time{}
+	_, err = s.Serialize(m)
+	require.Error(t, err)
+	require.Equal(t, 2, requests)
+}
+
+func TestSerializeGivenSchema(t *testing.T) {
+	schema := `
+{
+  "type": "record",
+  "name": "Value",
+  "namespace": "com.example",
+  "fields": [
+    {"name": "host", "type": "string"},
+    {"name": "cores", "type": "int"},
+    {"name": "usage", "type": ["null", "double"]},
+    {"name": "state", "type": {"type": "enum", "name": "State", "symbols": ["OK", "FAILED"]}},
+    {"name": "region", "type": "string", "default": "unknown"},
+    {"name": "time", "type": {"type": "long", "logicalType": "timestamp-millis"}}
+  ]
+}`
+
+	s := &Serializer{
+		Schema:    schema,
+		Timestamp: "time",
+	}
+	require.NoError(t, s.Init())
+
+	codec, err := goavro.NewCodec(schema)
+	require.NoError(t, err)
+
+	m := testutil.MustMetric(
+		"cpu",
+		map[string]string{"host": "a"},
+		map[string]interface{}{"cores": int64(4), "usage": 42.5, "state": "FAILED", "ignored": true},
+		time.UnixMilli(1500),
+	)
+	buf, err := s.Serialize(m)
+	require.NoError(t, err)
+	native, _, err := codec.NativeFromBinary(buf)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"host":   "a",
+		"cores":  int32(4),
+		"usage":  map[string]interface{}{"double": 42.5},
+		"state":  "FAILED",
+		"region": "unknown",
+		"time":   time.UnixMilli(1500).UTC(),
+	}, native)
+
+	// Missing nullable fields are null
+	m.RemoveField("usage")
+	buf, err = s.Serialize(m)
+	require.NoError(t, err)
+	native, _, err = codec.NativeFromBinary(buf)
+	require.NoError(t, err)
+	require.Nil(t, native.(map[string]interface{})["usage"])
+
+	// Values not matching the schema are an error
+	m.AddField("cores", "four")
+	_, err = s.Serialize(m)
+	require.ErrorContains(t, err, `converting "cores" failed`)
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Serializer
+		expected string
+	}{
+		{
+			name:     "invalid timestamp format",
+			plugin:   &Serializer{TimestampFormat: "RFC3339"},
+			expected: `invalid timestamp format "RFC3339"`,
+		},
+		{
+			name:     "invalid timestamp name",
+			plugin:   &Serializer{Timestamp: "time-stamp"},
+			expected: `invalid timestamp field name "time-stamp"`,
+		},
+		{
+			name:     "invalid schema",
+			plugin:   &Serializer{Schema: `{"type": "record"}`},
+			expected: "invalid 'avro_schema'",
+		},
+		{
+			name:     "schema not a record",
+			plugin:   &Serializer{Schema: `"string"`},
+			expected: "invalid 'avro_schema': schema is not a record",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.plugin.Init(), tt.expected)
+		})
+	}
+}
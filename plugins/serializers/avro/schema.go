@@ -0,0 +1,224 @@
+package avro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/influxdata/telegraf"
+)
+
+// schema is an Avro record schema with the information required to fill the
+// record from a metric.
+type schema struct {
+	definition string
+	subject    string
+	codec      *goavro.Codec
+	fields     []schemaField
+
+	// ID assigned by the schema registry, valid if registered is set
+	id         uint32
+	registered bool
+
+	// Last failed registration, repeated until retryAt
+	err     error
+	retryAt time.Time
+}
+
+type schemaField struct {
+	name      string
+	key       string
+	typ       interface{}
+	timestamp bool
+	nullable  bool
+}
+
+// derived schema definition as serialized to JSON
+type recordDefinition struct {
+	Type      string            `json:"type"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Fields    []fieldDefinition `json:"fields"`
+}
+
+type fieldDefinition struct {
+	Name    string          `json:"name"`
+	Type    interface{}     `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// nullableField returns the definition of a field being null by default, so
+// schemas of metrics with different tags and fields of the same measurement
+// stay compatible to each other.
+func nullableField(name, typ string) (fieldDefinition, schemaField) {
+	union := []interface{}{"null", typ}
+	definition := fieldDefinition{Name: name, Type: union, Default: json.RawMessage("null")}
+	return definition, schemaField{name: name, typ: union, nullable: true}
+}
+
+// parseSchema creates the schema from a user-supplied definition. Record
+// fields are filled with the metric's tag or field of the same name.
+func parseSchema(definition, timestamp string) (*schema, error) {
+	codec, err := goavro.NewCodec(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(definition), &parsed); err != nil {
+		return nil, err
+	}
+	record, ok := parsed.(map[string]interface{})
+	if !ok || record["type"] != "record" {
+		return nil, errors.New("schema is not a record")
+	}
+	name, _ := record["name"].(string)
+	if namespace, ok := record["namespace"].(string); ok && namespace != "" && !strings.Contains(name, ".") {
+		name = namespace + "." + name
+	}
+
+	definitions, ok := record["fields"].([]interface{})
+	if !ok {
+		return nil, errors.New("schema has no fields")
+	}
+	fields := make([]schemaField, 0, len(definitions))
+	for _, d := range definitions {
+		def, ok := d.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid field definition %v", d)
+		}
+		fieldName, ok := def["name"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid field name in definition %v", d)
+		}
+		_, hasDefault := def["default"]
+		fields = append(fields, schemaField{
+			name:      fieldName,
+			key:       fieldName,
+			typ:       def["type"],
+			timestamp: fieldName == timestamp,
+			nullable:  !hasDefault && isNullable(def["type"]),
+		})
+	}
+
+	return &schema{
+		definition: definition,
+		subject:    name,
+		codec:      codec,
+		fields:     fields,
+	}, nil
+}
+
+// deriveSchema creates a record schema named after the measurement with one
+// nullable field per tag and field of the metric plus the timestamp field.
+// The codec is left to the caller to avoid creating it for already known
+// schemas.
+func deriveSchema(m telegraf.Metric, namespace, timestamp string) (*schema, error) {
+	record := recordDefinition{
+		Type:      "record",
+		Name:      sanitize(m.Name()),
+		Namespace: namespace,
+	}
+	var fields []schemaField
+	seen := map[string]bool{timestamp: true}
+
+	tags := m.TagList()
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Key < tags[j].Key })
+	for _, tag := range tags {
+		name := sanitize(tag.Key)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		definition, field := nullableField(name, "string")
+		field.key = tag.Key
+		record.Fields = append(record.Fields, definition)
+		fields = append(fields, field)
+	}
+
+	metricFields := m.FieldList()
+	sort.Slice(metricFields, func(i, j int) bool { return metricFields[i].Key < metricFields[j].Key })
+	for _, field := range metricFields {
+		name := sanitize(field.Key)
+		if seen[name] {
+			continue
+		}
+		if _, isTag := m.GetTag(field.Key); isTag {
+			continue
+		}
+
+		var typ string
+		switch field.Value.(type) {
+		case bool:
+			typ = "boolean"
+		case int64, uint64:
+			typ = "long"
+		case float64:
+			typ = "double"
+		case string:
+			typ = "string"
+		default:
+			continue
+		}
+		seen[name] = true
+		definition, schemaField := nullableField(name, typ)
+		schemaField.key = field.Key
+		record.Fields = append(record.Fields, definition)
+		fields = append(fields, schemaField)
+	}
+
+	record.Fields = append(record.Fields, fieldDefinition{Name: timestamp, Type: "long"})
+	fields = append(fields, schemaField{name: timestamp, typ: "long", timestamp: true})
+
+	definition, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := record.Name
+	if namespace != "" {
+		subject = namespace + "." + subject
+	}
+	return &schema{
+		definition: string(definition),
+		subject:    subject,
+		fields:     fields,
+	}, nil
+}
+
+func isNullable(typ interface{}) bool {
+	union, ok := typ.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, branch := range union {
+		if branch == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitize replaces all characters not allowed in Avro names by underscores
+func sanitize(name string) string {
+	var b strings.Builder
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+			b.WriteRune(c)
+		case c >= '0' && c <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
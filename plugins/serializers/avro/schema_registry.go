@@ -0,0 +1,54 @@
+package avro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const subjectVersions = "%s/subjects/%s/versions"
+
+type schemaRegistry struct {
+	url    string
+	client *http.Client
+}
+
+func newSchemaRegistry(address string, timeout time.Duration) *schemaRegistry {
+	return &schemaRegistry{
+		url:    strings.TrimSuffix(address, "/"),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// register registers the schema under the given subject and returns its ID.
+// Registering an already known schema returns the existing ID.
+func (sr *schemaRegistry) register(subject, schema string) (uint32, error) {
+	body, err := json.Marshal(map[string]string{"schema": schema})
+	if err != nil {
+		return 0, err
+	}
+
+	address := fmt.Sprintf(subjectVersions, sr.url, url.PathEscape(subject))
+	resp, err := sr.client.Post(address, "application/vnd.schemaregistry.v1+json", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var jsonResponse struct {
+		ID      uint32 `json:"id"`
+		Code    int    `json:"error_code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jsonResponse); err != nil {
+		return 0, fmt.Errorf("decoding response from schema registry failed (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("registering schema for subject %q failed with status %d: %s", subject, resp.StatusCode, jsonResponse.Message)
+	}
+	return jsonResponse.ID, nil
+}
@@ -0,0 +1,88 @@
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/apache/arrow/go/v13/parquet"
+	"github.com/apache/arrow/go/v13/parquet/compress"
+	"github.com/apache/arrow/go/v13/parquet/pqarrow"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+type Serializer struct {
+	Compression string `toml:"parquet_compression"`
+
+	props *parquet.WriterProperties
+}
+
+func (s *Serializer) Init() error {
+	props, err := WriterProperties(s.Compression)
+	if err != nil {
+		return err
+	}
+	s.props = props
+
+	return nil
+}
+
+func (s *Serializer) Serialize(m telegraf.Metric) ([]byte, error) {
+	return s.SerializeBatch([]telegraf.Metric{m})
+}
+
+// SerializeBatch returns a complete Parquet file holding all given metrics.
+func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
+	if len(metrics) < 1 {
+		return nil, nil
+	}
+
+	schema := NewSchema(metrics)
+	record := schema.Record(metrics)
+	defer record.Release()
+
+	var buf bytes.Buffer
+	writer, err := pqarrow.NewFileWriter(schema.Arrow(), &buf, s.props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, fmt.Errorf("creating writer failed: %w", err)
+	}
+	if err := writer.Write(record); err != nil {
+		return nil, fmt.Errorf("writing metrics failed: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("finishing file failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriterProperties returns the Parquet writer properties for the given
+// compression codec, defaulting to snappy.
+func WriterProperties(compression string) (*parquet.WriterProperties, error) {
+	var codec compress.Compression
+	switch compression {
+	case "", "snappy":
+		codec = compress.Codecs.Snappy
+	case "none":
+		codec = compress.Codecs.Uncompressed
+	case "gzip":
+		codec = compress.Codecs.Gzip
+	case "brotli":
+		codec = compress.Codecs.Brotli
+	case "lz4":
+		codec = compress.Codecs.Lz4
+	case "zstd":
+		codec = compress.Codecs.Zstd
+	default:
+		return nil, fmt.Errorf("invalid compression %q", compression)
+	}
+	return parquet.NewWriterProperties(parquet.WithCompression(codec)), nil
+}
+
+func init() {
+	serializers.Add("parquet",
+		func() serializers.Serializer {
+			return &Serializer{}
+		},
+	)
+}
@@ -0,0 +1,113 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/parquet"
+	"github.com/apache/arrow/go/v13/parquet/pqarrow"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestSerializeBatch(t *testing.T) {
+	metrics := []telegraf.Metric{
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": 42.5, "cores": int64(4)},
+			time.Unix(1, 0),
+		),
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{"host": "b", "region": "eu"},
+			map[string]interface{}{"usage": 10.0, "online": true},
+			time.Unix(2, 0),
+		),
+	}
+
+	s := &Serializer{}
+	require.NoError(t, s.Init())
+	buf, err := s.SerializeBatch(metrics)
+	require.NoError(t, err)
+
+	table := readTable(t, buf)
+	defer table.Release()
+	require.EqualValues(t, 2, table.NumRows())
+
+	var columns []string
+	for _, f := range table.Schema().Fields() {
+		columns = append(columns, f.Name)
+	}
+	require.Equal(t, []string{"measurement", "time", "host", "region", "cores", "online", "usage"}, columns)
+
+	region := table.Column(3).Data().Chunk(0).(*array.String)
+	require.True(t, region.IsNull(0))
+	require.Equal(t, "eu", region.Value(1))
+
+	usage := table.Column(6).Data().Chunk(0).(*array.Float64)
+	require.Equal(t, []float64{42.5, 10.0}, usage.Float64Values())
+
+	ts := table.Column(1).Data().Chunk(0).(*array.Timestamp)
+	require.Equal(t, arrow.Timestamp(time.Unix(2, 0).UnixNano()), ts.Value(1))
+}
+
+func TestSerializeTypeConflict(t *testing.T) {
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("test", map[string]string{}, map[string]interface{}{"value": int64(1)}, time.Unix(0, 0)),
+		testutil.MustMetric("test", map[string]string{}, map[string]interface{}{"value": 2.5}, time.Unix(0, 0)),
+		testutil.MustMetric("test", map[string]string{}, map[string]interface{}{"value": "foo"}, time.Unix(0, 0)),
+	}
+
+	// Numeric types are widened to double
+	schema := NewSchema(metrics)
+	require.True(t, schema.Covers(metrics[0]))
+	require.True(t, schema.Covers(metrics[1]))
+	require.False(t, schema.Covers(metrics[2]))
+	require.True(t, schema.Extend(metrics[2:3]).Covers(metrics[2]))
+
+	schema = NewSchema(metrics[:1])
+	require.False(t, schema.Covers(metrics[1]))
+	extended := schema.Extend(metrics[1:2])
+	require.True(t, extended.Covers(metrics[0]))
+	require.True(t, extended.Covers(metrics[1]))
+
+	s := &Serializer{Compression: "zstd"}
+	require.NoError(t, s.Init())
+	buf, err := s.SerializeBatch(metrics)
+	require.NoError(t, err)
+
+	table := readTable(t, buf)
+	defer table.Release()
+
+	// Numeric values share a double column, other types are converted if
+	// possible
+	value := table.Column(2).Data().Chunk(0).(*array.Float64)
+	require.Equal(t, 1.0, value.Value(0))
+	require.Equal(t, 2.5, value.Value(1))
+	require.True(t, value.IsNull(2))
+}
+
+func TestInvalidCompression(t *testing.T) {
+	s := &Serializer{Compression: "foo"}
+	require.EqualError(t, s.Init(), `invalid compression "foo"`)
+}
+
+func readTable(t *testing.T, buf []byte) arrow.Table {
+	table, err := pqarrow.ReadTable(
+		context.Background(),
+		bytes.NewReader(buf),
+		parquet.NewReaderProperties(memory.DefaultAllocator),
+		pqarrow.ArrowReadProperties{},
+		memory.DefaultAllocator,
+	)
+	require.NoError(t, err)
+	return table
+}
@@ -0,0 +1,264 @@
+package parquet
+
+import (
+	"sort"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+)
+
+// Names of the columns present in every file
+const (
+	MeasurementColumn = "measurement"
+	TimeColumn        = "time"
+)
+
+// Schema describes the columns of a Parquet file holding metrics. Besides the
+// measurement name and the timestamp, there is a string column for each tag
+// and a column for each field typed after the field value. Fields named like
+// a tag or one of the fixed columns are skipped as are fields with values not
+// representable in Parquet, like native histograms.
+type Schema struct {
+	tags   map[string]bool
+	fields map[string]arrow.DataType
+
+	schema *arrow.Schema
+}
+
+// NewSchema returns the schema covering all given metrics. Conflicting numeric
+// types are widened to double, for other type conflicts the type of the first
+// value of a field is used.
+func NewSchema(metrics []telegraf.Metric) *Schema {
+	s := &Schema{
+		tags:   make(map[string]bool),
+		fields: make(map[string]arrow.DataType),
+	}
+	s.add(metrics, false)
+	return s
+}
+
+// Extend returns a new schema covering the columns of the schema and the
+// given metrics. Conflicting numeric types are widened to double, for other
+// type conflicts the types of the metrics win.
+func (s *Schema) Extend(metrics []telegraf.Metric) *Schema {
+	extended := &Schema{
+		tags:   make(map[string]bool, len(s.tags)),
+		fields: make(map[string]arrow.DataType, len(s.fields)),
+	}
+	for k := range s.tags {
+		extended.tags[k] = true
+	}
+	for k, t := range s.fields {
+		extended.fields[k] = t
+	}
+	extended.add(metrics, true)
+	return extended
+}
+
+// Covers returns true if the metric can be stored without losing tags, fields
+// or field types. Numeric values are covered by double columns.
+func (s *Schema) Covers(m telegraf.Metric) bool {
+	for _, tag := range m.TagList() {
+		if !isReserved(tag.Key) && !s.tags[tag.Key] {
+			return false
+		}
+	}
+	for _, field := range m.FieldList() {
+		t := dataType(field.Value)
+		if t == nil || isReserved(field.Key) || s.tags[field.Key] {
+			continue
+		}
+		existing, found := s.fields[field.Key]
+		if !found {
+			return false
+		}
+		if !arrow.TypeEqual(existing, t) && !(existing.ID() == arrow.FLOAT64 && isNumeric(t)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Arrow returns the Arrow schema with the columns sorted by kind and name.
+func (s *Schema) Arrow() *arrow.Schema {
+	if s.schema != nil {
+		return s.schema
+	}
+
+	tags := make([]string, 0, len(s.tags))
+	for k := range s.tags {
+		tags = append(tags, k)
+	}
+	sort.Strings(tags)
+	fields := make([]string, 0, len(s.fields))
+	for k := range s.fields {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	columns := make([]arrow.Field, 0, 2+len(tags)+len(fields))
+	columns = append(columns,
+		arrow.Field{Name: MeasurementColumn, Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: TimeColumn, Type: &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}},
+	)
+	for _, k := range tags {
+		columns = append(columns, arrow.Field{Name: k, Type: arrow.BinaryTypes.String, Nullable: true})
+	}
+	for _, k := range fields {
+		columns = append(columns, arrow.Field{Name: k, Type: s.fields[k], Nullable: true})
+	}
+	s.schema = arrow.NewSchema(columns, nil)
+	return s.schema
+}
+
+// Record returns the given metrics as Arrow record using the schema. Tags and
+// fields missing in a metric are null, field values of a different type are
+// converted if possible and null otherwise. The caller must release the record.
+func (s *Schema) Record(metrics []telegraf.Metric) arrow.Record {
+	schema := s.Arrow()
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	index := make(map[string]int, len(schema.Fields()))
+	for i, f := range schema.Fields() {
+		index[f.Name] = i
+	}
+
+	for _, m := range metrics {
+		builder.Field(0).(*array.StringBuilder).Append(m.Name())
+		builder.Field(1).(*array.TimestampBuilder).Append(arrow.Timestamp(m.Time().UnixNano()))
+
+		filled := make([]bool, len(schema.Fields()))
+		filled[0], filled[1] = true, true
+		for _, tag := range m.TagList() {
+			i, found := index[tag.Key]
+			if !found || isReserved(tag.Key) {
+				continue
+			}
+			builder.Field(i).(*array.StringBuilder).Append(tag.Value)
+			filled[i] = true
+		}
+		for _, field := range m.FieldList() {
+			i, found := index[field.Key]
+			if !found || filled[i] || s.tags[field.Key] {
+				continue
+			}
+			filled[i] = appendValue(builder.Field(i), field.Value)
+		}
+		for i, ok := range filled {
+			if !ok {
+				builder.Field(i).AppendNull()
+			}
+		}
+	}
+
+	return builder.NewRecord()
+}
+
+func (s *Schema) add(metrics []telegraf.Metric, override bool) {
+	s.schema = nil
+
+	// Tags take precedence over fields of the same name
+	for _, m := range metrics {
+		for _, tag := range m.TagList() {
+			if isReserved(tag.Key) {
+				continue
+			}
+			s.tags[tag.Key] = true
+			delete(s.fields, tag.Key)
+		}
+	}
+
+	for _, m := range metrics {
+		for _, field := range m.FieldList() {
+			t := dataType(field.Value)
+			if t == nil || isReserved(field.Key) || s.tags[field.Key] {
+				continue
+			}
+			existing, found := s.fields[field.Key]
+			switch {
+			case !found:
+				s.fields[field.Key] = t
+			case arrow.TypeEqual(existing, t):
+			case isNumeric(existing) && isNumeric(t):
+				s.fields[field.Key] = arrow.PrimitiveTypes.Float64
+			case override:
+				s.fields[field.Key] = t
+			}
+		}
+	}
+}
+
+func isReserved(name string) bool {
+	return name == MeasurementColumn || name == TimeColumn
+}
+
+func isNumeric(t arrow.DataType) bool {
+	switch t.ID() {
+	case arrow.INT64, arrow.UINT64, arrow.FLOAT64:
+		return true
+	}
+	return false
+}
+
+// dataType returns the Arrow type for the field value or nil if the value
+// cannot be represented.
+func dataType(value interface{}) arrow.DataType {
+	switch value.(type) {
+	case int64:
+		return arrow.PrimitiveTypes.Int64
+	case uint64:
+		return arrow.PrimitiveTypes.Uint64
+	case float64:
+		return arrow.PrimitiveTypes.Float64
+	case bool:
+		return arrow.FixedWidthTypes.Boolean
+	case string:
+		return arrow.BinaryTypes.String
+	}
+	return nil
+}
+
+// appendValue appends the value converted to the type of the builder and
+// returns false if the conversion failed.
+func appendValue(builder array.Builder, value interface{}) bool {
+	switch b := builder.(type) {
+	case *array.Int64Builder:
+		v, err := internal.ToInt64(value)
+		if err != nil {
+			return false
+		}
+		b.Append(v)
+	case *array.Uint64Builder:
+		v, err := internal.ToUint64(value)
+		if err != nil {
+			return false
+		}
+		b.Append(v)
+	case *array.Float64Builder:
+		v, err := internal.ToFloat64(value)
+		if err != nil {
+			return false
+		}
+		b.Append(v)
+	case *array.BooleanBuilder:
+		v, err := internal.ToBool(value)
+		if err != nil {
+			return false
+		}
+		b.Append(v)
+	case *array.StringBuilder:
+		v, err := internal.ToString(value)
+		if err != nil {
+			return false
+		}
+		b.Append(v)
+	default:
+		return false
+	}
+	return true
+}
@@ -54,6 +54,7 @@ type Histogram struct {
 	Buckets []Bucket
 	Count   uint64
 	Sum     float64
+	Native  *telegraf.ExponentialBuckets
 }
 
 func (h *Histogram) merge(b Bucket) {
@@ -183,7 +184,13 @@ func (c *Collection) createLabels(metric telegraf.Metric) []LabelPair {
 func (c *Collection) Add(metric telegraf.Metric, now time.Time) {
 	labels := c.createLabels(metric)
 	for _, field := range metric.FieldList() {
-		metricName := MetricName(metric.Name(), field.Key, metric.Type())
+		valueType := metric.Type()
+		metricName := MetricName(metric.Name(), field.Key, valueType)
+		if vt, ok := NativeValueType(field.Value); ok {
+			// Native values are complete, so the field key carries no suffix
+			valueType = vt
+			metricName = MetricName(metric.Name(), field.Key, telegraf.Untyped)
+		}
 		metricName, ok := SanitizeMetricName(metricName)
 		if !ok {
 			continue
@@ -191,7 +198,7 @@ func (c *Collection) Add(metric telegraf.Metric, now time.Time) {
 
 		family := MetricFamily{
 			Name: metricName,
-			Type: metric.Type(),
+			Type: valueType,
 		}
 
 		entry, ok := c.Entries[family]
@@ -215,6 +222,33 @@ func (c *Collection) Add(metric telegraf.Metric, now time.Time) {
 			}
 		}
 
+		switch v := field.Value.(type) {
+		case *telegraf.HistogramValue:
+			h := &Histogram{Count: v.Count, Sum: v.Sum, Native: v.Exponential}
+			for _, b := range v.Buckets {
+				h.Buckets = append(h.Buckets, Bucket{Bound: b.UpperBound, Count: b.Count})
+			}
+			entry.Metrics[metricKey] = &Metric{
+				Labels:    labels,
+				Time:      metric.Time(),
+				AddTime:   now,
+				Histogram: h,
+			}
+			continue
+		case *telegraf.SummaryValue:
+			s := &Summary{Count: v.Count, Sum: v.Sum}
+			for _, q := range v.Quantiles {
+				s.Quantiles = append(s.Quantiles, Quantile{Quantile: q.Quantile, Value: q.Value})
+			}
+			entry.Metrics[metricKey] = &Metric{
+				Labels:  labels,
+				Time:    metric.Time(),
+				AddTime: now,
+				Summary: s,
+			}
+			continue
+		}
+
 		switch metric.Type() {
 		case telegraf.Counter:
 			fallthrough
@@ -457,6 +491,13 @@ func (c *Collection) GetProto() []*dto.MetricFamily {
 					SampleCount: proto.Uint64(metric.Histogram.Count),
 					SampleSum:   proto.Float64(metric.Histogram.Sum),
 				}
+				if native := metric.Histogram.Native; native != nil {
+					m.Histogram.Schema = proto.Int32(native.Schema)
+					m.Histogram.ZeroThreshold = proto.Float64(native.ZeroThreshold)
+					m.Histogram.ZeroCount = proto.Uint64(native.ZeroCount)
+					m.Histogram.PositiveSpan, m.Histogram.PositiveDelta = nativeSpans(native.Positive)
+					m.Histogram.NegativeSpan, m.Histogram.NegativeDelta = nativeSpans(native.Negative)
+				}
 			case telegraf.Summary:
 				quantiles := make([]*dto.Quantile, 0, len(metric.Summary.Quantiles))
 				for _, quantile := range metric.Summary.Quantiles {
@@ -485,3 +526,11 @@ func (c *Collection) GetProto() []*dto.MetricFamily {
 
 	return result
 }
+
+func nativeSpans(r telegraf.BucketRange) ([]*dto.BucketSpan, []int64) {
+	offset, length, deltas := NativeBuckets(r)
+	if length == 0 {
+		return nil, nil
+	}
+	return []*dto.BucketSpan{{Offset: proto.Int32(offset), Length: proto.Uint32(length)}}, deltas
+}
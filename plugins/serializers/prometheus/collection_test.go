@@ -842,3 +842,90 @@ func TestExportTimestamps(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectionNativeValues(t *testing.T) {
+	histogram := testutil.MustMetric(
+		"prometheus",
+		map[string]string{"handler": "api"},
+		map[string]interface{}{
+			"request_duration_seconds": &telegraf.HistogramValue{
+				Count:   7,
+				Sum:     12.5,
+				Buckets: []telegraf.Bucket{{UpperBound: 1, Count: 3}, {UpperBound: math.Inf(1), Count: 7}},
+				Exponential: &telegraf.ExponentialBuckets{
+					Schema:        0,
+					ZeroThreshold: 1e-128,
+					ZeroCount:     1,
+					Positive:      telegraf.BucketRange{Offset: -1, Counts: []uint64{1, 2, 0, 1}},
+					Negative:      telegraf.BucketRange{Offset: 0, Counts: []uint64{2}},
+				},
+			},
+		},
+		time.Unix(0, 0),
+	)
+	summary := testutil.MustMetric(
+		"rpc",
+		map[string]string{},
+		map[string]interface{}{
+			"duration_seconds_count": &telegraf.SummaryValue{
+				Count:     3,
+				Sum:       1.5,
+				Quantiles: []telegraf.Quantile{{Quantile: 0.5, Value: 0.4}, {Quantile: 0.99, Value: 0.9}},
+			},
+		},
+		time.Unix(0, 0),
+		telegraf.Summary,
+	)
+
+	expected := []*dto.MetricFamily{
+		{
+			Name: proto.String("request_duration_seconds"),
+			Help: proto.String(helpString),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label: []*dto.LabelPair{{Name: proto.String("handler"), Value: proto.String("api")}},
+					Histogram: &dto.Histogram{
+						SampleCount: proto.Uint64(7),
+						SampleSum:   proto.Float64(12.5),
+						Bucket: []*dto.Bucket{
+							{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(3)},
+							{UpperBound: proto.Float64(math.Inf(1)), CumulativeCount: proto.Uint64(7)},
+						},
+						Schema:        proto.Int32(0),
+						ZeroThreshold: proto.Float64(1e-128),
+						ZeroCount:     proto.Uint64(1),
+						PositiveSpan:  []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(4)}},
+						PositiveDelta: []int64{1, 1, -2, 1},
+						NegativeSpan:  []*dto.BucketSpan{{Offset: proto.Int32(1), Length: proto.Uint32(1)}},
+						NegativeDelta: []int64{2},
+					},
+				},
+			},
+		},
+		{
+			// Native values keep the field key as is
+			Name: proto.String("rpc_duration_seconds_count"),
+			Help: proto.String(helpString),
+			Type: dto.MetricType_SUMMARY.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label: []*dto.LabelPair{},
+					Summary: &dto.Summary{
+						SampleCount: proto.Uint64(3),
+						SampleSum:   proto.Float64(1.5),
+						Quantile: []*dto.Quantile{
+							{Quantile: proto.Float64(0.5), Value: proto.Float64(0.4)},
+							{Quantile: proto.Float64(0.99), Value: proto.Float64(0.9)},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	c := NewCollection(FormatConfig{MetricSortOrder: SortMetrics})
+	c.Add(histogram, time.Unix(0, 0))
+	c.Add(summary, time.Unix(0, 0))
+	require.Equal(t, expected, c.GetProto())
+}
@@ -195,3 +195,34 @@ func SampleSum(value interface{}) (float64, bool) {
 		return 0, false
 	}
 }
+
+// NativeValueType returns the type of the metric family for histogram and
+// summary field values holding all buckets or quantiles.
+func NativeValueType(value interface{}) (telegraf.ValueType, bool) {
+	switch value.(type) {
+	case *telegraf.HistogramValue:
+		return telegraf.Histogram, true
+	case *telegraf.SummaryValue:
+		return telegraf.Summary, true
+	default:
+		return 0, false
+	}
+}
+
+// NativeBuckets converts a range of exponential buckets into the offset and
+// length of a single span and the delta-encoded counts used by Prometheus
+// native histograms. Prometheus bucket indices are shifted by one compared to
+// Telegraf.
+func NativeBuckets(r telegraf.BucketRange) (offset int32, length uint32, deltas []int64) {
+	if len(r.Counts) == 0 {
+		return 0, 0, nil
+	}
+
+	deltas = make([]int64, 0, len(r.Counts))
+	var previous int64
+	for _, c := range r.Counts {
+		deltas = append(deltas, int64(c)-previous)
+		previous = int64(c)
+	}
+	return r.Offset + 1, uint32(len(r.Counts)), deltas
+}
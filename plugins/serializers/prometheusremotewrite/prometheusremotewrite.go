@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -62,11 +63,24 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 		var promts prompb.TimeSeries
 		for _, field := range metric.FieldList() {
 			metricName := prometheus.MetricName(metric.Name(), field.Key, metric.Type())
+			if _, ok := prometheus.NativeValueType(field.Value); ok {
+				// Native values are complete, so the field key carries no suffix
+				metricName = prometheus.MetricName(metric.Name(), field.Key, telegraf.Untyped)
+			}
 			metricName, ok := prometheus.SanitizeMetricName(metricName)
 			if !ok {
 				continue
 			}
 
+			switch v := field.Value.(type) {
+			case *telegraf.HistogramValue:
+				addHistogram(entries, metricName, labels, v, metric.Time())
+				continue
+			case *telegraf.SummaryValue:
+				addSummary(entries, metricName, labels, v, metric.Time())
+				continue
+			}
+
 			switch metric.Type() {
 			case telegraf.Counter:
 				fallthrough
@@ -184,16 +198,7 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 				return nil, fmt.Errorf("unknown type %v", metric.Type())
 			}
 
-			// A batch of metrics can contain multiple values for a single
-			// Prometheus sample.  If this metric is older than the existing
-			// sample then we can skip over it.
-			m, ok := entries[metrickey]
-			if ok {
-				if metric.Time().Before(time.Unix(0, m.Samples[0].Timestamp*1_000_000)) {
-					continue
-				}
-			}
-			entries[metrickey] = promts
+			addEntry(entries, metrickey, promts, metric.Time())
 		}
 	}
 
@@ -238,6 +243,92 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// addEntry adds the series to the entries. A batch of metrics can contain
+// multiple values for a single Prometheus sample. If this metric is older than
+// the existing sample then we can skip over it.
+func addEntry(entries map[MetricKey]prompb.TimeSeries, key MetricKey, promts prompb.TimeSeries, t time.Time) {
+	if m, ok := entries[key]; ok {
+		var timestamp int64
+		if len(m.Histograms) > 0 {
+			timestamp = m.Histograms[0].Timestamp
+		} else {
+			timestamp = m.Samples[0].Timestamp
+		}
+		if t.Before(time.Unix(0, timestamp*1_000_000)) {
+			return
+		}
+	}
+	entries[key] = promts
+}
+
+// addHistogram adds the series of a histogram value. Explicit buckets are
+// sent as classic histogram series while exponential buckets are sent as a
+// native histogram.
+func addHistogram(entries map[MetricKey]prompb.TimeSeries, name string, labels []prompb.Label, v *telegraf.HistogramValue, t time.Time) {
+	if e := v.Exponential; e != nil {
+		h := prompb.Histogram{
+			Count:         &prompb.Histogram_CountInt{CountInt: v.Count},
+			Sum:           v.Sum,
+			Schema:        e.Schema,
+			ZeroThreshold: e.ZeroThreshold,
+			ZeroCount:     &prompb.Histogram_ZeroCountInt{ZeroCountInt: e.ZeroCount},
+			Timestamp:     t.UnixNano() / int64(time.Millisecond),
+		}
+		h.PositiveSpans, h.PositiveDeltas = nativeSpans(e.Positive)
+		h.NegativeSpans, h.NegativeDeltas = nativeSpans(e.Negative)
+
+		key, promts := getPromTS(name, labels, 0, t)
+		promts.Samples = nil
+		promts.Histograms = []prompb.Histogram{h}
+		addEntry(entries, key, promts, t)
+
+		// Only send classic series if there are explicit buckets
+		if len(v.Buckets) == 0 {
+			return
+		}
+	}
+
+	infSeen := false
+	for _, b := range v.Buckets {
+		extraLabel := prompb.Label{Name: "le", Value: fmt.Sprint(b.UpperBound)}
+		if math.IsInf(b.UpperBound, 1) {
+			extraLabel.Value = "+Inf"
+			infSeen = true
+		}
+		key, promts := getPromTS(name+"_bucket", labels, float64(b.Count), t, extraLabel)
+		addEntry(entries, key, promts, t)
+	}
+	if !infSeen {
+		key, promts := getPromTS(name+"_bucket", labels, float64(v.Count), t, prompb.Label{Name: "le", Value: "+Inf"})
+		addEntry(entries, key, promts, t)
+	}
+	key, promts := getPromTS(name+"_sum", labels, v.Sum, t)
+	addEntry(entries, key, promts, t)
+	key, promts = getPromTS(name+"_count", labels, float64(v.Count), t)
+	addEntry(entries, key, promts, t)
+}
+
+// addSummary adds the series of a summary value.
+func addSummary(entries map[MetricKey]prompb.TimeSeries, name string, labels []prompb.Label, v *telegraf.SummaryValue, t time.Time) {
+	for _, q := range v.Quantiles {
+		extraLabel := prompb.Label{Name: "quantile", Value: fmt.Sprint(q.Quantile)}
+		key, promts := getPromTS(name, labels, q.Value, t, extraLabel)
+		addEntry(entries, key, promts, t)
+	}
+	key, promts := getPromTS(name+"_sum", labels, v.Sum, t)
+	addEntry(entries, key, promts, t)
+	key, promts = getPromTS(name+"_count", labels, float64(v.Count), t)
+	addEntry(entries, key, promts, t)
+}
+
+func nativeSpans(r telegraf.BucketRange) ([]*prompb.BucketSpan, []int64) {
+	offset, length, deltas := prometheus.NativeBuckets(r)
+	if length == 0 {
+		return nil, nil
+	}
+	return []*prompb.BucketSpan{{Offset: offset, Length: length}}, deltas
+}
+
 func hasLabel(name string, labels []prompb.Label) bool {
 	for _, label := range labels {
 		if name == label.Name {
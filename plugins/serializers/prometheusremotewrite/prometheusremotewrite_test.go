@@ -663,6 +663,54 @@ cpu_time_user{cpu="cpu3"} 94148
 			expected: []byte(`
 rpc_duration_seconds_count 2693
 rpc_duration_seconds_sum 17560473
+`),
+		},
+		{
+			name: "native histogram value",
+			metrics: []telegraf.Metric{
+				testutil.MustMetric(
+					"prometheus",
+					map[string]string{},
+					map[string]interface{}{
+						"http_request_duration_seconds": &telegraf.HistogramValue{
+							Count:   4,
+							Sum:     20,
+							Buckets: []telegraf.Bucket{{UpperBound: 0.05, Count: 1}, {UpperBound: 0.1, Count: 2}},
+						},
+					},
+					time.Unix(0, 0),
+					telegraf.Histogram,
+				),
+			},
+			expected: []byte(`
+http_request_duration_seconds_count 4
+http_request_duration_seconds_sum 20
+http_request_duration_seconds_bucket{le="+Inf"} 4
+http_request_duration_seconds_bucket{le="0.05"} 1
+http_request_duration_seconds_bucket{le="0.1"} 2
+`),
+		},
+		{
+			name: "native summary value",
+			metrics: []telegraf.Metric{
+				testutil.MustMetric(
+					"rpc",
+					map[string]string{},
+					map[string]interface{}{
+						"duration_seconds": &telegraf.SummaryValue{
+							Count:     2693,
+							Sum:       17560473,
+							Quantiles: []telegraf.Quantile{{Quantile: 0.5, Value: 4773}, {Quantile: 0.9, Value: 9001}},
+						},
+					},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []byte(`
+rpc_duration_seconds_count 2693
+rpc_duration_seconds_sum 17560473
+rpc_duration_seconds{quantile="0.5"} 4773
+rpc_duration_seconds{quantile="0.9"} 9001
 `),
 		},
 		{
@@ -706,6 +754,60 @@ rpc_duration_seconds_sum 17560473
 	}
 }
 
+func TestRemoteWriteSerializeNativeHistogram(t *testing.T) {
+	m := testutil.MustMetric(
+		"prometheus",
+		map[string]string{"handler": "api"},
+		map[string]interface{}{
+			"request_duration_seconds": &telegraf.HistogramValue{
+				Count: 7,
+				Sum:   12.5,
+				Exponential: &telegraf.ExponentialBuckets{
+					Schema:        0,
+					ZeroThreshold: 1e-128,
+					ZeroCount:     1,
+					Positive:      telegraf.BucketRange{Offset: -1, Counts: []uint64{1, 2, 0, 1}},
+					Negative:      telegraf.BucketRange{Offset: 0, Counts: []uint64{2}},
+				},
+			},
+		},
+		time.Unix(1, 0),
+		telegraf.Histogram,
+	)
+
+	s := NewSerializer(FormatConfig{})
+	data, err := s.Serialize(m)
+	require.NoError(t, err)
+	protobuff, err := snappy.Decode(nil, data)
+	require.NoError(t, err)
+	var req prompb.WriteRequest
+	require.NoError(t, req.Unmarshal(protobuff))
+
+	expected := []prompb.TimeSeries{
+		{
+			Labels: []prompb.Label{
+				{Name: "__name__", Value: "request_duration_seconds"},
+				{Name: "handler", Value: "api"},
+			},
+			Histograms: []prompb.Histogram{
+				{
+					Count:          &prompb.Histogram_CountInt{CountInt: 7},
+					Sum:            12.5,
+					Schema:         0,
+					ZeroThreshold:  1e-128,
+					ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+					PositiveSpans:  []*prompb.BucketSpan{{Offset: 0, Length: 4}},
+					PositiveDeltas: []int64{1, 1, -2, 1},
+					NegativeSpans:  []*prompb.BucketSpan{{Offset: 1, Length: 1}},
+					NegativeDeltas: []int64{2},
+					Timestamp:      1000,
+				},
+			},
+		},
+	}
+	require.Equal(t, expected, req.Timeseries)
+}
+
 func prompbToText(data []byte) ([]byte, error) {
 	var buf = bytes.Buffer{}
 	protobuff, err := snappy.Decode(nil, data)
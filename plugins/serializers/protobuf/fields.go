@@ -0,0 +1,124 @@
+package protobuf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/influxdata/telegraf/internal"
+)
+
+const timestampMessage = protoreflect.FullName("google.protobuf.Timestamp")
+
+// fieldPath addresses a field of the message, the leading elements being the
+// enclosing message fields of nested fields.
+type fieldPath []protoreflect.FieldDescriptor
+
+// resolve looks up the dot separated path, e.g. "source.host", in the message.
+func resolve(md protoreflect.MessageDescriptor, path string) (fieldPath, error) {
+	parts := strings.Split(path, ".")
+	fp := make(fieldPath, 0, len(parts))
+	for i, part := range parts {
+		fd := md.Fields().ByName(protoreflect.Name(part))
+		if fd == nil {
+			return nil, fmt.Errorf("message %q has no field %q", md.FullName(), part)
+		}
+		fp = append(fp, fd)
+
+		if i == len(parts)-1 {
+			break
+		}
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return nil, fmt.Errorf("field %q is not a singular message field", fd.FullName())
+		}
+		md = fd.Message()
+	}
+	return fp, nil
+}
+
+func (fp fieldPath) field() protoreflect.FieldDescriptor {
+	return fp[len(fp)-1]
+}
+
+// target returns the message containing the addressed field, creating the
+// enclosing messages if necessary.
+func (fp fieldPath) target(msg protoreflect.Message) protoreflect.Message {
+	for _, fd := range fp[:len(fp)-1] {
+		msg = msg.Mutable(fd).Message()
+	}
+	return msg
+}
+
+func (fp fieldPath) set(msg protoreflect.Message, value interface{}) error {
+	fd := fp.field()
+	v, err := convert(fd, value)
+	if err != nil {
+		return err
+	}
+	fp.target(msg).Set(fd, v)
+	return nil
+}
+
+func isScalar(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsList() || fd.IsMap() {
+		return false
+	}
+	return fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind
+}
+
+// convert returns the value converted to the kind of the given field.
+func convert(fd protoreflect.FieldDescriptor, value interface{}) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		v, err := internal.ToBool(value)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := internal.ToInt64(value)
+		if err == nil && (v < math.MinInt32 || v > math.MaxInt32) {
+			err = fmt.Errorf("value %d out of range for %s", v, fd.Kind())
+		}
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := internal.ToInt64(value)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := internal.ToUint64(value)
+		if err == nil && v > math.MaxUint32 {
+			err = fmt.Errorf("value %d out of range for %s", v, fd.Kind())
+		}
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := internal.ToUint64(value)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := internal.ToFloat64(value)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := internal.ToFloat64(value)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.StringKind:
+		v, err := internal.ToString(value)
+		return protoreflect.ValueOfString(v), err
+	case protoreflect.BytesKind:
+		v, err := internal.ToString(value)
+		return protoreflect.ValueOfBytes([]byte(v)), err
+	case protoreflect.EnumKind:
+		// Enums are set by the name of the value or by its number
+		if name, ok := value.(string); ok {
+			if ev := fd.Enum().Values().ByName(protoreflect.Name(name)); ev != nil {
+				return protoreflect.ValueOfEnum(ev.Number()), nil
+			}
+		}
+		v, err := internal.ToInt64(value)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid value %v for enum %q", value, fd.Enum().FullName())
+		}
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return protoreflect.Value{}, fmt.Errorf("value %d out of range for enum %q", v, fd.Enum().FullName())
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+}
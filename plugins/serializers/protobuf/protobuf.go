@@ -0,0 +1,330 @@
+package protobuf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+type Serializer struct {
+	MessageDefinition string            `toml:"protobuf_file"`
+	MessageType       string            `toml:"protobuf_type"`
+	ImportPaths       []string          `toml:"protobuf_import_paths"`
+	NameField         string            `toml:"protobuf_name_field"`
+	TimestampField    string            `toml:"protobuf_timestamp_field"`
+	TimestampFormat   string            `toml:"protobuf_timestamp_format"`
+	TagsField         string            `toml:"protobuf_tags_field"`
+	FieldsField       string            `toml:"protobuf_fields_field"`
+	Tags              map[string]string `toml:"protobuf_tags"`
+	Fields            map[string]string `toml:"protobuf_fields"`
+	LengthDelimited   bool              `toml:"protobuf_length_delimited"`
+
+	descriptor  protoreflect.MessageDescriptor
+	name        fieldPath
+	timestamp   fieldPath
+	tagsField   fieldPath
+	fieldsField fieldPath
+	tags        map[string]fieldPath
+	fields      map[string]fieldPath
+
+	// Top-level fields set by the configuration and therefore not available
+	// for matching tags and fields by name
+	reserved map[protoreflect.Name]bool
+}
+
+func (s *Serializer) Init() error {
+	// Check the message definition and type
+	if s.MessageDefinition == "" {
+		return errors.New("'protobuf_file' not set")
+	}
+	if s.MessageType == "" {
+		return errors.New("'protobuf_type' not set")
+	}
+
+	// Load the file descriptors from the given protocol-buffer definition.
+	// Inferring the import paths is not possible as it fails for definitions
+	// importing well-known types like google.protobuf.Timestamp.
+	parser := protoparse.Parser{ImportPaths: s.ImportPaths}
+	fds, err := parser.ParseFiles(s.MessageDefinition)
+	if err != nil {
+		return fmt.Errorf("parsing protocol-buffer definition in %q failed: %w", s.MessageDefinition, err)
+	}
+	if len(fds) < 1 {
+		return fmt.Errorf("file %q does not contain file descriptors", s.MessageDefinition)
+	}
+	registry, err := protodesc.NewFiles(desc.ToFileDescriptorSet(fds...))
+	if err != nil {
+		return fmt.Errorf("constructing registry failed: %w", err)
+	}
+
+	// Lookup given type in the loaded file descriptors
+	msgFullName := protoreflect.FullName(s.MessageType)
+	descriptor, err := registry.FindDescriptorByName(msgFullName)
+	if err != nil {
+		return fmt.Errorf("looking up message type %q failed: %w", msgFullName, err)
+	}
+	msgDesc, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return fmt.Errorf("%q is not a message descriptor (%T)", msgFullName, descriptor)
+	}
+	s.descriptor = msgDesc
+	s.reserved = make(map[protoreflect.Name]bool)
+
+	// Resolve the configured message fields
+	if s.NameField != "" {
+		if s.name, err = s.resolveScalar(s.NameField); err != nil {
+			return fmt.Errorf("invalid 'protobuf_name_field': %w", err)
+		}
+	}
+	if s.TimestampField != "" {
+		if err := s.resolveTimestamp(); err != nil {
+			return fmt.Errorf("invalid 'protobuf_timestamp_field': %w", err)
+		}
+	}
+	if s.TagsField != "" {
+		if s.tagsField, err = s.resolveMap(s.TagsField); err != nil {
+			return fmt.Errorf("invalid 'protobuf_tags_field': %w", err)
+		}
+	}
+	if s.FieldsField != "" {
+		if s.fieldsField, err = s.resolveMap(s.FieldsField); err != nil {
+			return fmt.Errorf("invalid 'protobuf_fields_field': %w", err)
+		}
+	}
+
+	s.tags = make(map[string]fieldPath, len(s.Tags))
+	for key, path := range s.Tags {
+		if s.tags[key], err = s.resolveScalar(path); err != nil {
+			return fmt.Errorf("invalid mapping for tag %q: %w", key, err)
+		}
+	}
+	s.fields = make(map[string]fieldPath, len(s.Fields))
+	for key, path := range s.Fields {
+		if s.fields[key], err = s.resolveScalar(path); err != nil {
+			return fmt.Errorf("invalid mapping for field %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Serializer) resolveScalar(path string) (fieldPath, error) {
+	fp, err := s.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	if fd := fp.field(); !isScalar(fd) {
+		return nil, fmt.Errorf("field %q is not a singular scalar field", fd.FullName())
+	}
+	return fp, nil
+}
+
+func (s *Serializer) resolveMap(path string) (fieldPath, error) {
+	fp, err := s.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	fd := fp.field()
+	if !fd.IsMap() || fd.MapKey().Kind() != protoreflect.StringKind {
+		return nil, fmt.Errorf("field %q is not a map with string keys", fd.FullName())
+	}
+	if kind := fd.MapValue().Kind(); kind == protoreflect.MessageKind || kind == protoreflect.GroupKind {
+		return nil, fmt.Errorf("field %q is not a map of scalar values", fd.FullName())
+	}
+	return fp, nil
+}
+
+func (s *Serializer) resolveTimestamp() error {
+	fp, err := s.resolve(s.TimestampField)
+	if err != nil {
+		return err
+	}
+	fd := fp.field()
+	if fd.IsList() || fd.IsMap() {
+		return fmt.Errorf("field %q is not a singular field", fd.FullName())
+	}
+
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		if fd.Message().FullName() != timestampMessage {
+			return fmt.Errorf("field %q is not of type %s", fd.FullName(), timestampMessage)
+		}
+	case protoreflect.StringKind:
+		switch s.TimestampFormat {
+		case "":
+			s.TimestampFormat = time.RFC3339Nano
+		case "unix", "unix_ms", "unix_us", "unix_ns":
+		default:
+			if time.Now().Format(s.TimestampFormat) == s.TimestampFormat {
+				return fmt.Errorf("invalid timestamp format %q", s.TimestampFormat)
+			}
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind,
+		protoreflect.FloatKind, protoreflect.DoubleKind:
+		switch s.TimestampFormat {
+		case "":
+			s.TimestampFormat = "unix_ns"
+		case "unix", "unix_ms", "unix_us", "unix_ns":
+		default:
+			return fmt.Errorf("invalid timestamp format %q for numeric field %q", s.TimestampFormat, fd.FullName())
+		}
+	default:
+		return fmt.Errorf("field %q of kind %s cannot hold a timestamp", fd.FullName(), fd.Kind())
+	}
+	s.timestamp = fp
+
+	return nil
+}
+
+func (s *Serializer) resolve(path string) (fieldPath, error) {
+	fp, err := resolve(s.descriptor, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(fp) == 1 {
+		s.reserved[fp[0].Name()] = true
+	}
+	return fp, nil
+}
+
+func (s *Serializer) Serialize(m telegraf.Metric) ([]byte, error) {
+	msg, err := s.message(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.LengthDelimited {
+		return proto.Marshal(msg)
+	}
+	var buf bytes.Buffer
+	if _, err := protodelim.MarshalTo(&buf, msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
+	// Concatenated messages without framing are merged into one when decoding
+	if !s.LengthDelimited && len(metrics) > 1 {
+		return nil, errors.New("serializing multiple metrics requires 'protobuf_length_delimited'")
+	}
+
+	var buf []byte
+	for _, m := range metrics {
+		serialized, err := s.Serialize(m)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, serialized...)
+	}
+	return buf, nil
+}
+
+func (s *Serializer) message(m telegraf.Metric) (*dynamicpb.Message, error) {
+	msg := dynamicpb.NewMessage(s.descriptor)
+
+	if s.name != nil {
+		if err := s.name.set(msg, m.Name()); err != nil {
+			return nil, fmt.Errorf("setting name failed: %w", err)
+		}
+	}
+	if s.timestamp != nil {
+		if err := s.setTimestamp(msg, m.Time()); err != nil {
+			return nil, fmt.Errorf("setting timestamp failed: %w", err)
+		}
+	}
+
+	for _, tag := range m.TagList() {
+		if fp, found := s.tags[tag.Key]; found {
+			if err := fp.set(msg, tag.Value); err != nil {
+				return nil, fmt.Errorf("setting tag %q failed: %w", tag.Key, err)
+			}
+			continue
+		}
+		s.setUnmapped(msg, s.tagsField, tag.Key, tag.Value)
+	}
+
+	for _, field := range m.FieldList() {
+		if fp, found := s.fields[field.Key]; found {
+			if err := fp.set(msg, field.Value); err != nil {
+				return nil, fmt.Errorf("setting field %q failed: %w", field.Key, err)
+			}
+			continue
+		}
+		s.setUnmapped(msg, s.fieldsField, field.Key, field.Value)
+	}
+
+	return msg, nil
+}
+
+// setUnmapped sets the top-level field with the same name as the given key
+// or, if no such field exists, adds the value to the given map field. Values
+// that cannot be converted to the type of the field are skipped.
+func (s *Serializer) setUnmapped(msg protoreflect.Message, fp fieldPath, key string, value interface{}) {
+	name := protoreflect.Name(key)
+	if fd := s.descriptor.Fields().ByName(name); fd != nil && !s.reserved[name] && isScalar(fd) {
+		if v, err := convert(fd, value); err == nil {
+			msg.Set(fd, v)
+			return
+		}
+	}
+
+	if fp == nil {
+		return
+	}
+	fd := fp.field()
+	v, err := convert(fd.MapValue(), value)
+	if err != nil {
+		return
+	}
+	fp.target(msg).Mutable(fd).Map().Set(protoreflect.ValueOfString(key).MapKey(), v)
+}
+
+func (s *Serializer) setTimestamp(msg protoreflect.Message, t time.Time) error {
+	fd := s.timestamp.field()
+	if fd.Kind() == protoreflect.MessageKind {
+		ts := s.timestamp.target(msg).Mutable(fd).Message()
+		fields := fd.Message().Fields()
+		ts.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(t.Unix()))
+		ts.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+		return nil
+	}
+
+	var value interface{}
+	switch s.TimestampFormat {
+	case "unix":
+		value = t.Unix()
+	case "unix_ms":
+		value = t.UnixNano() / 1_000_000
+	case "unix_us":
+		value = t.UnixNano() / 1_000
+	case "unix_ns":
+		value = t.UnixNano()
+	default:
+		value = t.UTC().Format(s.TimestampFormat)
+	}
+	return s.timestamp.set(msg, value)
+}
+
+func init() {
+	serializers.Add("protobuf",
+		func() serializers.Serializer {
+			return &Serializer{}
+		},
+	)
+}
@@ -0,0 +1,247 @@
+package protobuf
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestSerialize(t *testing.T) {
+	s := &Serializer{
+		MessageDefinition: "testdata/metric.proto",
+		MessageType:       "telegraf.test.Metric",
+		NameField:         "name",
+		TimestampField:    "time",
+		TagsField:         "tags",
+		FieldsField:       "fields",
+		Tags:              map[string]string{"host": "source.hostname"},
+		Fields:            map[string]string{"usage_idle": "usage"},
+	}
+	require.NoError(t, s.Init())
+
+	m := testutil.MustMetric(
+		"cpu",
+		map[string]string{"host": "a", "region": "eu", "cpu": "cpu0"},
+		map[string]interface{}{
+			"usage_idle":   42.5,
+			"cores":        int64(4),
+			"state":        "FAILED",
+			"usage_system": int64(10),
+			"comment":      "not a number",
+		},
+		time.Unix(1, 500),
+	)
+	buf, err := s.Serialize(m)
+	require.NoError(t, err)
+
+	msg := dynamicpb.NewMessage(s.descriptor)
+	require.NoError(t, proto.Unmarshal(buf, msg))
+
+	require.Equal(t, "cpu", get(t, msg, "name").String())
+	require.Equal(t, int64(1), get(t, msg, "time.seconds").Int())
+	require.Equal(t, int64(500), get(t, msg, "time.nanos").Int())
+	require.Equal(t, "a", get(t, msg, "source.hostname").String())
+	require.Empty(t, get(t, msg, "source.region").String())
+	require.Equal(t, 42.5, get(t, msg, "usage").Float())
+	require.Equal(t, int64(4), get(t, msg, "cores").Int())
+	require.Equal(t, protoreflect.EnumNumber(2), get(t, msg, "state").Enum())
+
+	tags := get(t, msg, "tags").Map()
+	require.Equal(t, 2, tags.Len())
+	require.Equal(t, "eu", tags.Get(protoreflect.ValueOfString("region").MapKey()).String())
+	require.Equal(t, "cpu0", tags.Get(protoreflect.ValueOfString("cpu").MapKey()).String())
+
+	// String fields not convertible to double are skipped
+	fields := get(t, msg, "fields").Map()
+	require.Equal(t, 1, fields.Len())
+	require.Equal(t, 10.0, fields.Get(protoreflect.ValueOfString("usage_system").MapKey()).Float())
+}
+
+func TestSerializeTimestampFormat(t *testing.T) {
+	s := &Serializer{
+		MessageDefinition: "testdata/metric.proto",
+		MessageType:       "telegraf.test.Metric",
+		TimestampField:    "timestamp_ms",
+		TimestampFormat:   "unix_ms",
+	}
+	require.NoError(t, s.Init())
+
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"usage": 1.0}, time.Unix(12, 345000000))
+	buf, err := s.Serialize(m)
+	require.NoError(t, err)
+
+	msg := dynamicpb.NewMessage(s.descriptor)
+	require.NoError(t, proto.Unmarshal(buf, msg))
+	require.Equal(t, int64(12345), get(t, msg, "timestamp_ms").Int())
+	require.Equal(t, 1.0, get(t, msg, "usage").Float())
+	require.Empty(t, get(t, msg, "name").String())
+}
+
+func TestSerializeMappingError(t *testing.T) {
+	s := &Serializer{
+		MessageDefinition: "testdata/metric.proto",
+		MessageType:       "telegraf.test.Metric",
+		Fields:            map[string]string{"value": "cores"},
+	}
+	require.NoError(t, s.Init())
+
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": "four"}, time.Unix(0, 0))
+	_, err := s.Serialize(m)
+	require.ErrorContains(t, err, `setting field "value" failed`)
+}
+
+func TestSerializeBatchLengthDelimited(t *testing.T) {
+	s := &Serializer{
+		MessageDefinition: "testdata/metric.proto",
+		MessageType:       "telegraf.test.Metric",
+		NameField:         "name",
+		LengthDelimited:   true,
+	}
+	require.NoError(t, s.Init())
+
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"cores": int64(4)}, time.Unix(0, 0)),
+		testutil.MustMetric("mem", map[string]string{}, map[string]interface{}{"cores": int64(8)}, time.Unix(0, 0)),
+		testutil.MustMetric("disk", map[string]string{}, map[string]interface{}{"cores": int64(0)}, time.Unix(0, 0)),
+	}
+	buf, err := s.SerializeBatch(metrics)
+	require.NoError(t, err)
+
+	var names []string
+	var cores []int64
+	r := bufio.NewReader(bytes.NewReader(buf))
+	for {
+		msg := dynamicpb.NewMessage(s.descriptor)
+		err := protodelim.UnmarshalFrom(r, msg)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, get(t, msg, "name").String())
+		cores = append(cores, get(t, msg, "cores").Int())
+	}
+	require.Equal(t, []string{"cpu", "mem", "disk"}, names)
+	require.Equal(t, []int64{4, 8, 0}, cores)
+}
+
+func TestSerializeBatchRequiresFraming(t *testing.T) {
+	s := &Serializer{
+		MessageDefinition: "testdata/metric.proto",
+		MessageType:       "telegraf.test.Metric",
+	}
+	require.NoError(t, s.Init())
+
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"cores": int64(4)}, time.Unix(0, 0))
+	buf, err := s.SerializeBatch([]telegraf.Metric{m})
+	require.NoError(t, err)
+	require.NotEmpty(t, buf)
+
+	_, err = s.SerializeBatch([]telegraf.Metric{m, m})
+	require.EqualError(t, err, "serializing multiple metrics requires 'protobuf_length_delimited'")
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Serializer
+		expected string
+	}{
+		{
+			name:     "missing type",
+			plugin:   &Serializer{MessageDefinition: "testdata/metric.proto"},
+			expected: "'protobuf_type' not set",
+		},
+		{
+			name: "unknown type",
+			plugin: &Serializer{
+				MessageDefinition: "testdata/metric.proto",
+				MessageType:       "telegraf.test.Unknown",
+			},
+			expected: `looking up message type "telegraf.test.Unknown" failed`,
+		},
+		{
+			name: "unknown field",
+			plugin: &Serializer{
+				MessageDefinition: "testdata/metric.proto",
+				MessageType:       "telegraf.test.Metric",
+				Tags:              map[string]string{"host": "source.host"},
+			},
+			expected: `invalid mapping for tag "host": message "telegraf.test.Source" has no field "host"`,
+		},
+		{
+			name: "path through scalar",
+			plugin: &Serializer{
+				MessageDefinition: "testdata/metric.proto",
+				MessageType:       "telegraf.test.Metric",
+				Fields:            map[string]string{"value": "usage.value"},
+			},
+			expected: `field "telegraf.test.Metric.usage" is not a singular message field`,
+		},
+		{
+			name: "message as scalar",
+			plugin: &Serializer{
+				MessageDefinition: "testdata/metric.proto",
+				MessageType:       "telegraf.test.Metric",
+				NameField:         "source",
+			},
+			expected: `field "telegraf.test.Metric.source" is not a singular scalar field`,
+		},
+		{
+			name: "tags field not a map",
+			plugin: &Serializer{
+				MessageDefinition: "testdata/metric.proto",
+				MessageType:       "telegraf.test.Metric",
+				TagsField:         "name",
+			},
+			expected: `field "telegraf.test.Metric.name" is not a map with string keys`,
+		},
+		{
+			name: "layout for numeric timestamp",
+			plugin: &Serializer{
+				MessageDefinition: "testdata/metric.proto",
+				MessageType:       "telegraf.test.Metric",
+				TimestampField:    "timestamp_ms",
+				TimestampFormat:   time.RFC3339,
+			},
+			expected: "invalid timestamp format",
+		},
+		{
+			name: "invalid timestamp message",
+			plugin: &Serializer{
+				MessageDefinition: "testdata/metric.proto",
+				MessageType:       "telegraf.test.Metric",
+				TimestampField:    "source",
+			},
+			expected: `field "telegraf.test.Metric.source" is not of type google.protobuf.Timestamp`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.plugin.Init(), tt.expected)
+		})
+	}
+}
+
+func get(t *testing.T, msg protoreflect.Message, path string) protoreflect.Value {
+	t.Helper()
+
+	fp, err := resolve(msg.Descriptor(), path)
+	require.NoError(t, err)
+	for _, fd := range fp[:len(fp)-1] {
+		msg = msg.Get(fd).Message()
+	}
+	return msg.Get(fp.field())
+}
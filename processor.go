@@ -39,3 +39,17 @@ type StreamingProcessor interface {
 	// accumulator.
 	Stop()
 }
+
+// BatchProcessor is an optional interface for processors that can handle
+// multiple metrics at once, e.g. to reduce per-call overhead or to perform
+// lookups for all metrics of a batch. If a StreamingProcessor implements this
+// interface, AddBatch is called instead of Add with batches bounded by the
+// configured batch size and timeout.
+type BatchProcessor interface {
+	// AddBatch is called for each batch of metrics to be processed. The same
+	// rules as for StreamingProcessor.Add() apply to every metric of the
+	// batch, i.e. metrics you don't want to pass downstream should have
+	// metric.Drop() called.
+	// The batch slice must not be used after AddBatch() returns.
+	AddBatch(metrics []Metric, acc Accumulator) error
+}
@@ -18,6 +18,22 @@ type SecretStore interface {
 	GetResolver(key string) (ResolveFunc, error)
 }
 
+// RotatableSecretStore is an interface secret-stores can implement if static
+// secrets might be changed externally, e.g. when credentials are rotated.
+// Such secrets are resolved once but resolved again if a plugin using them
+// fails to authenticate.
+type RotatableSecretStore interface {
+	SecretStore
+
+	// Rotatable returns true if the secret of the given key might change
+	// during runtime.
+	Rotatable(key string) bool
+
+	// Refresh discards cached data of the given key so that resolving the
+	// key afterwards returns the current secret.
+	Refresh(key string) error
+}
+
 // ResolveFunc is a function to resolve the secret.
 // The returned flag indicates if the resolver is static (false), i.e.
 // the secret will not change over time, or dynamic (true) to handle
@@ -77,6 +77,27 @@ func RegisterTiming(measurement, field string, tags map[string]string) Stat {
 
 // Metrics returns all registered stats as telegraf metrics.
 func Metrics() []telegraf.Metric {
+	return metrics(Stat.Get)
+}
+
+// Snapshot returns all registered stats as telegraf metrics like Metrics but
+// without clearing the averages of timing stats. Use it for reading the stats
+// in addition to the internal input, e.g. for serving them on an endpoint.
+func Snapshot() []telegraf.Metric {
+	return metrics(func(s Stat) int64 {
+		if p, ok := s.(peeker); ok {
+			return p.Peek()
+		}
+		return s.Get()
+	})
+}
+
+// peeker is implemented by stats modifying their state when calling Get()
+type peeker interface {
+	Peek() int64
+}
+
+func metrics(get func(Stat) int64) []telegraf.Metric {
 	registry.mu.Lock()
 	now := time.Now()
 	metrics := make([]telegraf.Metric, 0, len(registry.stats))
@@ -91,7 +112,7 @@ func Metrics() []telegraf.Metric {
 					tags = stat.Tags()
 					name = stat.Name()
 				}
-				fields[fieldname] = stat.Get()
+				fields[fieldname] = get(stat)
 				j++
 			}
 			m := metric.New(name, tags, fields, now)
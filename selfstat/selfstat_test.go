@@ -211,3 +211,29 @@ func TestRegisterCopy(t *testing.T) {
 	tags["new"] = "value"
 	require.NotEqual(t, tags, stat.Tags())
 }
+
+func TestSnapshotKeepsTimings(t *testing.T) {
+	testLock.Lock()
+	defer testCleanup()
+	s := RegisterTiming("test", "test_field_ns", map[string]string{"test": "foo"})
+	s.Incr(10)
+	s.Incr(20)
+
+	// Taking snapshots does not clear the average
+	for i := 0; i < 2; i++ {
+		var found bool
+		for _, m := range Snapshot() {
+			if m.Name() != "internal_test" {
+				continue
+			}
+			found = true
+			v, ok := m.GetField("test_field_ns")
+			require.True(t, ok)
+			require.Equal(t, int64(15), v)
+		}
+		require.True(t, found)
+	}
+
+	s.Incr(30)
+	require.Equal(t, int64(20), s.Get())
+}